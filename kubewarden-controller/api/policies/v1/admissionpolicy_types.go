@@ -34,7 +34,7 @@ type AdmissionPolicySpec struct {
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Policy Server",type=string,JSONPath=`.spec.policyServer`,description="Bound to Policy Server"
 // +kubebuilder:printcolumn:name="Mutating",type=boolean,JSONPath=`.spec.mutating`,description="Whether the policy is mutating"
-// +kubebuilder:printcolumn:name="BackgroundAudit",type=boolean,JSONPath=`.spec.backgroundAudit`,description="Whether the policy is used in audit checks"
+// +kubebuilder:printcolumn:name="BackgroundAudit",type=boolean,JSONPath=`.status.backgroundAudit`,description="Whether the policy is used in audit checks"
 // +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`,description="Policy deployment mode"
 // +kubebuilder:printcolumn:name="Observed mode",type=string,JSONPath=`.status.mode`,description="Policy deployment mode observed on the assigned Policy Server"
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.policyStatus`,description="Status of the policy"
@@ -78,6 +78,10 @@ func (r *AdmissionPolicy) GetModule() string {
 	return r.Spec.Module
 }
 
+func (r *AdmissionPolicy) GetModules() []string {
+	return []string{r.Spec.Module}
+}
+
 func (r *AdmissionPolicy) IsMutating() bool {
 	return r.Spec.Mutating
 }
@@ -122,6 +126,10 @@ func (r *AdmissionPolicy) GetMatchPolicy() *admissionregistrationv1.MatchPolicyT
 	return r.Spec.MatchPolicy
 }
 
+func (r *AdmissionPolicy) GetReinvocationPolicy() *admissionregistrationv1.ReinvocationPolicyType {
+	return r.Spec.ReinvocationPolicy
+}
+
 func (r *AdmissionPolicy) GetMatchConditions() []admissionregistrationv1.MatchCondition {
 	return r.Spec.MatchConditions
 }
@@ -141,6 +149,10 @@ func (r *AdmissionPolicy) GetTimeoutSeconds() *int32 {
 	return r.Spec.TimeoutSeconds
 }
 
+func (r *AdmissionPolicy) GetResourceHints() *PolicyResourceHints {
+	return r.Spec.ResourceHints
+}
+
 func (r *AdmissionPolicy) GetObjectMeta() *metav1.ObjectMeta {
 	return &r.ObjectMeta
 }
@@ -157,7 +169,7 @@ func (r *AdmissionPolicy) GetContextAwareResources() []ContextAwareResource {
 	return []ContextAwareResource{}
 }
 
-func (r *AdmissionPolicy) GetBackgroundAudit() bool {
+func (r *AdmissionPolicy) GetBackgroundAudit() *bool {
 	return r.Spec.BackgroundAudit
 }
 
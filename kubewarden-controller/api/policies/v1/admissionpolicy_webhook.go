@@ -19,31 +19,46 @@ import (
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/go-logr/logr"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
+	"github.com/kubewarden/kubewarden-controller/internal/tracing"
 )
 
 // SetupWebhookWithManager registers the AdmissionPolicy webhook with the controller manager.
-func (r *AdmissionPolicy) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// When rejectClusterScopedResources is true, AdmissionPolicies targeting
+// cluster-scoped resources are rejected instead of being accepted with a warning.
+func (r *AdmissionPolicy) SetupWebhookWithManager(mgr ctrl.Manager, rejectClusterScopedResources bool) error {
 	logger := mgr.GetLogger().WithName("admissionpolicy-webhook")
+	validator := &admissionPolicyValidator{
+		k8sClient:                    mgr.GetClient(),
+		logger:                       logger,
+		rejectClusterScopedResources: rejectClusterScopedResources,
+	}
 
 	err := ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		WithDefaulter(&admissionPolicyDefaulter{
 			logger: logger,
 		}).
-		WithValidator(&admissionPolicyValidator{
-			logger: logger,
-		}).
+		WithValidator(validator).
 		Complete()
 	if err != nil {
 		return fmt.Errorf("failed enrolling webhook with manager: %w", err)
 	}
+
+	// The deletions only get warnings, they are validated by a separate
+	// webhook ignoring its failures, so that the policies can be deleted
+	// while the controller is not available.
+	mgr.GetWebhookServer().Register("/validate-delete-policies-kubewarden-io-v1-admissionpolicy",
+		admission.WithCustomValidator(mgr.GetScheme(), r, validator))
+
 	return nil
 }
 
@@ -57,12 +72,14 @@ type admissionPolicyDefaulter struct {
 var _ webhook.CustomDefaulter = &admissionPolicyDefaulter{}
 
 // Default implements webhook.CustomDefaulter so a webhook will be registered for the type.
-func (d *admissionPolicyDefaulter) Default(_ context.Context, obj runtime.Object) error {
+func (d *admissionPolicyDefaulter) Default(ctx context.Context, obj runtime.Object) error {
 	admissionPolicy, ok := obj.(*AdmissionPolicy)
 	if !ok {
 		return fmt.Errorf("expected an AdmissionPolicy object, got %T", obj)
 	}
 
+	tracing.RecordAdmission(ctx, admissionPolicy)
+
 	if admissionPolicy.Spec.PolicyServer == "" {
 		admissionPolicy.Spec.PolicyServer = constants.DefaultPolicyServer
 	}
@@ -74,16 +91,19 @@ func (d *admissionPolicyDefaulter) Default(_ context.Context, obj runtime.Object
 }
 
 //+kubebuilder:webhook:path=/validate-policies-kubewarden-io-v1-admissionpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=policies.kubewarden.io,resources=admissionpolicies,verbs=create;update,versions=v1,name=vadmissionpolicy.kb.io,admissionReviewVersions={v1,v1beta1}
+//+kubebuilder:webhook:path=/validate-delete-policies-kubewarden-io-v1-admissionpolicy,mutating=false,failurePolicy=ignore,sideEffects=None,groups=policies.kubewarden.io,resources=admissionpolicies,verbs=delete,versions=v1,name=vdeleteadmissionpolicy.kb.io,admissionReviewVersions={v1,v1beta1}
 
 // admissionPolicyValidator validates AdmissionPolicy objects when they are created, updated, or deleted.
 type admissionPolicyValidator struct {
-	logger logr.Logger
+	k8sClient                    client.Client
+	logger                       logr.Logger
+	rejectClusterScopedResources bool
 }
 
 var _ webhook.CustomValidator = &admissionPolicyValidator{}
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
-func (v *admissionPolicyValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *admissionPolicyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	admissionPolicy, ok := obj.(*AdmissionPolicy)
 	if !ok {
 		return nil, fmt.Errorf("expected an AdmissionPolicy object, got %T", obj)
@@ -92,15 +112,19 @@ func (v *admissionPolicyValidator) ValidateCreate(_ context.Context, obj runtime
 	v.logger.Info("Validating AdmissionPolicy creation", "name", admissionPolicy.GetName())
 
 	allErrors := validatePolicyCreate(admissionPolicy)
+	warnings, clusterScopedErrors := v.validateClusterScopedResources(admissionPolicy)
+	allErrors = append(allErrors, clusterScopedErrors...)
+	warnings = append(warnings, matchConditionsWarnings(admissionPolicy)...)
 	if len(allErrors) != 0 {
-		return nil, prepareInvalidAPIError(admissionPolicy, allErrors)
+		recordValidationRejection(ctx, "AdmissionPolicy", allErrors)
+		return warnings, prepareInvalidAPIError(admissionPolicy, allErrors)
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
-func (v *admissionPolicyValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+func (v *admissionPolicyValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	oldAdmissionPolicy, ok := oldObj.(*AdmissionPolicy)
 	if !ok {
 		return nil, fmt.Errorf("expected an AdmissionPolicy object, got %T", oldObj)
@@ -113,15 +137,36 @@ func (v *admissionPolicyValidator) ValidateUpdate(_ context.Context, oldObj, new
 	v.logger.Info("Validating ClusterAdmissionPolicy update", "name", newAdmissionPolicy.GetName())
 
 	allErrors := validatePolicyUpdate(oldAdmissionPolicy, newAdmissionPolicy)
+	warnings, clusterScopedErrors := v.validateClusterScopedResources(newAdmissionPolicy)
+	allErrors = append(allErrors, clusterScopedErrors...)
+	warnings = append(warnings, matchConditionsWarnings(newAdmissionPolicy)...)
 	if len(allErrors) != 0 {
-		return nil, prepareInvalidAPIError(newAdmissionPolicy, allErrors)
+		recordValidationRejection(ctx, "AdmissionPolicy", allErrors)
+		return warnings, prepareInvalidAPIError(newAdmissionPolicy, allErrors)
+	}
+
+	return warnings, nil
+}
+
+// validateClusterScopedResources checks if the AdmissionPolicy targets
+// cluster-scoped resources. Depending on the validator configuration, the
+// findings are returned as warnings or as validation errors.
+func (v *admissionPolicyValidator) validateClusterScopedResources(admissionPolicy *AdmissionPolicy) (admission.Warnings, field.ErrorList) {
+	clusterScopedErrors := validateClusterScopedResourcesTargets(admissionPolicy)
+	if v.rejectClusterScopedResources {
+		return nil, clusterScopedErrors
+	}
+
+	var warnings admission.Warnings
+	for _, err := range clusterScopedErrors {
+		warnings = append(warnings, err.Error())
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
-func (v *admissionPolicyValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *admissionPolicyValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	admissionPolicy, ok := obj.(*AdmissionPolicy)
 	if !ok {
 		return nil, fmt.Errorf("expected an AdmissionPolicy object, got %T", obj)
@@ -129,5 +174,12 @@ func (v *admissionPolicyValidator) ValidateDelete(_ context.Context, obj runtime
 
 	v.logger.Info("Validating AdmissionPolicy delete", "name", admissionPolicy.GetName())
 
-	return nil, nil
+	// The lookup of the policy groups only produces warnings, failing it must
+	// not prevent the deletion of the policy.
+	warnings, err := policyGroupsSharingModuleWarnings(ctx, v.k8sClient, admissionPolicy)
+	if err != nil {
+		v.logger.Error(err, "Cannot look up the policy groups running the module of the AdmissionPolicy", "name", admissionPolicy.GetName())
+	}
+
+	return warnings, nil
 }
@@ -212,7 +212,7 @@ func TestAdmissionPolicyValidateUpdateWithInvalidType(t *testing.T) {
 }
 
 func TestAdmissionPolicyValidateDelete(t *testing.T) {
-	validator := admissionPolicyValidator{logger: logr.Discard()}
+	validator := admissionPolicyValidator{k8sClient: newPolicyGroupsFakeClient(t), logger: logr.Discard()}
 	policy := NewAdmissionPolicyFactory().Build()
 
 	warnings, err := validator.ValidateDelete(t.Context(), policy)
@@ -220,6 +220,16 @@ func TestAdmissionPolicyValidateDelete(t *testing.T) {
 	assert.Empty(t, warnings)
 }
 
+func TestAdmissionPolicyValidateDeleteWithPolicyGroupSharingModule(t *testing.T) {
+	k8sClient := newPolicyGroupsFakeClient(t, NewClusterAdmissionPolicyGroupFactory().WithName("group").Build())
+	validator := admissionPolicyValidator{k8sClient: k8sClient, logger: logr.Discard()}
+	policy := NewAdmissionPolicyFactory().Build()
+
+	warnings, err := validator.ValidateDelete(t.Context(), policy)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`ClusterAdmissionPolicyGroup group runs the same module in its member "pod_privileged", the policy group is not affected by the deletion`}, []string(warnings))
+}
+
 func TestAdmissionPolicyValidateDeleteWithInvalidType(t *testing.T) {
 	validator := admissionPolicyValidator{logger: logr.Discard()}
 	obj := &corev1.Pod{}
@@ -228,3 +238,104 @@ func TestAdmissionPolicyValidateDeleteWithInvalidType(t *testing.T) {
 	require.ErrorContains(t, err, "expected an AdmissionPolicy object, got *v1.Pod")
 	assert.Empty(t, warnings)
 }
+
+func TestAdmissionPolicyValidateClusterScopedResources(t *testing.T) {
+	tests := []struct {
+		name                         string
+		rules                        []admissionregistrationv1.RuleWithOperations
+		rejectClusterScopedResources bool
+		expectedWarnings             int
+		expectedError                string
+	}{
+		{
+			name: "namespaced resources",
+			rules: []admissionregistrationv1.RuleWithOperations{
+				{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{""},
+						APIVersions: []string{"v1"},
+						Resources:   []string{"pods", "configmaps"},
+					},
+				},
+			},
+			rejectClusterScopedResources: true,
+			expectedWarnings:             0,
+			expectedError:                "",
+		},
+		{
+			name: "cluster-scoped resources with warnings",
+			rules: []admissionregistrationv1.RuleWithOperations{
+				{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{""},
+						APIVersions: []string{"v1"},
+						Resources:   []string{"pods", "nodes", "persistentvolumes/status"},
+					},
+				},
+			},
+			rejectClusterScopedResources: false,
+			expectedWarnings:             2,
+			expectedError:                "",
+		},
+		{
+			name: "cluster-scoped resources with rejection",
+			rules: []admissionregistrationv1.RuleWithOperations{
+				{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"rbac.authorization.k8s.io"},
+						APIVersions: []string{"v1"},
+						Resources:   []string{"clusterroles"},
+					},
+				},
+			},
+			rejectClusterScopedResources: true,
+			expectedWarnings:             0,
+			expectedError:                "spec.rules[0]: Forbidden: {APIGroup: rbac.authorization.k8s.io, Resource: clusterroles} resources are cluster-scoped and cannot be targeted by a namespaced policy",
+		},
+		{
+			name: "cluster-scoped resource in a different API group",
+			rules: []admissionregistrationv1.RuleWithOperations{
+				{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"apps"},
+						APIVersions: []string{"v1"},
+						Resources:   []string{"nodes"},
+					},
+				},
+			},
+			rejectClusterScopedResources: true,
+			expectedWarnings:             0,
+			expectedError:                "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validator := admissionPolicyValidator{
+				logger:                       logr.Discard(),
+				rejectClusterScopedResources: test.rejectClusterScopedResources,
+			}
+			policy := NewAdmissionPolicyFactory().WithRules(test.rules).Build()
+
+			warnings, err := validator.ValidateCreate(t.Context(), policy)
+			assert.Len(t, warnings, test.expectedWarnings)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			warnings, err = validator.ValidateUpdate(t.Context(), policy, policy)
+			assert.Len(t, warnings, test.expectedWarnings)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
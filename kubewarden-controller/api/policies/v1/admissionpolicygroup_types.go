@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"strings"
+
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,7 +36,7 @@ type AdmissionPolicyGroupSpec struct {
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Policy Server",type=string,JSONPath=`.spec.policyServer`,description="Bound to Policy Server"
 // +kubebuilder:printcolumn:name="Mutating",type=boolean,JSONPath=`.spec.mutating`,description="Whether the policy is mutating"
-// +kubebuilder:printcolumn:name="BackgroundAudit",type=boolean,JSONPath=`.spec.backgroundAudit`,description="Whether the policy is used in audit checks"
+// +kubebuilder:printcolumn:name="BackgroundAudit",type=boolean,JSONPath=`.status.backgroundAudit`,description="Whether the policy is used in audit checks"
 // +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`,description="Policy deployment mode"
 // +kubebuilder:printcolumn:name="Observed mode",type=string,JSONPath=`.status.mode`,description="Policy deployment mode observed on the assigned Policy Server"
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.policyStatus`,description="Status of the policy"
@@ -83,8 +85,16 @@ func (r *AdmissionPolicyGroup) GetStatus() *PolicyStatus {
 	return &r.Status
 }
 
+// GetModule returns the modules of the members of the group, sorted and
+// joined by commas. It allows telling apart the groups by their composition.
 func (r *AdmissionPolicyGroup) GetModule() string {
-	return ""
+	return strings.Join(r.GetModules(), ",")
+}
+
+// GetModules returns the modules of the members of the group, sorted and
+// deduplicated.
+func (r *AdmissionPolicyGroup) GetModules() []string {
+	return r.Spec.Policies.modules()
 }
 
 func (r *AdmissionPolicyGroup) GetPolicyGroupMembersWithContext() PolicyGroupMembersWithContext {
@@ -146,6 +156,11 @@ func (r *AdmissionPolicyGroup) GetMatchPolicy() *admissionregistrationv1.MatchPo
 	return r.Spec.MatchPolicy
 }
 
+// GetReinvocationPolicy returns nil, policy groups are never mutating.
+func (r *AdmissionPolicyGroup) GetReinvocationPolicy() *admissionregistrationv1.ReinvocationPolicyType {
+	return nil
+}
+
 func (r *AdmissionPolicyGroup) GetMatchConditions() []admissionregistrationv1.MatchCondition {
 	return r.Spec.MatchConditions
 }
@@ -165,6 +180,10 @@ func (r *AdmissionPolicyGroup) GetTimeoutSeconds() *int32 {
 	return r.Spec.TimeoutSeconds
 }
 
+func (r *AdmissionPolicyGroup) GetResourceHints() *PolicyResourceHints {
+	return r.Spec.ResourceHints
+}
+
 func (r *AdmissionPolicyGroup) GetObjectMeta() *metav1.ObjectMeta {
 	return &r.ObjectMeta
 }
@@ -184,7 +203,7 @@ func (r *AdmissionPolicyGroup) GetContextAwareResources() []ContextAwareResource
 	return []ContextAwareResource{}
 }
 
-func (r *AdmissionPolicyGroup) GetBackgroundAudit() bool {
+func (r *AdmissionPolicyGroup) GetBackgroundAudit() *bool {
 	return r.Spec.BackgroundAudit
 }
 
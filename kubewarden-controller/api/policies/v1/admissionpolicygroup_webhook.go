@@ -26,10 +26,13 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
+	"github.com/kubewarden/kubewarden-controller/internal/tracing"
 )
 
 // SetupWebhookWithManager registers the AdmissionPolicyGroup webhook with the controller manager.
-func (r *AdmissionPolicyGroup) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// Policy groups with more than maxMembers members are rejected, a
+// non-positive value disables the limit.
+func (r *AdmissionPolicyGroup) SetupWebhookWithManager(mgr ctrl.Manager, maxMembers int) error {
 	logger := mgr.GetLogger().WithName("admissionpolicygroup-webhook")
 
 	err := ctrl.NewWebhookManagedBy(mgr).
@@ -38,7 +41,8 @@ func (r *AdmissionPolicyGroup) SetupWebhookWithManager(mgr ctrl.Manager) error {
 			logger: logger,
 		}).
 		WithValidator(&admissionPolicyGroupValidator{
-			logger: logger,
+			logger:     logger,
+			maxMembers: maxMembers,
 		}).
 		Complete()
 	if err != nil {
@@ -58,12 +62,14 @@ type admissionPolicyGroupDefaulter struct {
 var _ webhook.CustomDefaulter = &admissionPolicyGroupDefaulter{}
 
 // Default implements webhook.CustomDefaulter so a webhook will be registered for the type.
-func (d *admissionPolicyGroupDefaulter) Default(_ context.Context, obj runtime.Object) error {
+func (d *admissionPolicyGroupDefaulter) Default(ctx context.Context, obj runtime.Object) error {
 	admissionPolicyGroup, ok := obj.(*AdmissionPolicyGroup)
 	if !ok {
 		return fmt.Errorf("expected an AdmissionPolicyGroup object, got %T", obj)
 	}
 
+	tracing.RecordAdmission(ctx, admissionPolicyGroup)
+
 	d.logger.Info("Defaulting AdmissionPolicyGroup", "name", admissionPolicyGroup.GetName())
 
 	if admissionPolicyGroup.Spec.PolicyServer == "" {
@@ -80,13 +86,14 @@ func (d *admissionPolicyGroupDefaulter) Default(_ context.Context, obj runtime.O
 
 // admissionPolicyGroupValidator validates AdmissionPolicyGroup objects when they are created, updated, or deleted.
 type admissionPolicyGroupValidator struct {
-	logger logr.Logger
+	logger     logr.Logger
+	maxMembers int
 }
 
 var _ webhook.CustomValidator = &admissionPolicyGroupValidator{}
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
-func (v *admissionPolicyGroupValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *admissionPolicyGroupValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	admissionPolicyGroup, ok := obj.(*AdmissionPolicyGroup)
 	if !ok {
 		return nil, fmt.Errorf("expected an AdmissionPolicyGroup object, got %T", obj)
@@ -94,17 +101,19 @@ func (v *admissionPolicyGroupValidator) ValidateCreate(_ context.Context, obj ru
 
 	v.logger.Info("Validating AdmissionPolicyGroup creation", "name", admissionPolicyGroup.GetName())
 
-	allErrors := validatePolicyGroupCreate(admissionPolicyGroup)
+	warnings := matchConditionsWarnings(admissionPolicyGroup)
+	allErrors := validatePolicyGroupCreate(admissionPolicyGroup, v.maxMembers)
 
 	if len(allErrors) != 0 {
-		return nil, prepareInvalidAPIError(admissionPolicyGroup, allErrors)
+		recordValidationRejection(ctx, "AdmissionPolicyGroup", allErrors)
+		return warnings, prepareInvalidAPIError(admissionPolicyGroup, allErrors)
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (v *admissionPolicyGroupValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+func (v *admissionPolicyGroupValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	oldAdmissionPolicyGroup, ok := oldObj.(*AdmissionPolicyGroup)
 	if !ok {
 		return nil, fmt.Errorf("expected an AdmissionPolicyGroup object, got %T", oldObj)
@@ -116,11 +125,13 @@ func (v *admissionPolicyGroupValidator) ValidateUpdate(_ context.Context, oldObj
 
 	v.logger.Info("Validating AdmissionPolicyGroup update", "name", newAdmissionPolicyGroup.GetName())
 
-	if allErrors := validatePolicyGroupUpdate(oldAdmissionPolicyGroup, newAdmissionPolicyGroup); len(allErrors) != 0 {
-		return nil, prepareInvalidAPIError(newAdmissionPolicyGroup, allErrors)
+	warnings := matchConditionsWarnings(newAdmissionPolicyGroup)
+	if allErrors := validatePolicyGroupUpdate(oldAdmissionPolicyGroup, newAdmissionPolicyGroup, v.maxMembers); len(allErrors) != 0 {
+		recordValidationRejection(ctx, "AdmissionPolicyGroup", allErrors)
+		return warnings, prepareInvalidAPIError(newAdmissionPolicyGroup, allErrors)
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
@@ -229,3 +229,30 @@ func TestAdmissionPolicyGroupValidateDeleteWithInvalidType(t *testing.T) {
 	require.ErrorContains(t, err, "expected an AdmissionPolicyGroup object, got *v1.Pod")
 	assert.Empty(t, warnings)
 }
+
+func TestAdmissionPolicyGroupValidateMaxMembers(t *testing.T) {
+	validator := admissionPolicyGroupValidator{logger: logr.Discard(), maxMembers: 1}
+	oldPolicy := NewAdmissionPolicyGroupFactory().Build()
+	newPolicy := NewAdmissionPolicyGroupFactory().Build()
+	newPolicy.Spec.Policies["user_group_psp"] = PolicyGroupMember{
+		Module: "registry://ghcr.io/kubewarden/tests/user-group-psp:v0.4.9",
+	}
+
+	_, err := validator.ValidateCreate(t.Context(), oldPolicy)
+	require.NoError(t, err)
+
+	_, err = validator.ValidateCreate(t.Context(), newPolicy)
+	require.ErrorContains(t, err, "spec.policies: Too many: 2: must have at most 1 items")
+
+	_, err = validator.ValidateUpdate(t.Context(), oldPolicy, newPolicy)
+	require.ErrorContains(t, err, "spec.policies: Too many: 2: must have at most 1 items")
+
+	// The groups larger than the limit can be updated as long as they do
+	// not grow
+	_, err = validator.ValidateUpdate(t.Context(), newPolicy, newPolicy.DeepCopy())
+	require.NoError(t, err)
+
+	validator.maxMembers = 0
+	_, err = validator.ValidateCreate(t.Context(), newPolicy)
+	require.NoError(t, err)
+}
@@ -97,7 +97,7 @@ type ClusterAdmissionPolicySpec struct {
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Policy Server",type=string,JSONPath=`.spec.policyServer`,description="Bound to Policy Server"
 // +kubebuilder:printcolumn:name="Mutating",type=boolean,JSONPath=`.spec.mutating`,description="Whether the policy is mutating"
-// +kubebuilder:printcolumn:name="BackgroundAudit",type=boolean,JSONPath=`.spec.backgroundAudit`,description="Whether the policy is used in audit checks"
+// +kubebuilder:printcolumn:name="BackgroundAudit",type=boolean,JSONPath=`.status.backgroundAudit`,description="Whether the policy is used in audit checks"
 // +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`,description="Policy deployment mode"
 // +kubebuilder:printcolumn:name="Observed mode",type=string,JSONPath=`.status.mode`,description="Policy deployment mode observed on the assigned Policy Server"
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.policyStatus`,description="Status of the policy"
@@ -140,6 +140,10 @@ func (r *ClusterAdmissionPolicy) GetModule() string {
 	return r.Spec.Module
 }
 
+func (r *ClusterAdmissionPolicy) GetModules() []string {
+	return []string{r.Spec.Module}
+}
+
 func (r *ClusterAdmissionPolicy) IsMutating() bool {
 	return r.Spec.Mutating
 }
@@ -172,6 +176,10 @@ func (r *ClusterAdmissionPolicy) GetMatchPolicy() *admissionregistrationv1.Match
 	return r.Spec.MatchPolicy
 }
 
+func (r *ClusterAdmissionPolicy) GetReinvocationPolicy() *admissionregistrationv1.ReinvocationPolicyType {
+	return r.Spec.ReinvocationPolicy
+}
+
 func (r *ClusterAdmissionPolicy) GetRules() []admissionregistrationv1.RuleWithOperations {
 	return r.Spec.Rules
 }
@@ -192,6 +200,10 @@ func (r *ClusterAdmissionPolicy) GetTimeoutSeconds() *int32 {
 	return r.Spec.TimeoutSeconds
 }
 
+func (r *ClusterAdmissionPolicy) GetResourceHints() *PolicyResourceHints {
+	return r.Spec.ResourceHints
+}
+
 func (r *ClusterAdmissionPolicy) GetObjectMeta() *metav1.ObjectMeta {
 	return &r.ObjectMeta
 }
@@ -208,7 +220,7 @@ func (r *ClusterAdmissionPolicy) GetContextAwareResources() []ContextAwareResour
 	return r.Spec.ContextAwareResources
 }
 
-func (r *ClusterAdmissionPolicy) GetBackgroundAudit() bool {
+func (r *ClusterAdmissionPolicy) GetBackgroundAudit() *bool {
 	return r.Spec.BackgroundAudit
 }
 
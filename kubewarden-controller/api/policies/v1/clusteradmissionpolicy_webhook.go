@@ -25,28 +25,38 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/go-logr/logr"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
+	"github.com/kubewarden/kubewarden-controller/internal/tracing"
 )
 
 // SetupWebhookWithManager registers the ClusterAdmissionPolicy webhook with the controller manager.
 func (r *ClusterAdmissionPolicy) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	logger := mgr.GetLogger().WithName("clusteradmissionpolicy-webhook")
+	validator := &clusterAdmissionPolicyValidator{
+		k8sClient: mgr.GetClient(),
+		logger:    logger,
+	}
 
 	err := ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		WithDefaulter(&clusterAdmissionPolicyDefaulter{
 			logger: logger,
 		}).
-		WithValidator(&clusterAdmissionPolicyValidator{
-			logger: logger,
-		}).
+		WithValidator(validator).
 		Complete()
 	if err != nil {
 		return fmt.Errorf("failed enrolling webhook with manager: %w", err)
 	}
 
+	// The deletions only get warnings, they are validated by a separate
+	// webhook ignoring its failures, so that the policies can be deleted
+	// while the controller is not available.
+	mgr.GetWebhookServer().Register("/validate-delete-policies-kubewarden-io-v1-clusteradmissionpolicy",
+		admission.WithCustomValidator(mgr.GetScheme(), r, validator))
+
 	return nil
 }
 
@@ -60,12 +70,14 @@ type clusterAdmissionPolicyDefaulter struct {
 var _ webhook.CustomDefaulter = &clusterAdmissionPolicyDefaulter{}
 
 // Default implements webhook.CustomDefaulter so a webhook will be registered for the type.
-func (d *clusterAdmissionPolicyDefaulter) Default(_ context.Context, obj runtime.Object) error {
+func (d *clusterAdmissionPolicyDefaulter) Default(ctx context.Context, obj runtime.Object) error {
 	clusterAdmissionPolicy, ok := obj.(*ClusterAdmissionPolicy)
 	if !ok {
 		return fmt.Errorf("expected a ClusterAdmissionPolicy object, got %T", obj)
 	}
 
+	tracing.RecordAdmission(ctx, clusterAdmissionPolicy)
+
 	d.logger.Info("Defaulting ClusterAdmissionPolicy", "name", clusterAdmissionPolicy.GetName())
 
 	if clusterAdmissionPolicy.Spec.PolicyServer == "" {
@@ -79,16 +91,18 @@ func (d *clusterAdmissionPolicyDefaulter) Default(_ context.Context, obj runtime
 }
 
 //+kubebuilder:webhook:path=/validate-policies-kubewarden-io-v1-clusteradmissionpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=policies.kubewarden.io,resources=clusteradmissionpolicies,verbs=create;update,versions=v1,name=vclusteradmissionpolicy.kb.io,admissionReviewVersions={v1,v1beta1}
+//+kubebuilder:webhook:path=/validate-delete-policies-kubewarden-io-v1-clusteradmissionpolicy,mutating=false,failurePolicy=ignore,sideEffects=None,groups=policies.kubewarden.io,resources=clusteradmissionpolicies,verbs=delete,versions=v1,name=vdeleteclusteradmissionpolicy.kb.io,admissionReviewVersions={v1,v1beta1}
 
 // clusterAdmissionPolicyValidator validates ClusterAdmissionPolicy objects when they are created, updated, or deleted.
 type clusterAdmissionPolicyValidator struct {
-	logger logr.Logger
+	k8sClient client.Client
+	logger    logr.Logger
 }
 
 var _ webhook.CustomValidator = &clusterAdmissionPolicyValidator{}
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
-func (v *clusterAdmissionPolicyValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *clusterAdmissionPolicyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	clusterAdmissionPolicy, ok := obj.(*ClusterAdmissionPolicy)
 	if !ok {
 		return nil, fmt.Errorf("expected a ClusterAdmissionPolicy object, got %T", obj)
@@ -96,16 +110,18 @@ func (v *clusterAdmissionPolicyValidator) ValidateCreate(_ context.Context, obj
 
 	v.logger.Info("Validating ClusterAdmissionPolicy creation", "name", clusterAdmissionPolicy.GetName())
 
+	warnings := matchConditionsWarnings(clusterAdmissionPolicy)
 	allErrors := validatePolicyCreate(clusterAdmissionPolicy)
 	if len(allErrors) != 0 {
-		return nil, prepareInvalidAPIError(clusterAdmissionPolicy, allErrors)
+		recordValidationRejection(ctx, "ClusterAdmissionPolicy", allErrors)
+		return warnings, prepareInvalidAPIError(clusterAdmissionPolicy, allErrors)
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
-func (v *clusterAdmissionPolicyValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+func (v *clusterAdmissionPolicyValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	oldClusterAdmissionPolicy, ok := oldObj.(*ClusterAdmissionPolicy)
 	if !ok {
 		return nil, fmt.Errorf("expected a ClusterAdmissionPolicy object, got %T", oldObj)
@@ -117,16 +133,18 @@ func (v *clusterAdmissionPolicyValidator) ValidateUpdate(_ context.Context, oldO
 
 	v.logger.Info("Validating ClusterAdmissionPolicy update", "name", newClusterAdmissionPolicy.GetName())
 
+	warnings := matchConditionsWarnings(newClusterAdmissionPolicy)
 	allErrors := validatePolicyUpdate(oldClusterAdmissionPolicy, newClusterAdmissionPolicy)
 	if len(allErrors) != 0 {
-		return nil, prepareInvalidAPIError(newClusterAdmissionPolicy, allErrors)
+		recordValidationRejection(ctx, "ClusterAdmissionPolicy", allErrors)
+		return warnings, prepareInvalidAPIError(newClusterAdmissionPolicy, allErrors)
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
-func (v *clusterAdmissionPolicyValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *clusterAdmissionPolicyValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	clusterAdmissionPolicy, ok := obj.(*ClusterAdmissionPolicy)
 	if !ok {
 		return nil, fmt.Errorf("expected a ClusterAdmissionPolicy object, got %T", obj)
@@ -134,5 +152,12 @@ func (v *clusterAdmissionPolicyValidator) ValidateDelete(_ context.Context, obj
 
 	v.logger.Info("Validating ClusterAdmissionPolicy delete", "name", clusterAdmissionPolicy.GetName())
 
-	return nil, nil
+	// The lookup of the policy groups only produces warnings, failing it must
+	// not prevent the deletion of the policy.
+	warnings, err := policyGroupsSharingModuleWarnings(ctx, v.k8sClient, clusterAdmissionPolicy)
+	if err != nil {
+		v.logger.Error(err, "Cannot look up the policy groups running the module of the ClusterAdmissionPolicy", "name", clusterAdmissionPolicy.GetName())
+	}
+
+	return warnings, nil
 }
@@ -23,6 +23,7 @@ import (
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
 )
@@ -144,6 +145,45 @@ func TestClusterAdmissionPolicyValidateCreateWithErrors(t *testing.T) {
 	assert.Empty(t, warnings)
 }
 
+func TestClusterAdmissionPolicyValidateCreateWithMatchConditionsIdentifiers(t *testing.T) {
+	tests := []struct {
+		name             string
+		expression       string
+		expectedWarnings admission.Warnings
+	}{
+		{
+			"with known identifier",
+			"object.metadata.name == 'foo'",
+			nil,
+		},
+		{
+			"with unknown identifier",
+			"namespaceObject.metadata.name == 'foo'",
+			admission.Warnings{
+				`spec.matchConditions[0].expression: unknown identifier "namespaceObject", match conditions can only reference the authorizer, object, oldObject, request variables`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validator := clusterAdmissionPolicyValidator{logger: logr.Discard()}
+			policy := NewClusterAdmissionPolicyFactory().
+				WithMatchConditions([]admissionregistrationv1.MatchCondition{
+					{
+						Name:       "foo",
+						Expression: test.expression,
+					},
+				}).
+				Build()
+
+			warnings, err := validator.ValidateCreate(t.Context(), policy)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedWarnings, warnings)
+		})
+	}
+}
+
 func TestClusterAdmissionPolicyValidateCreateWithInvalidType(t *testing.T) {
 	validator := clusterAdmissionPolicyValidator{logger: logr.Discard()}
 	obj := &corev1.Pod{}
@@ -213,7 +253,7 @@ func TestClusterAdmissionPolicyValidateUpdateWithInvalidType(t *testing.T) {
 }
 
 func TestClusterAdmissionPolicyValidateDelete(t *testing.T) {
-	validator := clusterAdmissionPolicyValidator{logger: logr.Discard()}
+	validator := clusterAdmissionPolicyValidator{k8sClient: newPolicyGroupsFakeClient(t), logger: logr.Discard()}
 	policy := NewClusterAdmissionPolicyFactory().Build()
 
 	warnings, err := validator.ValidateDelete(t.Context(), policy)
@@ -221,6 +261,16 @@ func TestClusterAdmissionPolicyValidateDelete(t *testing.T) {
 	assert.Empty(t, warnings)
 }
 
+func TestClusterAdmissionPolicyValidateDeleteWithPolicyGroupSharingModule(t *testing.T) {
+	k8sClient := newPolicyGroupsFakeClient(t, NewClusterAdmissionPolicyGroupFactory().WithName("group").Build())
+	validator := clusterAdmissionPolicyValidator{k8sClient: k8sClient, logger: logr.Discard()}
+	policy := NewClusterAdmissionPolicyFactory().Build()
+
+	warnings, err := validator.ValidateDelete(t.Context(), policy)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`ClusterAdmissionPolicyGroup group runs the same module in its member "pod_privileged", the policy group is not affected by the deletion`}, []string(warnings))
+}
+
 func TestClusterAdmissionPolicyValidateDeleteWithInvalidType(t *testing.T) {
 	validator := clusterAdmissionPolicyValidator{logger: logr.Discard()}
 	obj := &corev1.Pod{}
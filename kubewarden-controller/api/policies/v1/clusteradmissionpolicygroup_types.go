@@ -17,11 +17,25 @@ limitations under the License.
 package v1
 
 import (
+	"strings"
+
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// +kubebuilder:validation:Enum=webhook;validatingAdmissionPolicy
+type PolicyGroupBackend string
+
+const (
+	// PolicyGroupBackendWebhook enforces the policy group with a webhook
+	// served by the PolicyServer the group is scheduled on.
+	PolicyGroupBackendWebhook PolicyGroupBackend = "webhook"
+	// PolicyGroupBackendValidatingAdmissionPolicy enforces the policy group
+	// with a Kubernetes ValidatingAdmissionPolicy and its binding.
+	PolicyGroupBackendValidatingAdmissionPolicy PolicyGroupBackend = "validatingAdmissionPolicy"
+)
+
 type ClusterPolicyGroupSpec struct {
 	GroupSpec `json:""`
 
@@ -83,6 +97,20 @@ type ClusterAdmissionPolicyGroupSpec struct {
 	// Default to the empty LabelSelector, which matches everything.
 	// +optional
 	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Backend selects how the policy group is enforced. Can be set to
+	// either "webhook" or "validatingAdmissionPolicy".
+	// When set to "validatingAdmissionPolicy", the policy group is compiled
+	// into a Kubernetes ValidatingAdmissionPolicy and its binding, as long as
+	// all the policy members are CEL policies whose settings only contain
+	// validations. Otherwise, or when the cluster does not serve the
+	// ValidatingAdmissionPolicy API, the policy group falls back to the
+	// webhook backend and the PolicyGroupBackendFallback condition reports
+	// why. The backend in use is reported in the status.
+	// If it's empty, it is defaulted to "webhook".
+	// +kubebuilder:default:=webhook
+	// +optional
+	Backend PolicyGroupBackend `json:"backend,omitempty"`
 }
 
 // ClusterAdmissionPolicyGroup is the Schema for the clusteradmissionpolicies API
@@ -92,7 +120,7 @@ type ClusterAdmissionPolicyGroupSpec struct {
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Policy Server",type=string,JSONPath=`.spec.policyServer`,description="Bound to Policy Server"
 // +kubebuilder:printcolumn:name="Mutating",type=boolean,JSONPath=`.spec.mutating`,description="Whether the policy is mutating"
-// +kubebuilder:printcolumn:name="BackgroundAudit",type=boolean,JSONPath=`.spec.backgroundAudit`,description="Whether the policy is used in audit checks"
+// +kubebuilder:printcolumn:name="BackgroundAudit",type=boolean,JSONPath=`.status.backgroundAudit`,description="Whether the policy is used in audit checks"
 // +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`,description="Policy deployment mode"
 // +kubebuilder:printcolumn:name="Observed mode",type=string,JSONPath=`.status.mode`,description="Policy deployment mode observed on the assigned Policy Server"
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.policyStatus`,description="Status of the policy"
@@ -131,8 +159,16 @@ func (r *ClusterAdmissionPolicyGroup) SetPolicyModeStatus(policyMode PolicyModeS
 	r.Status.PolicyMode = policyMode
 }
 
+// GetModule returns the modules of the members of the group, sorted and
+// joined by commas. It allows telling apart the groups by their composition.
 func (r *ClusterAdmissionPolicyGroup) GetModule() string {
-	return ""
+	return strings.Join(r.GetModules(), ",")
+}
+
+// GetModules returns the modules of the members of the group, sorted and
+// deduplicated.
+func (r *ClusterAdmissionPolicyGroup) GetModules() []string {
+	return r.Spec.Policies.modules()
 }
 
 func (r *ClusterAdmissionPolicyGroup) IsMutating() bool {
@@ -186,6 +222,11 @@ func (r *ClusterAdmissionPolicyGroup) GetMatchPolicy() *admissionregistrationv1.
 	return r.Spec.MatchPolicy
 }
 
+// GetReinvocationPolicy returns nil, policy groups are never mutating.
+func (r *ClusterAdmissionPolicyGroup) GetReinvocationPolicy() *admissionregistrationv1.ReinvocationPolicyType {
+	return nil
+}
+
 func (r *ClusterAdmissionPolicyGroup) GetRules() []admissionregistrationv1.RuleWithOperations {
 	return r.Spec.Rules
 }
@@ -206,6 +247,10 @@ func (r *ClusterAdmissionPolicyGroup) GetTimeoutSeconds() *int32 {
 	return r.Spec.TimeoutSeconds
 }
 
+func (r *ClusterAdmissionPolicyGroup) GetResourceHints() *PolicyResourceHints {
+	return r.Spec.ResourceHints
+}
+
 func (r *ClusterAdmissionPolicyGroup) GetObjectMeta() *metav1.ObjectMeta {
 	return &r.ObjectMeta
 }
@@ -218,14 +263,14 @@ func (r *ClusterAdmissionPolicyGroup) GetUniqueName() string {
 	return "clusterwide-group-" + r.Name
 }
 
+// GetContextAwareResources returns the union of the context-aware resources
+// of the members. A resource requested by several members is listed once, so
+// that the access to it is granted once to the policy server.
 func (r *ClusterAdmissionPolicyGroup) GetContextAwareResources() []ContextAwareResource {
-	// We return an empty slice here because the policy memebers have the
-	// context aware resources. Therefore, the policy group does not need
-	// to have them.
-	return []ContextAwareResource{}
+	return r.Spec.Policies.contextAwareResources()
 }
 
-func (r *ClusterAdmissionPolicyGroup) GetBackgroundAudit() bool {
+func (r *ClusterAdmissionPolicyGroup) GetBackgroundAudit() *bool {
 	return r.Spec.BackgroundAudit
 }
 
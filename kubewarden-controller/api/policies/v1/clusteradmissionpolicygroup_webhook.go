@@ -28,9 +28,13 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
+	"github.com/kubewarden/kubewarden-controller/internal/tracing"
 )
 
-func (r *ClusterAdmissionPolicyGroup) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// SetupWebhookWithManager registers the ClusterAdmissionPolicyGroup webhook with the controller manager.
+// Policy groups with more than maxMembers members are rejected, a
+// non-positive value disables the limit.
+func (r *ClusterAdmissionPolicyGroup) SetupWebhookWithManager(mgr ctrl.Manager, maxMembers int) error {
 	logger := mgr.GetLogger().WithName("clusteradmissionpolicygroup-webhook")
 
 	err := ctrl.NewWebhookManagedBy(mgr).
@@ -39,7 +43,8 @@ func (r *ClusterAdmissionPolicyGroup) SetupWebhookWithManager(mgr ctrl.Manager)
 			logger: logger,
 		}).
 		WithValidator(&clusterAdmissionPolicyGroupValidator{
-			logger: logger,
+			logger:     logger,
+			maxMembers: maxMembers,
 		}).
 		Complete()
 	if err != nil {
@@ -59,12 +64,14 @@ type clusterAdmissionPolicyGroupDefaulter struct {
 var _ webhook.CustomDefaulter = &clusterAdmissionPolicyGroupDefaulter{}
 
 // Default implements webhook.CustomDefaulter so a webhook will be registered for the type.
-func (d *clusterAdmissionPolicyGroupDefaulter) Default(_ context.Context, obj runtime.Object) error {
+func (d *clusterAdmissionPolicyGroupDefaulter) Default(ctx context.Context, obj runtime.Object) error {
 	clusterAdmissionPolicyGroup, ok := obj.(*ClusterAdmissionPolicyGroup)
 	if !ok {
 		return fmt.Errorf("expected a ClusterAdmissionPolicyGroup object, got %T", obj)
 	}
 
+	tracing.RecordAdmission(ctx, clusterAdmissionPolicyGroup)
+
 	d.logger.Info("Defaulting ClusterAdmissionPolicyGroup", "name", clusterAdmissionPolicyGroup.GetName())
 
 	if clusterAdmissionPolicyGroup.Spec.PolicyServer == "" {
@@ -81,13 +88,14 @@ func (d *clusterAdmissionPolicyGroupDefaulter) Default(_ context.Context, obj ru
 
 // clusterAdmissionPolicyGroupValidator validates ClusterAdmissionPolicyGroup objects when they are created, updated, or deleted.
 type clusterAdmissionPolicyGroupValidator struct {
-	logger logr.Logger
+	logger     logr.Logger
+	maxMembers int
 }
 
 var _ webhook.CustomValidator = &clusterAdmissionPolicyGroupValidator{}
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
-func (v *clusterAdmissionPolicyGroupValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *clusterAdmissionPolicyGroupValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	clusterAdmissionPolicyGroup, ok := obj.(*ClusterAdmissionPolicyGroup)
 	if !ok {
 		return nil, fmt.Errorf("expected a ClusterAdmissionPolicyGroup object, got %T", obj)
@@ -95,16 +103,19 @@ func (v *clusterAdmissionPolicyGroupValidator) ValidateCreate(_ context.Context,
 
 	v.logger.Info("Validating ClusterAdmissionPolicyGroup creation", "name", clusterAdmissionPolicyGroup.GetName())
 
-	allErrors := validatePolicyGroupCreate(clusterAdmissionPolicyGroup)
+	warnings := matchConditionsWarnings(clusterAdmissionPolicyGroup)
+	warnings = append(warnings, policyGroupSelectorsWarnings(clusterAdmissionPolicyGroup)...)
+	allErrors := validatePolicyGroupCreate(clusterAdmissionPolicyGroup, v.maxMembers)
 	if len(allErrors) != 0 {
-		return nil, prepareInvalidAPIError(clusterAdmissionPolicyGroup, allErrors)
+		recordValidationRejection(ctx, "ClusterAdmissionPolicyGroup", allErrors)
+		return warnings, prepareInvalidAPIError(clusterAdmissionPolicyGroup, allErrors)
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
-func (v *clusterAdmissionPolicyGroupValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+func (v *clusterAdmissionPolicyGroupValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	oldclusterAdmissionPolicyGroup, ok := oldObj.(*ClusterAdmissionPolicyGroup)
 	if !ok {
 		return nil, fmt.Errorf("expected a ClusterAdmissionPolicyGroup object, got %T", oldObj)
@@ -116,11 +127,14 @@ func (v *clusterAdmissionPolicyGroupValidator) ValidateUpdate(_ context.Context,
 
 	v.logger.Info("Validating ClusterAdmissionPolicyGroup update", "name", newclusterAdmissionPolicyGroup.GetName())
 
-	if allErrors := validatePolicyGroupUpdate(oldclusterAdmissionPolicyGroup, newclusterAdmissionPolicyGroup); len(allErrors) != 0 {
-		return nil, prepareInvalidAPIError(newclusterAdmissionPolicyGroup, allErrors)
+	warnings := matchConditionsWarnings(newclusterAdmissionPolicyGroup)
+	warnings = append(warnings, policyGroupSelectorsWarnings(newclusterAdmissionPolicyGroup)...)
+	if allErrors := validatePolicyGroupUpdate(oldclusterAdmissionPolicyGroup, newclusterAdmissionPolicyGroup, v.maxMembers); len(allErrors) != 0 {
+		recordValidationRejection(ctx, "ClusterAdmissionPolicyGroup", allErrors)
+		return warnings, prepareInvalidAPIError(newclusterAdmissionPolicyGroup, allErrors)
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
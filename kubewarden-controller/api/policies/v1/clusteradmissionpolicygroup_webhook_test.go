@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/require"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
 )
@@ -54,6 +55,45 @@ func TestClusterAdmissionPolicyGroupValidateCreate(t *testing.T) {
 	assert.Empty(t, warnings)
 }
 
+func TestClusterAdmissionPolicyGroupValidateCreateWithMatchConditionsIdentifiers(t *testing.T) {
+	tests := []struct {
+		name             string
+		expression       string
+		expectedWarnings admission.Warnings
+	}{
+		{
+			"with known identifier",
+			"object.metadata.name == 'foo'",
+			nil,
+		},
+		{
+			"with unknown identifier",
+			"namespaceObject.metadata.name == 'foo'",
+			admission.Warnings{
+				`spec.matchConditions[0].expression: unknown identifier "namespaceObject", match conditions can only reference the authorizer, object, oldObject, request variables`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validator := clusterAdmissionPolicyGroupValidator{logger: logr.Discard()}
+			policy := NewClusterAdmissionPolicyGroupFactory().
+				WithMatchConditions([]admissionregistrationv1.MatchCondition{
+					{
+						Name:       "foo",
+						Expression: test.expression,
+					},
+				}).
+				Build()
+
+			warnings, err := validator.ValidateCreate(t.Context(), policy)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedWarnings, warnings)
+		})
+	}
+}
+
 func TestClusterAdmissionPolicyGroupValidateCreateWithErrors(t *testing.T) {
 	policy := NewClusterAdmissionPolicyGroupFactory().
 		WithPolicyServer("").
@@ -238,3 +278,32 @@ func TestClusterAdmissionPolicyGroupValidateDeleteWithInvalidType(t *testing.T)
 	require.ErrorContains(t, err, "expected a ClusterAdmissionPolicyGroup object, got *v1.Pod")
 	assert.Empty(t, warnings)
 }
+
+func TestClusterAdmissionPolicyGroupValidateMaxMembers(t *testing.T) {
+	validator := clusterAdmissionPolicyGroupValidator{logger: logr.Discard(), maxMembers: 2}
+	oldPolicy := NewClusterAdmissionPolicyGroupFactory().Build()
+	newPolicy := NewClusterAdmissionPolicyGroupFactory().Build()
+	newPolicy.Spec.Policies["safe_labels"] = PolicyGroupMemberWithContext{
+		PolicyGroupMember: PolicyGroupMember{
+			Module: "registry://ghcr.io/kubewarden/tests/safe-labels:v1.0.0",
+		},
+	}
+
+	_, err := validator.ValidateCreate(t.Context(), oldPolicy)
+	require.NoError(t, err)
+
+	_, err = validator.ValidateCreate(t.Context(), newPolicy)
+	require.ErrorContains(t, err, "spec.policies: Too many: 3: must have at most 2 items")
+
+	_, err = validator.ValidateUpdate(t.Context(), oldPolicy, newPolicy)
+	require.ErrorContains(t, err, "spec.policies: Too many: 3: must have at most 2 items")
+
+	// The groups larger than the limit can be updated as long as they do
+	// not grow
+	_, err = validator.ValidateUpdate(t.Context(), newPolicy, newPolicy.DeepCopy())
+	require.NoError(t, err)
+
+	validator.maxMembers = 0
+	_, err = validator.ValidateCreate(t.Context(), newPolicy)
+	require.NoError(t, err)
+}
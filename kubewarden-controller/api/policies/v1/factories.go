@@ -346,6 +346,7 @@ type ClusterAdmissionPolicyGroupFactory struct {
 	matchConds    []admissionregistrationv1.MatchCondition
 	mode          PolicyMode
 	message       string
+	backend       PolicyGroupBackend
 }
 
 func NewClusterAdmissionPolicyGroupFactory() *ClusterAdmissionPolicyGroupFactory {
@@ -421,6 +422,16 @@ func (f *ClusterAdmissionPolicyGroupFactory) WithMode(mode PolicyMode) *ClusterA
 	return f
 }
 
+func (f *ClusterAdmissionPolicyGroupFactory) WithExpression(expression string) *ClusterAdmissionPolicyGroupFactory {
+	f.expression = expression
+	return f
+}
+
+func (f *ClusterAdmissionPolicyGroupFactory) WithBackend(backend PolicyGroupBackend) *ClusterAdmissionPolicyGroupFactory {
+	f.backend = backend
+	return f
+}
+
 func (f *ClusterAdmissionPolicyGroupFactory) Build() *ClusterAdmissionPolicyGroup {
 	clusterAdmissionPolicy := ClusterAdmissionPolicyGroup{
 		ObjectMeta: metav1.ObjectMeta{
@@ -447,23 +458,25 @@ func (f *ClusterAdmissionPolicyGroupFactory) Build() *ClusterAdmissionPolicyGrou
 				},
 				Policies: f.policyMembers,
 			},
+			Backend: f.backend,
 		},
 	}
 	return &clusterAdmissionPolicy
 }
 
 type PolicyServerBuilder struct {
-	name            string
-	minAvailable    *intstr.IntOrString
-	maxUnavailable  *intstr.IntOrString
-	imagePullSecret string
-	limits          corev1.ResourceList
-	requests        corev1.ResourceList
+	name             string
+	minAvailable     *intstr.IntOrString
+	maxUnavailable   *intstr.IntOrString
+	imagePullSecret  string
+	imagePullSecrets []corev1.LocalObjectReference
+	limits           corev1.ResourceList
+	requests         corev1.ResourceList
 }
 
 func NewPolicyServerFactory() *PolicyServerBuilder {
 	return &PolicyServerBuilder{
-		name: newName("policy-server"),
+		name: newName("policyserver"),
 	}
 }
 
@@ -487,6 +500,13 @@ func (f *PolicyServerBuilder) WithImagePullSecret(secret string) *PolicyServerBu
 	return f
 }
 
+func (f *PolicyServerBuilder) WithImagePullSecrets(secrets ...string) *PolicyServerBuilder {
+	for _, secret := range secrets {
+		f.imagePullSecrets = append(f.imagePullSecrets, corev1.LocalObjectReference{Name: secret})
+	}
+	return f
+}
+
 func (f *PolicyServerBuilder) WithLimits(limits corev1.ResourceList) *PolicyServerBuilder {
 	f.limits = limits
 	return f
@@ -512,13 +532,14 @@ func (f *PolicyServerBuilder) Build() *PolicyServer {
 			},
 		},
 		Spec: PolicyServerSpec{
-			Image:           policyServerRepository() + ":" + policyServerVersion(),
-			Replicas:        1,
-			MinAvailable:    f.minAvailable,
-			MaxUnavailable:  f.maxUnavailable,
-			ImagePullSecret: f.imagePullSecret,
-			Limits:          f.limits,
-			Requests:        f.requests,
+			Image:            policyServerRepository() + ":" + policyServerVersion(),
+			Replicas:         1,
+			MinAvailable:     f.minAvailable,
+			MaxUnavailable:   f.maxUnavailable,
+			ImagePullSecret:  f.imagePullSecret,
+			ImagePullSecrets: f.imagePullSecrets,
+			Limits:           f.limits,
+			Requests:         f.requests,
 		},
 	}
 
@@ -52,6 +52,28 @@ const (
 	// for this policy, only the latest instance of the policy can be
 	// reached through policy server where it is scheduled.
 	PolicyUniquelyReachable PolicyConditionType = "PolicyUniquelyReachable"
+	// PolicyMatchConditionsIgnored represents the condition of the
+	// matchConditions of the policy not being applied to its webhook,
+	// because the AdmissionWebhookMatchConditions feature gate is disabled
+	// in the cluster. The condition is set only when the policy has
+	// matchConditions.
+	PolicyMatchConditionsIgnored PolicyConditionType = "PolicyMatchConditionsIgnored"
+	// PolicyReconciliationPaused represents the condition of the policy
+	// reconciliation being paused by the kubewarden.io/paused annotation.
+	// The condition is set only while the reconciliation is paused.
+	PolicyReconciliationPaused PolicyConditionType = "PolicyReconciliationPaused"
+	// PolicyNamespaceNotAllowed represents the condition of the policy not
+	// being served, and its webhook removed, because its namespace is not in
+	// the namespace allow-list of the controller. The condition is set only on the
+	// AdmissionPolicies outside of the allow-list.
+	PolicyNamespaceNotAllowed PolicyConditionType = "PolicyNamespaceNotAllowed"
+	// PolicyGroupBackendFallback represents the condition of a policy group
+	// requesting the validatingAdmissionPolicy backend being enforced with
+	// the webhook backend instead, because it cannot be compiled into a
+	// ValidatingAdmissionPolicy or the cluster does not serve the
+	// ValidatingAdmissionPolicy API. The condition is set only while the
+	// policy group falls back to the webhook backend.
+	PolicyGroupBackendFallback PolicyConditionType = "PolicyGroupBackendFallback"
 )
 
 const (
@@ -68,6 +90,15 @@ type PolicyStatus struct {
 	// PolicyMode represents the observed policy mode of this policy in
 	// the associated PolicyServer configuration
 	PolicyMode PolicyModeStatus `json:"mode,omitempty"`
+	// Backend represents the backend enforcing the policy. It is only set
+	// for ClusterAdmissionPolicyGroup resources.
+	// +optional
+	Backend PolicyGroupBackend `json:"backend,omitempty"`
+	// BackgroundAudit is the backgroundAudit of the policy resolved against
+	// the defaultBackgroundAudit of its policy server. It is unset until the
+	// policy is bound to an existing policy server.
+	// +optional
+	BackgroundAudit *bool `json:"backgroundAudit,omitempty"`
 	// Conditions represent the observed conditions of the
 	// ClusterAdmissionPolicy resource.  Known .status.conditions.types
 	// are: "PolicyServerSecretReconciled",
@@ -86,15 +117,17 @@ type PolicyStatus struct {
 type PolicySettings interface {
 	GetPolicyMode() PolicyMode
 	GetModule() string
+	GetModules() []string
 	GetSettings() runtime.RawExtension
 	GetContextAwareResources() []ContextAwareResource
-	GetBackgroundAudit() bool
+	GetBackgroundAudit() *bool
 	GetSeverity() (string, bool)
 	GetCategory() (string, bool)
 	GetTitle() (string, bool)
 	GetDescription() (string, bool)
 	GetTimeoutSeconds() *int32
 	GetMessage() string
+	GetResourceHints() *PolicyResourceHints
 }
 
 // +kubebuilder:object:generate:=false
@@ -110,6 +143,7 @@ type PolicyAdmissionRegistrationSettings interface {
 	GetFailurePolicy() *admissionregistrationv1.FailurePolicyType
 	GetMatchPolicy() *admissionregistrationv1.MatchPolicyType
 	GetMatchConditions() []admissionregistrationv1.MatchCondition
+	GetReinvocationPolicy() *admissionregistrationv1.ReinvocationPolicyType
 }
 
 // +kubebuilder:object:generate:=false
@@ -16,7 +16,12 @@ limitations under the License.
 package v1
 
 import (
+	"cmp"
+	"slices"
+
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -76,13 +81,22 @@ type PolicySpec struct {
 	// incoming requests or not.
 	Mutating bool `json:"mutating"`
 
+	// ReinvocationPolicy indicates whether a mutating policy should be called
+	// again when the object is modified by other admission plugins after the
+	// policy evaluation. Allowed values are "Never" and "IfNeeded".
+	// Only available for mutating policies.
+	// Defaults to "Never".
+	// +optional
+	ReinvocationPolicy *admissionregistrationv1.ReinvocationPolicyType `json:"reinvocationPolicy,omitempty"`
+
 	// BackgroundAudit indicates whether a policy should be used or skipped when
 	// performing audit checks. If false, the policy cannot produce meaningful
 	// evaluation results during audit checks and will be skipped.
-	// The default is "true".
-	// +kubebuilder:default:=true
+	// When unset, the defaultBackgroundAudit of the policy server is used,
+	// which defaults to "true". The resolved value is reported in
+	// status.backgroundAudit.
 	// +optional
-	BackgroundAudit bool `json:"backgroundAudit"`
+	BackgroundAudit *bool `json:"backgroundAudit,omitempty"`
 
 	// matchPolicy defines how the "rules" list is used to match incoming requests.
 	// Allowed values are "Exact" or "Equivalent".
@@ -145,11 +159,18 @@ type PolicySpec struct {
 	// the webhook call will be ignored or the API call will fail based on the
 	// failure policy.
 	// The timeout value must be between 1 and 30 seconds.
-	// Default to 10 seconds.
+	// When unset, the defaultPolicyTimeoutSeconds of the PolicyServer is
+	// used. Default to 10 seconds.
 	// +optional
-	// +kubebuilder:default:=10
 	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
 
+	// ResourceHints declares the resources the policy is expected to need
+	// on its Policy Server. The hints of the policies bound to a Policy
+	// Server are aggregated into the recommended requests reported in its
+	// status. They are not applied to the Policy Server automatically.
+	// +optional
+	ResourceHints *PolicyResourceHints `json:"resourceHints,omitempty"`
+
 	// Message overrides the rejection message of the policy.
 	// When provided, the policy's rejection message can be found
 	// inside of the `.status.details.causes` field of the
@@ -160,6 +181,15 @@ type PolicySpec struct {
 
 type PolicyGroupMembers map[string]PolicyGroupMember
 
+// modules returns the sorted and deduplicated modules of the members.
+func (m PolicyGroupMembers) modules() []string {
+	modules := make([]string, 0, len(m))
+	for _, member := range m {
+		modules = append(modules, member.Module)
+	}
+	return uniqueModules(modules)
+}
+
 type PolicyGroupMember struct {
 	// Module is the location of the WASM module to be loaded. Can be a
 	// local file (file://), a remote file served by an HTTP server
@@ -181,6 +211,44 @@ type PolicyGroupMember struct {
 
 type PolicyGroupMembersWithContext map[string]PolicyGroupMemberWithContext
 
+// modules returns the sorted and deduplicated modules of the members.
+func (m PolicyGroupMembersWithContext) modules() []string {
+	modules := make([]string, 0, len(m))
+	for _, member := range m {
+		modules = append(modules, member.Module)
+	}
+	return uniqueModules(modules)
+}
+
+// contextAwareResources returns the union of the context-aware resources of
+// the members, each resource listed once.
+func (m PolicyGroupMembersWithContext) contextAwareResources() []ContextAwareResource {
+	resources := make([][]ContextAwareResource, 0, len(m))
+	for _, member := range m {
+		resources = append(resources, member.ContextAwareResources)
+	}
+	return UniqueContextAwareResources(resources...)
+}
+
+// UniqueContextAwareResources returns the given context-aware resources
+// sorted by apiVersion and kind, each resource listed once.
+func UniqueContextAwareResources(resources ...[]ContextAwareResource) []ContextAwareResource {
+	unique := slices.Concat(resources...)
+	slices.SortFunc(unique, func(a, b ContextAwareResource) int {
+		return cmp.Or(cmp.Compare(a.APIVersion, b.APIVersion), cmp.Compare(a.Kind, b.Kind))
+	})
+	return slices.Compact(unique)
+}
+
+// uniqueModules returns the given modules sorted and deduplicated, a
+// representation that does not depend on the order or on the names of the
+// group members, so that two groups running the same modules have the same
+// one.
+func uniqueModules(modules []string) []string {
+	slices.Sort(modules)
+	return slices.Compact(modules)
+}
+
 type PolicyGroupMemberWithContext struct {
 	PolicyGroupMember `json:""`
 
@@ -225,10 +293,11 @@ type GroupSpec struct {
 	// BackgroundAudit indicates whether a policy should be used or skipped when
 	// performing audit checks. If false, the policy cannot produce meaningful
 	// evaluation results during audit checks and will be skipped.
-	// The default is "true".
-	// +kubebuilder:default:=true
+	// When unset, the defaultBackgroundAudit of the policy server is used,
+	// which defaults to "true". The resolved value is reported in
+	// status.backgroundAudit.
 	// +optional
-	BackgroundAudit bool `json:"backgroundAudit"`
+	BackgroundAudit *bool `json:"backgroundAudit,omitempty"`
 
 	// matchPolicy defines how the "rules" list is used to match incoming requests.
 	// Allowed values are "Exact" or "Equivalent".
@@ -291,11 +360,18 @@ type GroupSpec struct {
 	// the webhook call will be ignored or the API call will fail based on the
 	// failure policy.
 	// The timeout value must be between 1 and 30 seconds.
-	// Default to 10 seconds.
+	// When unset, the defaultPolicyTimeoutSeconds of the PolicyServer is
+	// used. Default to 10 seconds.
 	// +optional
-	// +kubebuilder:default:=10
 	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
 
+	// ResourceHints declares the resources the policy is expected to need
+	// on its Policy Server. The hints of the policies bound to a Policy
+	// Server are aggregated into the recommended requests reported in its
+	// status. They are not applied to the Policy Server automatically.
+	// +optional
+	ResourceHints *PolicyResourceHints `json:"resourceHints,omitempty"`
+
 	// Expression is the evaluation expression to accept or reject the
 	// admission request under evaluation. This field uses CEL as the
 	// expression language for the policy groups. Each policy in the group
@@ -317,6 +393,48 @@ type GroupSpec struct {
 	Message string `json:"message"`
 }
 
+// PolicyResourceHints describes the resources a policy is expected to need
+// on its Policy Server, on top of the ones needed by the Policy Server
+// itself.
+type PolicyResourceHints struct {
+	// CPU is the CPU the policy is expected to need.
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+	// Memory is the memory the policy is expected to need. Context-aware
+	// policies should account for the resources they cache.
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+}
+
+// AggregateResourceHints sums the resource hints of the given policies into
+// a list of resource requests. It returns nil when none of the policies
+// declares a hint.
+func AggregateResourceHints(policies []Policy) corev1.ResourceList {
+	var requests corev1.ResourceList
+	add := func(name corev1.ResourceName, quantity *resource.Quantity) {
+		if quantity == nil {
+			return
+		}
+		if requests == nil {
+			requests = corev1.ResourceList{}
+		}
+		total := requests[name]
+		total.Add(*quantity)
+		requests[name] = total
+	}
+
+	for _, policy := range policies {
+		hints := policy.GetResourceHints()
+		if hints == nil {
+			continue
+		}
+		add(corev1.ResourceCPU, hints.CPU)
+		add(corev1.ResourceMemory, hints.Memory)
+	}
+
+	return requests
+}
+
 type PolicyGroupSpec struct {
 	GroupSpec `json:""`
 
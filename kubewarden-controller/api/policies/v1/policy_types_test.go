@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+func TestAggregateResourceHints(t *testing.T) {
+	policy := NewClusterAdmissionPolicyFactory().Build()
+	policy.Spec.ResourceHints = &PolicyResourceHints{
+		CPU:    ptr.To(resource.MustParse("100m")),
+		Memory: ptr.To(resource.MustParse("64Mi")),
+	}
+	namespacedPolicy := NewAdmissionPolicyFactory().Build()
+	namespacedPolicy.Spec.ResourceHints = &PolicyResourceHints{
+		Memory: ptr.To(resource.MustParse("192Mi")),
+	}
+	group := NewAdmissionPolicyGroupFactory().Build()
+	group.Spec.ResourceHints = &PolicyResourceHints{
+		CPU: ptr.To(resource.MustParse("0.5")),
+	}
+	policyWithoutHints := NewClusterAdmissionPolicyGroupFactory().Build()
+
+	tests := []struct {
+		name     string
+		policies []Policy
+		expected corev1.ResourceList
+	}{
+		{"no policies", nil, nil},
+		{"policies without hints", []Policy{policyWithoutHints}, nil},
+		{
+			"policies with hints",
+			[]Policy{policy, namespacedPolicy, group, policyWithoutHints},
+			corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("600m"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests := AggregateResourceHints(test.policies)
+			assert.Len(t, requests, len(test.expected))
+			for name, quantity := range test.expected {
+				assert.Zero(t, quantity.Cmp(requests[name]), "unexpected %s request: %s", name, requests.Name(name, resource.DecimalSI))
+			}
+		})
+	}
+}
+
+func TestPolicyServerIsBackgroundAuditEnabled(t *testing.T) {
+	tests := []struct {
+		name                   string
+		backgroundAudit        *bool
+		defaultBackgroundAudit *bool
+		expected               bool
+	}{
+		{"unset policy and policy server", nil, nil, true},
+		{"unset policy, policy server enabling the audit", nil, ptr.To(true), true},
+		{"unset policy, policy server disabling the audit", nil, ptr.To(false), false},
+		{"audited policy, unset policy server", ptr.To(true), nil, true},
+		{"audited policy, policy server enabling the audit", ptr.To(true), ptr.To(true), true},
+		{"audited policy, policy server disabling the audit", ptr.To(true), ptr.To(false), true},
+		{"policy excluded from the audit, unset policy server", ptr.To(false), nil, false},
+		{"policy excluded from the audit, policy server enabling the audit", ptr.To(false), ptr.To(true), false},
+		{"policy excluded from the audit, policy server disabling the audit", ptr.To(false), ptr.To(false), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policyServer := NewPolicyServerFactory().Build()
+			policyServer.Spec.DefaultBackgroundAudit = test.defaultBackgroundAudit
+			policy := NewClusterAdmissionPolicyFactory().Build()
+			policy.Spec.BackgroundAudit = test.backgroundAudit
+
+			assert.Equal(t, test.expected, policyServer.IsBackgroundAuditEnabled(policy))
+		})
+	}
+}
+
+func TestPolicyServerPorts(t *testing.T) {
+	tests := []struct {
+		name                       string
+		env                        []corev1.EnvVar
+		expectedListenPort         int
+		expectedReadinessProbePort int
+	}{
+		{"default ports", nil, constants.PolicyServerListenPort, constants.PolicyServerReadinessProbePort},
+		{
+			"ports set in env",
+			[]corev1.EnvVar{
+				{Name: constants.PolicyServerListenPortEnvVar, Value: "9443"},
+				{Name: constants.PolicyServerReadinessProbePortEnvVar, Value: "9081"},
+			},
+			9443,
+			9081,
+		},
+		{
+			"invalid port in env",
+			[]corev1.EnvVar{{Name: constants.PolicyServerListenPortEnvVar, Value: "https"}},
+			constants.PolicyServerListenPort,
+			constants.PolicyServerReadinessProbePort,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policyServer := NewPolicyServerFactory().Build()
+			policyServer.Spec.Env = test.env
+
+			assert.Equal(t, test.expectedListenPort, policyServer.ListenPort())
+			assert.Equal(t, test.expectedReadinessProbePort, policyServer.ReadinessProbePort())
+		})
+	}
+}
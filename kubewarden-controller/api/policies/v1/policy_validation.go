@@ -17,9 +17,14 @@ limitations under the License.
 package v1
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
+	celgo "github.com/google/cel-go/cel"
+	celast "github.com/google/cel-go/common/ast"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -29,6 +34,8 @@ import (
 	"k8s.io/apiserver/pkg/admission/plugin/webhook/matchconditions"
 	"k8s.io/apiserver/pkg/cel"
 	"k8s.io/apiserver/pkg/cel/environment"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 // nonStrictStatelessCELCompiler is a cel Compiler that does not enforce strict cost enforcement.
@@ -75,11 +82,61 @@ func defaultSensitiveResources() []sensitiveResource {
 	}
 }
 
+type clusterScopedResource struct {
+	APIGroup string
+	Resource string
+}
+
+func (cr clusterScopedResource) String() string {
+	return fmt.Sprintf("APIGroup: %s, Resource: %s", cr.APIGroup, cr.Resource)
+}
+
+// MatchesRules returns true when the rule explicitly targets the cluster-scoped
+// resource. Resource wildcards are not taken into account, because they also
+// target namespaced resources.
+func (cr clusterScopedResource) MatchesRules(apiGroups []string, resources []string) bool {
+	apiGroupMatches := slices.ContainsFunc(apiGroups, func(apiGroup string) bool {
+		return apiGroup == cr.APIGroup || apiGroup == "*"
+	})
+	resourceMatches := slices.ContainsFunc(resources, func(resource string) bool {
+		return resource == cr.Resource || strings.HasPrefix(resource, cr.Resource+"/")
+	})
+
+	return apiGroupMatches && resourceMatches
+}
+
+func knownClusterScopedResources() []clusterScopedResource {
+	return []clusterScopedResource{
+		{APIGroup: "", Resource: "namespaces"},
+		{APIGroup: "", Resource: "nodes"},
+		{APIGroup: "", Resource: "persistentvolumes"},
+		{APIGroup: "admissionregistration.k8s.io", Resource: "mutatingwebhookconfigurations"},
+		{APIGroup: "admissionregistration.k8s.io", Resource: "validatingwebhookconfigurations"},
+		{APIGroup: "admissionregistration.k8s.io", Resource: "validatingadmissionpolicies"},
+		{APIGroup: "admissionregistration.k8s.io", Resource: "validatingadmissionpolicybindings"},
+		{APIGroup: "apiextensions.k8s.io", Resource: "customresourcedefinitions"},
+		{APIGroup: "apiregistration.k8s.io", Resource: "apiservices"},
+		{APIGroup: "certificates.k8s.io", Resource: "certificatesigningrequests"},
+		{APIGroup: "networking.k8s.io", Resource: "ingressclasses"},
+		{APIGroup: "node.k8s.io", Resource: "runtimeclasses"},
+		{APIGroup: "rbac.authorization.k8s.io", Resource: "clusterroles"},
+		{APIGroup: "rbac.authorization.k8s.io", Resource: "clusterrolebindings"},
+		{APIGroup: "scheduling.k8s.io", Resource: "priorityclasses"},
+		{APIGroup: "storage.k8s.io", Resource: "csidrivers"},
+		{APIGroup: "storage.k8s.io", Resource: "csinodes"},
+		{APIGroup: "storage.k8s.io", Resource: "storageclasses"},
+		{APIGroup: "storage.k8s.io", Resource: "volumeattachments"},
+	}
+}
+
 func validatePolicyCreate(policy Policy) field.ErrorList {
 	var allErrors field.ErrorList
 
 	allErrors = append(allErrors, validateRulesField(policy)...)
 	allErrors = append(allErrors, validateMatchConditions(policy.GetMatchConditions(), field.NewPath("spec").Child("matchConditions"))...)
+	if err := validateReinvocationPolicy(policy); err != nil {
+		allErrors = append(allErrors, err)
+	}
 	return allErrors
 }
 
@@ -88,6 +145,9 @@ func validatePolicyUpdate(oldPolicy, newPolicy Policy) field.ErrorList {
 
 	allErrors = append(allErrors, validateRulesField(newPolicy)...)
 	allErrors = append(allErrors, validateMatchConditions(newPolicy.GetMatchConditions(), field.NewPath("spec").Child("matchConditions"))...)
+	if err := validateReinvocationPolicy(newPolicy); err != nil {
+		allErrors = append(allErrors, err)
+	}
 	if err := validatePolicyServerField(oldPolicy, newPolicy); err != nil {
 		allErrors = append(allErrors, err)
 	}
@@ -213,6 +273,44 @@ func checkRulesArrayForSensitiveResourcesBeingTargeted(rulesAPIGroups []string,
 	return allErrors
 }
 
+// validateClusterScopedResourcesTargets checks if the rules of a namespaced
+// policy target known cluster-scoped resources. Admission requests for these
+// resources do not carry a namespace, so a namespaced policy never evaluates them.
+func validateClusterScopedResourcesTargets(policy Policy) field.ErrorList {
+	var allErrors field.ErrorList
+
+	rulesField := field.NewPath("spec", "rules")
+	for i, rule := range policy.GetRules() {
+		for _, clusterScopedResource := range knownClusterScopedResources() {
+			if clusterScopedResource.MatchesRules(rule.Rule.APIGroups, rule.Rule.Resources) {
+				allErrors = append(allErrors, field.Forbidden(rulesField.Index(i), fmt.Sprintf("{%s} resources are cluster-scoped and cannot be targeted by a namespaced policy", clusterScopedResource)))
+			}
+		}
+	}
+
+	return allErrors
+}
+
+// validateReinvocationPolicy checks that the reinvocationPolicy is supported by
+// the Kubernetes API server and that only the mutating policies are reinvoked.
+func validateReinvocationPolicy(policy Policy) *field.Error {
+	reinvocationPolicy := policy.GetReinvocationPolicy()
+	if reinvocationPolicy == nil {
+		return nil
+	}
+	path := field.NewPath("spec").Child("reinvocationPolicy")
+
+	supportedReinvocationPolicies := []string{string(admissionregistrationv1.NeverReinvocationPolicy), string(admissionregistrationv1.IfNeededReinvocationPolicy)}
+	if !slices.Contains(supportedReinvocationPolicies, string(*reinvocationPolicy)) {
+		return field.NotSupported(path, *reinvocationPolicy, supportedReinvocationPolicies)
+	}
+	if *reinvocationPolicy == admissionregistrationv1.IfNeededReinvocationPolicy && !policy.IsMutating() {
+		return field.Forbidden(path, "only mutating policies can be reinvoked")
+	}
+
+	return nil
+}
+
 func validatePolicyServerField(oldPolicy, newPolicy Policy) *field.Error {
 	if oldPolicy.GetPolicyServer() != newPolicy.GetPolicyServer() {
 		return field.Forbidden(field.NewPath("spec").Child("policyServer"), "the field is immutable")
@@ -305,3 +403,115 @@ func validateMatchConditionsExpression(expressionStr string, fldPath *field.Path
 	}
 	return allErrors
 }
+
+// matchConditionsVariables returns the variables the Kubernetes API server
+// provides to the match conditions of the admission webhooks.
+func matchConditionsVariables() []string {
+	return []string{"authorizer", "object", "oldObject", "request"}
+}
+
+// celBuiltinIdentifiers returns the identifiers that are not variables: the
+// type denotations and the namespaces of the CEL libraries.
+func celBuiltinIdentifiers() []string {
+	return []string{
+		"bool", "bytes", "double", "dyn", "int", "list", "map", "null_type", "string", "type", "uint",
+		"math", "optional", "sets", "strings",
+	}
+}
+
+// matchConditionsWarnings is a best-effort check warning about the match
+// conditions referencing root identifiers that are not variables available to
+// the match conditions. Such expressions fail at admission time on the
+// Kubernetes API server.
+func matchConditionsWarnings(policy Policy) admission.Warnings {
+	var warnings admission.Warnings
+
+	for i, matchCondition := range policy.GetMatchConditions() {
+		for _, identifier := range unknownRootIdentifiers(matchCondition.Expression) {
+			warnings = append(warnings, fmt.Sprintf("%s: unknown identifier %q, match conditions can only reference the %s variables",
+				field.NewPath("spec").Child("matchConditions").Index(i).Child("expression"),
+				identifier,
+				strings.Join(matchConditionsVariables(), ", ")))
+		}
+	}
+
+	return warnings
+}
+
+// policyGroupsSharingModuleWarnings returns a warning for each member of the
+// policy groups running the module of the given policy. The
+// AdmissionPolicyGroups are looked up in the namespace of the policy, or in
+// all the namespaces for a cluster-wide policy. The members of the policy
+// groups are defined inline, deleting the policy does not break the groups:
+// the warnings let the operator know that the module is still evaluated.
+func policyGroupsSharingModuleWarnings(ctx context.Context, k8sClient client.Client, policy Policy) (admission.Warnings, error) {
+	clusterAdmissionPolicyGroups := &ClusterAdmissionPolicyGroupList{}
+	if err := k8sClient.List(ctx, clusterAdmissionPolicyGroups); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterAdmissionPolicyGroups: %w", err)
+	}
+
+	admissionPolicyGroups := &AdmissionPolicyGroupList{}
+	if err := k8sClient.List(ctx, admissionPolicyGroups, client.InNamespace(policy.GetNamespace())); err != nil {
+		return nil, fmt.Errorf("failed to list AdmissionPolicyGroups: %w", err)
+	}
+
+	var warnings admission.Warnings
+	for i := range clusterAdmissionPolicyGroups.Items {
+		group := &clusterAdmissionPolicyGroups.Items[i]
+		warnings = append(warnings, policyGroupSharingModuleWarnings("ClusterAdmissionPolicyGroup "+group.GetName(), group, policy.GetModule())...)
+	}
+	for i := range admissionPolicyGroups.Items {
+		group := &admissionPolicyGroups.Items[i]
+		warnings = append(warnings, policyGroupSharingModuleWarnings("AdmissionPolicyGroup "+group.GetNamespace()+"/"+group.GetName(), group, policy.GetModule())...)
+	}
+
+	return warnings, nil
+}
+
+// policyGroupSharingModuleWarnings returns a warning for each member of the
+// given policy group running the given module. The members are sorted by
+// name, to return stable warnings.
+func policyGroupSharingModuleWarnings(groupDescription string, group PolicyGroup, module string) admission.Warnings {
+	var warnings admission.Warnings
+
+	members := group.GetPolicyGroupMembersWithContext()
+	for _, name := range slices.Sorted(maps.Keys(members)) {
+		if members[name].Module == module {
+			warnings = append(warnings, fmt.Sprintf("%s runs the same module in its member %q, the policy group is not affected by the deletion", groupDescription, name))
+		}
+	}
+
+	return warnings
+}
+
+// unknownRootIdentifiers returns the sorted root identifiers of the given CEL
+// expression that are neither match conditions variables, nor builtin
+// identifiers, nor comprehension variables. Expressions that cannot be parsed
+// are ignored, their errors are reported by the match conditions validation.
+func unknownRootIdentifiers(expression string) []string {
+	env, err := celgo.NewEnv()
+	if err != nil {
+		return nil
+	}
+	parsed, issues := env.Parse(expression)
+	if issues.Err() != nil {
+		return nil
+	}
+
+	identifiers := sets.New[string]()
+	comprehensionVariables := sets.New[string]()
+	celast.PostOrderVisit(parsed.NativeRep().Expr(), celast.NewExprVisitor(func(e celast.Expr) {
+		switch e.Kind() { //nolint:exhaustive // Only identifiers and comprehensions declare or reference variables
+		case celast.IdentKind:
+			identifiers.Insert(e.AsIdent())
+		case celast.ComprehensionKind:
+			comprehensionVariables.Insert(e.AsComprehension().IterVar(), e.AsComprehension().AccuVar())
+		}
+	}))
+
+	identifiers.Delete(comprehensionVariables.UnsortedList()...)
+	identifiers.Delete(matchConditionsVariables()...)
+	identifiers.Delete(celBuiltinIdentifiers()...)
+
+	return sets.List(identifiers)
+}
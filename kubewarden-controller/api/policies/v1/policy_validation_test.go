@@ -17,10 +17,15 @@ package v1
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestSensitiveResourceMatchRule(t *testing.T) {
@@ -457,6 +462,65 @@ func TestValidateMatchConditionsField(t *testing.T) {
 	}
 }
 
+func TestMatchConditionsWarnings(t *testing.T) {
+	tests := []struct {
+		name             string
+		expression       string
+		expectedWarnings []string
+	}{
+		{
+			"with known variables",
+			"object.metadata.name == 'foo' && oldObject == null && request.operation == 'CREATE'",
+			nil,
+		},
+		{
+			"with authorizer",
+			"authorizer.group('apps').resource('deployments').check('create').allowed()",
+			nil,
+		},
+		{
+			"with comprehension variables",
+			"object.spec.containers.all(container, container.image.startsWith('registry.example.com/'))",
+			nil,
+		},
+		{
+			"with type denotations",
+			"type(object.metadata.labels) == map",
+			nil,
+		},
+		{
+			"with unknown identifiers",
+			"namespaceObject.metadata.name == 'foo' || params.enabled || namespaceObject.metadata.labels.size() > 0",
+			[]string{
+				`spec.matchConditions[0].expression: unknown identifier "namespaceObject", match conditions can only reference the authorizer, object, oldObject, request variables`,
+				`spec.matchConditions[0].expression: unknown identifier "params", match conditions can only reference the authorizer, object, oldObject, request variables`,
+			},
+		},
+		{
+			"with invalid expression",
+			"invalid expression",
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := NewClusterAdmissionPolicyFactory().
+				WithMatchConditions([]admissionregistrationv1.MatchCondition{
+					{
+						Name:       "foo",
+						Expression: test.expression,
+					},
+				}).
+				Build()
+
+			warnings := matchConditionsWarnings(policy)
+
+			require.Equal(t, test.expectedWarnings, []string(warnings))
+		})
+	}
+}
+
 func TestValidatePolicyServerField(t *testing.T) {
 	defaultRules := []admissionregistrationv1.RuleWithOperations{{
 		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.OperationAll},
@@ -596,3 +660,91 @@ func TestValidatePolicyModeField(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateReinvocationPolicy(t *testing.T) {
+	tests := []struct {
+		name                 string
+		mutating             bool
+		reinvocationPolicy   *admissionregistrationv1.ReinvocationPolicyType
+		expectedErrorMessage string // use empty string when no error is expected
+	}{
+		{"not set", false, nil, ""},
+		{"mutating policy reinvoked if needed", true, ptr.To(admissionregistrationv1.IfNeededReinvocationPolicy), ""},
+		{"mutating policy never reinvoked", true, ptr.To(admissionregistrationv1.NeverReinvocationPolicy), ""},
+		{"validating policy never reinvoked", false, ptr.To(admissionregistrationv1.NeverReinvocationPolicy), ""},
+		{"validating policy reinvoked if needed", false, ptr.To(admissionregistrationv1.IfNeededReinvocationPolicy), "spec.reinvocationPolicy: Forbidden: only mutating policies can be reinvoked"},
+		{"unsupported value", true, ptr.To(admissionregistrationv1.ReinvocationPolicyType("Always")), `spec.reinvocationPolicy: Unsupported value: "Always": supported values: "Never", "IfNeeded"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := NewClusterAdmissionPolicyFactory().WithMutating(test.mutating).Build()
+			policy.Spec.ReinvocationPolicy = test.reinvocationPolicy
+
+			err := validateReinvocationPolicy(policy)
+
+			if test.expectedErrorMessage != "" {
+				require.ErrorContains(t, err, test.expectedErrorMessage)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func newPolicyGroupsFakeClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, AddToScheme(scheme))
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func TestPolicyGroupsSharingModuleWarnings(t *testing.T) {
+	policyWithOtherModule := NewAdmissionPolicyFactory().WithNamespace("default").Build()
+	policyWithOtherModule.Spec.Module = "registry://ghcr.io/kubewarden/tests/safe-labels:v0.1.0"
+
+	k8sClient := newPolicyGroupsFakeClient(t,
+		NewClusterAdmissionPolicyGroupFactory().WithName("cluster-group").Build(),
+		NewAdmissionPolicyGroupFactory().WithName("group").WithNamespace("default").Build(),
+		NewAdmissionPolicyGroupFactory().WithName("other-group").WithNamespace("other").Build(),
+	)
+
+	tests := []struct {
+		name             string
+		policy           Policy
+		expectedWarnings []string
+	}{
+		{
+			"cluster-wide policy",
+			NewClusterAdmissionPolicyFactory().Build(),
+			[]string{
+				`ClusterAdmissionPolicyGroup cluster-group runs the same module in its member "pod_privileged", the policy group is not affected by the deletion`,
+				`AdmissionPolicyGroup default/group runs the same module in its member "pod_privileged", the policy group is not affected by the deletion`,
+				`AdmissionPolicyGroup other/other-group runs the same module in its member "pod_privileged", the policy group is not affected by the deletion`,
+			},
+		},
+		{
+			"namespaced policy",
+			NewAdmissionPolicyFactory().WithNamespace("default").Build(),
+			[]string{
+				`ClusterAdmissionPolicyGroup cluster-group runs the same module in its member "pod_privileged", the policy group is not affected by the deletion`,
+				`AdmissionPolicyGroup default/group runs the same module in its member "pod_privileged", the policy group is not affected by the deletion`,
+			},
+		},
+		{
+			"policy running a module not used by the policy groups",
+			policyWithOtherModule,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			warnings, err := policyGroupsSharingModuleWarnings(t.Context(), k8sClient, test.policy)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, test.expectedWarnings, warnings)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyGroupGetModule(t *testing.T) {
+	podPrivileged := "registry://ghcr.io/kubewarden/tests/pod-privileged:v0.2.5"
+	userGroupPSP := "registry://ghcr.io/kubewarden/tests/user-group-psp:v0.4.9"
+
+	clusterGroup := NewClusterAdmissionPolicyGroupFactory().Build()
+	assert.Equal(t, podPrivileged+","+userGroupPSP, clusterGroup.GetModule())
+
+	// The same module used by several members is listed once
+	group := NewAdmissionPolicyGroupFactory().Build()
+	group.Spec.Policies = PolicyGroupMembers{
+		"user_group_psp": {Module: userGroupPSP},
+		"privileged":     {Module: podPrivileged},
+		"pod_privileged": {Module: podPrivileged},
+	}
+	assert.Equal(t, podPrivileged+","+userGroupPSP, group.GetModule())
+
+	group.Spec.Policies = nil
+	assert.Empty(t, group.GetModule())
+}
+
+func TestPolicyGetModules(t *testing.T) {
+	podPrivileged := "registry://ghcr.io/kubewarden/tests/pod-privileged:v0.2.5"
+	userGroupPSP := "registry://ghcr.io/kubewarden/tests/user-group-psp:v0.4.9"
+
+	policy := NewClusterAdmissionPolicyFactory().Build()
+	assert.Equal(t, []string{podPrivileged}, policy.GetModules())
+
+	clusterGroup := NewClusterAdmissionPolicyGroupFactory().Build()
+	assert.Equal(t, []string{podPrivileged, userGroupPSP}, clusterGroup.GetModules())
+
+	// The same module used by several members is listed once
+	group := NewAdmissionPolicyGroupFactory().Build()
+	group.Spec.Policies = PolicyGroupMembers{
+		"user_group_psp": {Module: userGroupPSP},
+		"privileged":     {Module: podPrivileged},
+		"pod_privileged": {Module: podPrivileged},
+	}
+	assert.Equal(t, []string{podPrivileged, userGroupPSP}, group.GetModules())
+
+	group.Spec.Policies = nil
+	assert.Empty(t, group.GetModules())
+}
+
+func TestClusterAdmissionPolicyGroupGetContextAwareResources(t *testing.T) {
+	group := NewClusterAdmissionPolicyGroupFactory().Build()
+	assert.Empty(t, group.GetContextAwareResources())
+
+	group.Spec.Policies["pod_privileged"] = PolicyGroupMemberWithContext{
+		ContextAwareResources: []ContextAwareResource{
+			{APIVersion: "v1", Kind: "Namespace"},
+			{APIVersion: "apps/v1", Kind: "Deployment"},
+		},
+	}
+	group.Spec.Policies["user_group_psp"] = PolicyGroupMemberWithContext{
+		ContextAwareResources: []ContextAwareResource{
+			{APIVersion: "v1", Kind: "Namespace"},
+			{APIVersion: "v1", Kind: "ConfigMap"},
+			{APIVersion: "v1", Kind: "ConfigMap"},
+		},
+	}
+
+	assert.Equal(t, []ContextAwareResource{
+		{APIVersion: "apps/v1", Kind: "Deployment"},
+		{APIVersion: "v1", Kind: "ConfigMap"},
+		{APIVersion: "v1", Kind: "Namespace"},
+	}, group.GetContextAwareResources())
+	assert.True(t, group.IsContextAware())
+}
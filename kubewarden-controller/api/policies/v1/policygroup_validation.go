@@ -3,14 +3,20 @@ package v1
 import (
 	"fmt"
 	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/google/cel-go/cel"
+	celast "github.com/google/cel-go/common/ast"
 	"github.com/google/cel-go/common/decls"
 	"github.com/google/cel-go/common/operators"
 	"github.com/google/cel-go/common/stdlib"
 	"github.com/google/cel-go/common/types"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 // Regex to validate the policy members names.
@@ -29,11 +35,14 @@ var celReservedSymbols = sets.NewString(
 	"var", "void", "while",
 )
 
-func validatePolicyGroupCreate(policyGroup PolicyGroup) field.ErrorList {
+func validatePolicyGroupCreate(policyGroup PolicyGroup, maxMembers int) field.ErrorList {
 	var allErrors field.ErrorList
 
 	allErrors = append(allErrors, validatePolicyCreate(policyGroup)...)
 	allErrors = append(allErrors, validatePolicyGroupMembers(policyGroup)...)
+	if err := validatePolicyGroupMembersCount(nil, policyGroup, maxMembers); err != nil {
+		allErrors = append(allErrors, err)
+	}
 	if err := validatePolicyGroupExpressionField(policyGroup); err != nil {
 		allErrors = append(allErrors, err)
 	}
@@ -44,11 +53,14 @@ func validatePolicyGroupCreate(policyGroup PolicyGroup) field.ErrorList {
 	return allErrors
 }
 
-func validatePolicyGroupUpdate(oldPolicyGroup, newPolicyGroup PolicyGroup) field.ErrorList {
+func validatePolicyGroupUpdate(oldPolicyGroup, newPolicyGroup PolicyGroup, maxMembers int) field.ErrorList {
 	var allErrors field.ErrorList
 
 	allErrors = append(allErrors, validatePolicyUpdate(oldPolicyGroup, newPolicyGroup)...)
 	allErrors = append(allErrors, validatePolicyGroupMembers(newPolicyGroup)...)
+	if err := validatePolicyGroupMembersCount(oldPolicyGroup, newPolicyGroup, maxMembers); err != nil {
+		allErrors = append(allErrors, err)
+	}
 	if err := validatePolicyGroupExpressionField(newPolicyGroup); err != nil {
 		allErrors = append(allErrors, err)
 	}
@@ -84,6 +96,22 @@ func validatePolicyGroupMembers(policyGroup PolicyGroup) field.ErrorList {
 	return allErrors
 }
 
+// validatePolicyGroupMembersCount validates that a policy group does not have
+// more than maxMembers members. A non-positive maxMembers disables the limit.
+// The groups created before the limit was lowered can still be updated, as
+// long as their number of members does not grow.
+func validatePolicyGroupMembersCount(oldPolicyGroup, newPolicyGroup PolicyGroup, maxMembers int) *field.Error {
+	count := len(newPolicyGroup.GetPolicyGroupMembersWithContext())
+	if maxMembers <= 0 || count <= maxMembers {
+		return nil
+	}
+	if oldPolicyGroup != nil && count <= len(oldPolicyGroup.GetPolicyGroupMembersWithContext()) {
+		return nil
+	}
+
+	return field.TooMany(field.NewPath("spec").Child("policies"), count, maxMembers)
+}
+
 // validatePolicyGroupExpressionField validates that the expression is a valid CEL expression that evaluates to a boolean.
 // Only the following operators are allowed: equals, not equals, logical or, logical and, and logical not.
 // Policy members are imported as custom functions that take no arguments and return a boolean.
@@ -128,7 +156,16 @@ func validatePolicyGroupExpressionField(policyGroup PolicyGroup) *field.Error {
 		return field.InternalError(expressionField, fmt.Errorf("error creating CEL environment: %w", err))
 	}
 
-	ast, issues := env.Compile(policyGroup.GetExpression())
+	parsedAST, issues := env.Parse(policyGroup.GetExpression())
+	if issues != nil && issues.Err() != nil {
+		return field.Invalid(expressionField, policyGroup.GetExpression(), fmt.Sprintf("parsing failed: %v", issues.Err()))
+	}
+	if unknownMembers := unknownPolicyGroupMembers(parsedAST.NativeRep().Expr(), policyGroup.GetPolicyGroupMembersWithContext()); len(unknownMembers) > 0 {
+		return field.Invalid(expressionField, policyGroup.GetExpression(),
+			fmt.Sprintf("references policies not defined in spec.policies: %s", strings.Join(unknownMembers, ", ")))
+	}
+
+	ast, issues := env.Check(parsedAST)
 	if issues != nil && issues.Err() != nil {
 		return field.Invalid(expressionField, policyGroup.GetExpression(), fmt.Sprintf("compilation failed: %v", issues.Err()))
 	}
@@ -138,3 +175,146 @@ func validatePolicyGroupExpressionField(policyGroup PolicyGroup) *field.Error {
 
 	return nil
 }
+
+// unknownPolicyGroupMembers returns the sorted names of the policies
+// referenced by the expression, either called or used as identifiers, that
+// are not members of the group.
+func unknownPolicyGroupMembers(expr celast.Expr, members PolicyGroupMembersWithContext) []string {
+	unknownMembers := sets.New[string]()
+	celast.PreOrderVisit(expr, celast.NewExprVisitor(func(e celast.Expr) {
+		var name string
+		switch e.Kind() { //nolint:exhaustive // Only the calls and the identifiers can reference a policy
+		case celast.CallKind:
+			if e.AsCall().IsMemberFunction() {
+				return
+			}
+			name = e.AsCall().FunctionName()
+			if _, isOperator := operators.FindReverse(name); isOperator || strings.HasPrefix(name, "@") {
+				return
+			}
+		case celast.IdentKind:
+			name = e.AsIdent()
+		default:
+			return
+		}
+		if _, ok := members[name]; !ok {
+			unknownMembers.Insert(name)
+		}
+	}))
+
+	return sets.List(unknownMembers)
+}
+
+// labelConstraint is the set of requirements put by label selectors on the
+// value of a single label.
+type labelConstraint struct {
+	present  bool
+	absent   bool
+	allowed  sets.Set[string]
+	excluded sets.Set[string]
+}
+
+func (c *labelConstraint) addRequirement(operator metav1.LabelSelectorOperator, values []string) {
+	switch operator {
+	case metav1.LabelSelectorOpIn:
+		c.present = true
+		if c.allowed == nil {
+			c.allowed = sets.New(values...)
+		} else {
+			c.allowed = c.allowed.Intersection(sets.New(values...))
+		}
+	case metav1.LabelSelectorOpNotIn:
+		c.excluded = c.excluded.Union(sets.New(values...))
+	case metav1.LabelSelectorOpExists:
+		c.present = true
+	case metav1.LabelSelectorOpDoesNotExist:
+		c.absent = true
+	}
+}
+
+// contradictory returns whether no label value satisfies the constraint.
+func (c *labelConstraint) contradictory() bool {
+	if c.present && c.absent {
+		return true
+	}
+
+	return c.allowed != nil && c.allowed.Difference(c.excluded).Len() == 0
+}
+
+// contradictoryLabels returns the sorted labels whose requirements, combined
+// across all the given selectors, cannot be satisfied by any object.
+func contradictoryLabels(selectors ...*metav1.LabelSelector) []string {
+	constraints := make(map[string]*labelConstraint)
+	constraint := func(key string) *labelConstraint {
+		if _, ok := constraints[key]; !ok {
+			constraints[key] = &labelConstraint{excluded: sets.New[string]()}
+		}
+		return constraints[key]
+	}
+
+	for _, selector := range selectors {
+		if selector == nil {
+			continue
+		}
+		for key, value := range selector.MatchLabels {
+			constraint(key).addRequirement(metav1.LabelSelectorOpIn, []string{value})
+		}
+		for _, expression := range selector.MatchExpressions {
+			constraint(expression.Key).addRequirement(expression.Operator, expression.Values)
+		}
+	}
+
+	labels := sets.New[string]()
+	for key, c := range constraints {
+		if c.contradictory() {
+			labels.Insert(key)
+		}
+	}
+
+	return sets.List(labels)
+}
+
+// targetsNamespaces returns whether the rules match the Namespace resource.
+func targetsNamespaces(rules []admissionregistrationv1.RuleWithOperations) bool {
+	for _, rule := range rules {
+		if (slices.Contains(rule.APIGroups, "") || slices.Contains(rule.APIGroups, "*")) &&
+			(slices.Contains(rule.Resources, "namespaces") || slices.Contains(rule.Resources, "*")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// policyGroupSelectorsWarnings warns about the selectors of the policy group
+// that are strictly contradictory, making the policy group never evaluate
+// the requests they should restrict. Namespace objects are matched by both
+// the namespace selector and the object selector, hence the two selectors
+// are checked together when the policy group targets namespaces.
+func policyGroupSelectorsWarnings(policyGroup PolicyGroup) admission.Warnings {
+	var warnings admission.Warnings
+
+	namespaceSelectorField := field.NewPath("spec").Child("namespaceSelector")
+	objectSelectorField := field.NewPath("spec").Child("objectSelector")
+
+	namespaceSelectorLabels := contradictoryLabels(policyGroup.GetNamespaceSelector())
+	if len(namespaceSelectorLabels) != 0 {
+		warnings = append(warnings, fmt.Sprintf("%s: the requirements on the labels %s are contradictory, the policy group never matches any namespace",
+			namespaceSelectorField, strings.Join(namespaceSelectorLabels, ", ")))
+	}
+	objectSelectorLabels := contradictoryLabels(policyGroup.GetObjectSelector())
+	if len(objectSelectorLabels) != 0 {
+		warnings = append(warnings, fmt.Sprintf("%s: the requirements on the labels %s are contradictory, the policy group never matches any object",
+			objectSelectorField, strings.Join(objectSelectorLabels, ", ")))
+	}
+	if len(warnings) != 0 || !targetsNamespaces(policyGroup.GetRules()) {
+		return warnings
+	}
+
+	if labels := contradictoryLabels(policyGroup.GetNamespaceSelector(), policyGroup.GetObjectSelector()); len(labels) != 0 {
+		warnings = append(warnings, fmt.Sprintf("%s: the requirements on the labels %s contradict %s, the policy group never matches Namespace objects",
+			objectSelectorField, strings.Join(labels, ", "), namespaceSelectorField))
+	}
+
+	return warnings
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -130,6 +131,64 @@ func TestValidatePolicyGroupExpressionField(t *testing.T) {
 			},
 			`spec.expression: Invalid value: "2 > 1": compilation failed`,
 		},
+		{
+			"with syntax error",
+			&ClusterAdmissionPolicyGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "testing-cluster-policy-group",
+				},
+				Spec: ClusterAdmissionPolicyGroupSpec{
+					ClusterPolicyGroupSpec: ClusterPolicyGroupSpec{
+						GroupSpec: GroupSpec{
+							Expression: "policy1() &&",
+							Message:    "This is a test policy",
+						},
+						Policies: PolicyGroupMembersWithContext{
+							"policy1": {
+								PolicyGroupMember: PolicyGroupMember{
+									Module: "ghcr.io/kubewarden/tests/user-group-psp:v0.4.9",
+								},
+							},
+							"policy2": {
+								PolicyGroupMember: PolicyGroupMember{
+									Module: "ghcr.io/kubewarden/tests/safe-labels:v1.0.0",
+								},
+							},
+						},
+					},
+				},
+			},
+			`spec.expression: Invalid value: "policy1() &&": parsing failed: ERROR: <input>:1:13: Syntax error`,
+		},
+		{
+			"with unknown policies",
+			&ClusterAdmissionPolicyGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "testing-cluster-policy-group",
+				},
+				Spec: ClusterAdmissionPolicyGroupSpec{
+					ClusterPolicyGroupSpec: ClusterPolicyGroupSpec{
+						GroupSpec: GroupSpec{
+							Expression: "policy1() && (policy3() || !unknown)",
+							Message:    "This is a test policy",
+						},
+						Policies: PolicyGroupMembersWithContext{
+							"policy1": {
+								PolicyGroupMember: PolicyGroupMember{
+									Module: "ghcr.io/kubewarden/tests/user-group-psp:v0.4.9",
+								},
+							},
+							"policy2": {
+								PolicyGroupMember: PolicyGroupMember{
+									Module: "ghcr.io/kubewarden/tests/safe-labels:v1.0.0",
+								},
+							},
+						},
+					},
+				},
+			},
+			`spec.expression: Invalid value: "policy1() && (policy3() || !unknown)": references policies not defined in spec.policies: policy3, unknown`,
+		},
 	}
 
 	for _, test := range tests {
@@ -341,3 +400,112 @@ func TestValidatePolicyGroupMembers(t *testing.T) {
 		})
 	}
 }
+
+func TestPolicyGroupSelectorsWarnings(t *testing.T) {
+	podRules := []admissionregistrationv1.RuleWithOperations{{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		Rule: admissionregistrationv1.Rule{
+			APIGroups:   []string{""},
+			APIVersions: []string{"v1"},
+			Resources:   []string{"pods"},
+		},
+	}}
+	namespaceRules := []admissionregistrationv1.RuleWithOperations{{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		Rule: admissionregistrationv1.Rule{
+			APIGroups:   []string{""},
+			APIVersions: []string{"v1"},
+			Resources:   []string{"namespaces"},
+		},
+	}}
+
+	tests := []struct {
+		name              string
+		rules             []admissionregistrationv1.RuleWithOperations
+		namespaceSelector *metav1.LabelSelector
+		objectSelector    *metav1.LabelSelector
+		expectedWarnings  []string
+	}{
+		{
+			"without selectors",
+			podRules,
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"with consistent selectors",
+			namespaceRules,
+			&metav1.LabelSelector{
+				MatchLabels: map[string]string{"environment": "production"},
+			},
+			&metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "environment", Operator: metav1.LabelSelectorOpIn, Values: []string{"production", "staging"}},
+					{Key: "team", Operator: metav1.LabelSelectorOpExists},
+				},
+			},
+			nil,
+		},
+		{
+			"with contradictory namespace selector",
+			podRules,
+			&metav1.LabelSelector{
+				MatchLabels: map[string]string{"environment": "production"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "environment", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"production"}},
+				},
+			},
+			nil,
+			[]string{"spec.namespaceSelector: the requirements on the labels environment are contradictory, the policy group never matches any namespace"},
+		},
+		{
+			"with contradictory object selector",
+			podRules,
+			nil,
+			&metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "team", Operator: metav1.LabelSelectorOpExists},
+					{Key: "team", Operator: metav1.LabelSelectorOpDoesNotExist},
+				},
+			},
+			[]string{"spec.objectSelector: the requirements on the labels team are contradictory, the policy group never matches any object"},
+		},
+		{
+			"with selectors contradicting each other on pods",
+			podRules,
+			&metav1.LabelSelector{
+				MatchLabels: map[string]string{"environment": "production"},
+			},
+			&metav1.LabelSelector{
+				MatchLabels: map[string]string{"environment": "staging"},
+			},
+			nil,
+		},
+		{
+			"with selectors contradicting each other on namespaces",
+			namespaceRules,
+			&metav1.LabelSelector{
+				MatchLabels: map[string]string{"environment": "production"},
+			},
+			&metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "environment", Operator: metav1.LabelSelectorOpIn, Values: []string{"staging", "development"}},
+				},
+			},
+			[]string{"spec.objectSelector: the requirements on the labels environment contradict spec.namespaceSelector, the policy group never matches Namespace objects"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policyGroup := NewClusterAdmissionPolicyGroupFactory().WithRules(test.rules).Build()
+			policyGroup.Spec.NamespaceSelector = test.namespaceSelector
+			policyGroup.Spec.ObjectSelector = test.objectSelector
+
+			warnings := policyGroupSelectorsWarnings(policyGroup)
+
+			require.Equal(t, test.expectedWarnings, []string(warnings))
+		})
+	}
+}
@@ -17,20 +17,35 @@ limitations under the License.
 package v1
 
 import (
+	"slices"
+	"strconv"
+
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 )
 
 // PolicyServerSecurity defines securityContext configuration to be used in the Policy Server workload.
 type PolicyServerSecurity struct {
-	// securityContext definition to be used in the policy server container
+	// securityContext definition to be used in the policy server container.
+	// When not set, the controller uses a restricted security context, the
+	// seccomp profile being inherited from the Pod.
 	// +optional
 	Container *corev1.SecurityContext `json:"container,omitempty"`
-	// podSecurityContext definition to be used in the policy server Pod
+	// podSecurityContext definition to be used in the policy server Pod.
+	// When not set, the controller uses a security context with the
+	// RuntimeDefault seccomp profile.
 	// +optional
 	Pod *corev1.PodSecurityContext `json:"pod,omitempty"`
+	// seccompProfile to be used in the policy server Pod. It overrides the
+	// seccompProfile of the pod securityContext. Use the Localhost type
+	// together with localhostProfile to load a profile shipped on the
+	// nodes.
+	// +optional
+	SeccompProfile *corev1.SeccompProfile `json:"seccompProfile,omitempty"`
 }
 
 // PolicyServerSpec defines the desired state of PolicyServer.
@@ -62,16 +77,123 @@ type PolicyServerSpec struct {
 	// +optional
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
+	// LogLevel is the log level of the policy server, one of trace, debug,
+	// info, warn or error. The policy server default level is used when
+	// not set.
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// Workers is the number of worker threads evaluating the policies in
+	// each policy server pod. The policy server does not take the CPU limit
+	// of its container into account: when not set, it defaults to the CPU
+	// limit of the Policy Server rounded up, if any, or to the number of
+	// CPUs of the node otherwise.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Workers *int32 `json:"workers,omitempty"`
+
+	// List of sources to populate environment variables in the container,
+	// e.g. a ConfigMap holding the common policy server settings. The
+	// ConfigMaps and Secrets must be defined in the same namespace of the
+	// policy server deployment. The variables defined by Env, and the ones
+	// set by the controller, e.g. the ports, take precedence. The controller
+	// does not watch the referenced objects: the policy server pods must be
+	// restarted to load their changes, e.g. with
+	// `kubectl rollout restart deployment`.
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// FeatureFlags toggles the experimental and debugging features of the
+	// policy server. The keys are the feature names, one of enable-pprof,
+	// continue-on-errors or disable-timeout-protection, the values are
+	// "true" or "false". Unknown features are ignored with a warning. The
+	// features weakening the security of the policy server are subject to
+	// the same restrictions of the corresponding environment variables.
+	// +optional
+	FeatureFlags map[string]string `json:"featureFlags,omitempty"`
+
 	// Name of the service account associated with the policy server.
 	// Namespace service account will be used if not specified.
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 
+	// DedicatedServiceAccount makes the controller create a ServiceAccount
+	// for the policy server, owned by the PolicyServer. A ClusterRole named
+	// kubewarden-policy-server-<name> grants the ServiceAccount read access
+	// only to the context-aware resources of the policies bound to the
+	// policy server. The controller is allowed to bind and escalate only the
+	// ClusterRoles listed in its own RBAC rules, the default one being the
+	// one of the "default" policy server: the ClusterRole names of the other
+	// policy servers must be added to the controller RBAC rules.
+	// It cannot be used together with ServiceAccountName.
+	// +optional
+	DedicatedServiceAccount bool `json:"dedicatedServiceAccount,omitempty"`
+
+	// DefaultBackgroundAudit is the backgroundAudit of the policies bound to
+	// the policy server that do not set it. The policies setting
+	// backgroundAudit keep their own value. Defaults to true.
+	// +optional
+	DefaultBackgroundAudit *bool `json:"defaultBackgroundAudit,omitempty"`
+
+	// DefaultPolicyTimeoutSeconds is the webhook timeout given to the
+	// policies bound to the policy server that do not set timeoutSeconds.
+	// The value set by a policy always wins. The timeout value must be
+	// between 1 and 30 seconds.
+	// +optional
+	DefaultPolicyTimeoutSeconds *int32 `json:"defaultPolicyTimeoutSeconds,omitempty"`
+
+	// ServiceAccountToken makes the policy server authenticate against the
+	// Kubernetes API server with a projected service account token bound to
+	// the given audience and expiration, instead of the automatically
+	// mounted service account token. The Kubernetes API server must accept
+	// the audience.
+	// +optional
+	ServiceAccountToken *ServiceAccountTokenProjection `json:"serviceAccountToken,omitempty"`
+
+	// AutomountServiceAccountToken tells whether the service account token
+	// is mounted in the policy server pods. Disable it to reduce the attack
+	// surface when the policies do not need to access the Kubernetes API
+	// server. The token is mounted anyway while a context-aware policy is
+	// bound to the Policy Server, and a warning event is recorded. It is
+	// ignored when serviceAccountToken is set.
+	// +optional
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+
+	// ProjectedToken mounts in the policy server container a service account
+	// token bound to the given audience, e.g. to let the policies
+	// authenticate against an external service. The token is mounted at
+	// /var/run/secrets/tokens/token, independently of the token used to
+	// reach the Kubernetes API server.
+	// +optional
+	ProjectedToken *ServiceAccountTokenProjection `json:"projectedToken,omitempty"`
+
+	// CertificateSecret is the name of a Secret in the controller namespace
+	// holding the certificate used by the policy server to serve TLS, e.g.
+	// issued by cert-manager or by a corporate PKI. The Secret must contain
+	// the tls.crt, tls.key and ca.crt keys. The tls.crt certificate must be
+	// signed by ca.crt and include the DNS name of the policy server Service,
+	// policy-server-<name>.<namespace>.svc, among its SANs. The ca.crt
+	// certificate is injected as CA bundle in the webhook configurations of
+	// the policies bound to the policy server, and refreshed when the Secret
+	// changes. When set, the controller does not generate nor rotate the
+	// policy server certificate, the certificate is managed externally.
+	// +optional
+	CertificateSecret string `json:"certificateSecret,omitempty"`
+
 	// Name of ImagePullSecret secret in the same namespace, used for pulling
 	// policies from repositories.
+	// Deprecated: use ImagePullSecrets instead.
 	// +optional
 	ImagePullSecret string `json:"imagePullSecret,omitempty"`
 
+	// ImagePullSecrets is a list of secrets of type
+	// kubernetes.io/dockerconfigjson in the same namespace, used for pulling
+	// the policy server image and the policies from repositories. The
+	// credentials of all the secrets are merged, when more secrets provide
+	// credentials for the same registry the first one wins.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
 	// List of insecure URIs to policy repositories. The `insecureSources`
 	// content format corresponds with the contents of the `insecure_sources`
 	// key in `sources.yaml`. Reference for `sources.yaml` is found in the
@@ -90,10 +212,20 @@ type PolicyServerSpec struct {
 
 	// Name of VerificationConfig configmap in the same namespace, containing
 	// Sigstore verification configuration. The configuration must be under a
-	// key named verification-config in the Configmap.
+	// key named verification-config in the Configmap. The configuration is
+	// validated when the PolicyServer is created or updated.
 	// +optional
 	VerificationConfig string `json:"verificationConfig,omitempty"`
 
+	// Name of the Secret in the same namespace containing the Sigstore
+	// verification configuration, for configurations that should not be
+	// stored in a ConfigMap. The configuration must be under a key named
+	// verification-config in the Secret. It cannot be set together with
+	// VerificationConfig. The configuration is validated when the
+	// PolicyServer is created or updated.
+	// +optional
+	VerificationConfigSecret string `json:"verificationConfigSecret,omitempty"`
+
 	// Security configuration to be used in the Policy Server workload.
 	// The field allows different configurations for the pod and containers.
 	// If set for the containers, this configuration will not be used in
@@ -128,6 +260,184 @@ type PolicyServerSpec struct {
 	// remain unchanged, but new pods that reference it cannot be created.
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// RevisionHistoryLimit is the number of old ReplicaSets of the policy
+	// server Deployment to retain to allow rollback. When not set, the
+	// controller keeps 3 old ReplicaSets.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// ProgressDeadlineSeconds is the maximum time in seconds for a rollout
+	// of the policy server Deployment to make progress before it is
+	// considered stalled, e.g. because the image cannot be pulled. A stalled
+	// rollout is reported by the DeploymentProgressing condition. When not
+	// set, the controller uses 600 seconds.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// HeadlessService creates the policy server Service as a headless
+	// Service (`clusterIP: None`), allowing clients to address the policy
+	// server pods directly. Note that the Kubernetes API server requires a
+	// Service with a ClusterIP to route admission requests to the policy
+	// server.
+	// +optional
+	HeadlessService bool `json:"headlessService,omitempty"`
+
+	// ServiceType is the type of the policy server Service. Defaults to
+	// ClusterIP. A headless Service must be of type ClusterIP. The
+	// LoadBalancer type exposes the policy server outside of the cluster and
+	// is rejected unless allowed by the controller configuration.
+	// +optional
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// ServiceAnnotations are added to the policy server Service, e.g. to
+	// configure an internal load balancer or the service mesh. The
+	// annotations removed from the field are removed from the Service, the
+	// annotations set on the Service by other tools are preserved.
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+
+	// Strategy is the deployment strategy used to replace the old policy
+	// server pods by new ones. When not set, the RollingUpdate strategy with
+	// the Kubernetes defaults is used. The Recreate strategy cannot be used
+	// together with MinAvailable or MaxUnavailable.
+	// +optional
+	Strategy *appsv1.DeploymentStrategy `json:"strategy,omitempty"`
+
+	// RuntimeClassName refers to a RuntimeClass object to be used to run the
+	// policy server pods, e.g. to run them in a sandboxed runtime like
+	// gVisor. When not set, the default container runtime is used.
+	// More info: https://kubernetes.io/docs/concepts/containers/runtime-class/
+	// +optional
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// NodeSelector is a selector which must match a node's labels for the
+	// policy server pods to be scheduled on that node.
+	// More info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// NodeName pins all the policy server pods to the given node, bypassing
+	// the scheduler. It is meant for short-lived debugging sessions only: the
+	// pods are not rescheduled when the node is unavailable, so the Policy
+	// Server is not highly available. Default empty.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// HostAliases is an optional list of hosts and IPs that will be injected
+	// into the policy server pods' hosts file. Useful to reach registries
+	// whose hostnames cannot be resolved by the cluster DNS.
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// DNSPolicy sets the DNS policy of the policy server pods. When not set,
+	// the Kubernetes default (ClusterFirst) is used.
+	// +optional
+	DNSPolicy *corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+
+	// DNSConfig specifies the DNS parameters of the policy server pods. It
+	// can only be set when DNSPolicy is None.
+	// +optional
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// ReadinessGates are additional conditions evaluated for the readiness
+	// of the policy server pods, e.g. set by a network controller once the
+	// pods are registered in a service mesh.
+	// More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle/#pod-readiness-gate
+	// +optional
+	ReadinessGates []corev1.PodReadinessGate `json:"readinessGates,omitempty"`
+
+	// PreStopSleepSeconds delays the termination of the policy server
+	// container by the given number of seconds, with a preStop sleep hook.
+	// It lets the pod endpoint be removed from the Service before the policy
+	// server stops serving, avoiding failed admission requests during the
+	// rollouts. The sleep counts against the termination grace period of
+	// the pods, 30 seconds: the policy server is killed without shutting
+	// down gracefully when the sleep lasts longer. When not set, or zero, no
+	// hook is configured. Requires Kubernetes 1.30 or newer.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	PreStopSleepSeconds *int32 `json:"preStopSleepSeconds,omitempty"`
+
+	// InitContainers are run in the policy server pods before the policy
+	// server container starts, e.g. to pre-pull the policies into the policy
+	// store. The volumes mounted by the policy server container are also
+	// mounted in the init containers, unless they already mount a volume
+	// with the same name or at the same path.
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// Probes configures the readiness and the liveness probes of the policy
+	// server container.
+	// +optional
+	Probes ProbesConfiguration `json:"probes,omitempty"`
+}
+
+// ProbesConfiguration defines the probes of the policy server container. The
+// readiness and the liveness probes are configured independently, since
+// readiness often needs a higher failure tolerance than liveness.
+type ProbesConfiguration struct {
+	// Readiness configures the readiness probe of the policy server
+	// container. When not set, the Kubernetes defaults are used.
+	// +optional
+	Readiness *ProbeConfiguration `json:"readiness,omitempty"`
+
+	// Liveness configures the liveness probe of the policy server container.
+	// The liveness probe checks the same endpoint of the readiness probe and
+	// it is added only when this field is set. Its successThreshold must be 1.
+	// +optional
+	Liveness *ProbeConfiguration `json:"liveness,omitempty"`
+}
+
+// ProbeConfiguration defines the parameters of a probe of the policy server
+// container. The parameters not set use the Kubernetes defaults.
+type ProbeConfiguration struct {
+	// InitialDelaySeconds is the number of seconds after the container has
+	// started before the probe is initiated.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// PeriodSeconds is how often, in seconds, to perform the probe.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// TimeoutSeconds is the number of seconds after which the probe times
+	// out.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// SuccessThreshold is the minimum consecutive successes for the probe to
+	// be considered successful after having failed.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+
+	// FailureThreshold is the minimum consecutive failures for the probe to
+	// be considered failed after having succeeded.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// ServiceAccountTokenProjection describes a projected service account token
+// mounted in the policy server container.
+type ServiceAccountTokenProjection struct {
+	// Audience is the intended audience of the token. It must not contain
+	// whitespaces, and must be an absolute URL when it has a scheme.
+	// +kubebuilder:validation:MinLength=1
+	Audience string `json:"audience"`
+	// ExpirationSeconds is the requested duration of validity of the token.
+	// The kubelet rotates the token before it expires.
+	// +optional
+	// +kubebuilder:default=3600
+	// +kubebuilder:validation:Minimum=600
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
 }
 
 type ReconciliationTransitionReason string
@@ -137,6 +447,25 @@ const (
 	ReconciliationFailed ReconciliationTransitionReason = "ReconciliationFailed"
 	// ReconciliationSucceeded represents a reconciliation success.
 	ReconciliationSucceeded ReconciliationTransitionReason = "ReconciliationSucceeded"
+	// CertificateExpiring represents a serving certificate expiring within
+	// the configured threshold.
+	CertificateExpiring ReconciliationTransitionReason = "CertificateExpiring"
+	// CertificateNotExpiring represents a serving certificate not expiring
+	// within the configured threshold.
+	CertificateNotExpiring ReconciliationTransitionReason = "CertificateNotExpiring"
+	// PodsScheduled represents the policy server pods being created and
+	// scheduled.
+	PodsScheduled ReconciliationTransitionReason = "PodsScheduled"
+	// PodsCreationFailed represents the policy server pods not being
+	// created, e.g. because of a ResourceQuota.
+	PodsCreationFailed ReconciliationTransitionReason = "PodsCreationFailed"
+	// PodsUnschedulable represents a policy server pod not being scheduled,
+	// e.g. because no node has enough resources to satisfy its requests.
+	PodsUnschedulable ReconciliationTransitionReason = "PodsUnschedulable"
+	// NamespaceNotAllowed represents an AdmissionPolicy not being served
+	// because its namespace is not in the namespace allow-list of the
+	// controller.
+	NamespaceNotAllowed ReconciliationTransitionReason = "NamespaceNotAllowed"
 )
 
 type PolicyServerConditionType string
@@ -160,6 +489,27 @@ const (
 	// PolicyServerPodDisruptionBudgetReconciled represents the condition of the
 	// Policy Server PodDisruptionBudget reconciliation.
 	PolicyServerPodDisruptionBudgetReconciled PolicyServerConditionType = "PodDisruptionBudgetReconciled"
+	// PolicyServerServiceAccountReconciled represents the condition of the
+	// Policy Server dedicated ServiceAccount and RBAC reconciliation.
+	PolicyServerServiceAccountReconciled PolicyServerConditionType = "ServiceAccountReconciled"
+	// PolicyServerCertificateValid represents whether the Policy Server
+	// serving certificate is valid beyond the configured expiration
+	// threshold. It is set to false when the certificate is about to expire.
+	PolicyServerCertificateValid PolicyServerConditionType = "CertificateValid"
+	// PolicyServerDeploymentProgressing mirrors the Progressing condition of
+	// the Policy Server Deployment. It is set to false with the
+	// ProgressDeadlineExceeded reason when a rollout is stalled.
+	PolicyServerDeploymentProgressing PolicyServerConditionType = "DeploymentProgressing"
+	// PolicyServerPodsSchedulable represents whether the Policy Server pods
+	// can be created and scheduled. It is set to false, with the message of
+	// the underlying failure, when the pods are rejected by a ResourceQuota
+	// or when a pod cannot be scheduled.
+	PolicyServerPodsSchedulable PolicyServerConditionType = "PodsSchedulable"
+	// PolicyServerReconciliationPaused represents the condition of the
+	// Policy Server reconciliation being paused by the kubewarden.io/paused
+	// annotation. The condition is set only while the reconciliation is
+	// paused.
+	PolicyServerReconciliationPaused PolicyServerConditionType = "ReconciliationPaused"
 )
 
 // PolicyServerStatus defines the observed state of PolicyServer.
@@ -174,6 +524,45 @@ type PolicyServerStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions"`
+	// ObservedImages lists the images, including their digest, run by the
+	// policy server container of the running Policy Server pods. More than
+	// one image is listed while a rollout is in progress.
+	// +optional
+	ObservedImages []string `json:"observedImages,omitempty"`
+	// CertificateExpiresAt is the expiration time of the certificate
+	// currently used by the Policy Server to serve TLS.
+	// +optional
+	CertificateExpiresAt *metav1.Time `json:"certificateExpiresAt,omitempty"`
+	// ConfigChecksum is the checksum of the content of the Policy Server
+	// ConfigMap. It is also set as the kubewarden/config-checksum annotation
+	// of the Policy Server pods, which are rolled out only when it changes.
+	// +optional
+	ConfigChecksum string `json:"configChecksum,omitempty"`
+	// Policies summarizes the status of the policies bound to the Policy
+	// Server.
+	// +optional
+	Policies *PolicyServerPoliciesSummary `json:"policies,omitempty"`
+	// RecommendedRequests is the sum of the resource hints declared by the
+	// policies bound to the Policy Server. It is a sizing recommendation
+	// for the requests of the Policy Server, it is not applied
+	// automatically.
+	// +optional
+	RecommendedRequests corev1.ResourceList `json:"recommendedRequests,omitempty"`
+}
+
+// PolicyServerPoliciesSummary counts the policies bound to a Policy Server
+// in each status.
+type PolicyServerPoliciesSummary struct {
+	// Total is the number of policies bound to the Policy Server.
+	Total int `json:"total"`
+	// Active is the number of policies served by the Policy Server.
+	Active int `json:"active"`
+	// Pending is the number of policies waiting for the Policy Server to
+	// serve them.
+	Pending int `json:"pending"`
+	// Scheduled is the number of policies waiting for the Policy Server to
+	// be created.
+	Scheduled int `json:"scheduled"`
 }
 
 //+kubebuilder:object:root=true
@@ -181,6 +570,8 @@ type PolicyServerStatus struct {
 //+kubebuilder:resource:scope=Cluster,shortName=ps
 //+kubebuilder:printcolumn:name="Replicas",type=string,JSONPath=`.spec.replicas`,description="Policy Server replicas"
 //+kubebuilder:printcolumn:name="Image",type=string,JSONPath=`.spec.image`,description="Policy Server image"
+//+kubebuilder:printcolumn:name="Active Policies",type=integer,JSONPath=`.status.policies.active`,description="Active policies bound to the Policy Server"
+//+kubebuilder:printcolumn:name="Policies",type=integer,JSONPath=`.status.policies.total`,description="Policies bound to the Policy Server"
 //+kubebuilder:storageversion
 
 // PolicyServer is the Schema for the policyservers API.
@@ -193,7 +584,88 @@ type PolicyServer struct {
 }
 
 func (ps *PolicyServer) NameWithPrefix() string {
-	return "policy-server-" + ps.Name
+	return constants.PolicyServerNamePrefix + ps.Name
+}
+
+// DefaultPolicyServerContainerSecurityContext returns the security context
+// of the policy server container used when the PolicyServer does not set
+// one.
+func DefaultPolicyServerContainerSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		ReadOnlyRootFilesystem:   ptr.To(true),
+		Privileged:               ptr.To(false),
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities: &corev1.Capabilities{
+			Add:  []corev1.Capability{},
+			Drop: []corev1.Capability{"ALL"},
+		},
+		RunAsNonRoot: ptr.To(true),
+	}
+}
+
+// DefaultPolicyServerPodSecurityContext returns the security context of the
+// policy server pods used when the PolicyServer does not set one.
+func DefaultPolicyServerPodSecurityContext() *corev1.PodSecurityContext {
+	return &corev1.PodSecurityContext{
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+// ImagePullSecretNames returns the names of all the image pull secrets
+// referenced by the PolicyServer, starting with the deprecated
+// ImagePullSecret field. Duplicated and empty names are skipped.
+func (ps *PolicyServer) ImagePullSecretNames() []string {
+	names := []string{}
+	if ps.Spec.ImagePullSecret != "" {
+		names = append(names, ps.Spec.ImagePullSecret)
+	}
+	for _, secret := range ps.Spec.ImagePullSecrets {
+		if secret.Name != "" && !slices.Contains(names, secret.Name) {
+			names = append(names, secret.Name)
+		}
+	}
+
+	return names
+}
+
+// IsBackgroundAuditEnabled returns whether the given policy, bound to the
+// PolicyServer, is evaluated during the audit checks: the backgroundAudit of
+// the policy when set, the DefaultBackgroundAudit of the PolicyServer
+// otherwise.
+func (ps *PolicyServer) IsBackgroundAuditEnabled(policy Policy) bool {
+	return ptr.Deref(policy.GetBackgroundAudit(), ptr.Deref(ps.Spec.DefaultBackgroundAudit, true))
+}
+
+// ListenPort returns the port the policy server pods serve the admission
+// requests on, which can be overridden with spec.env.
+func (ps *PolicyServer) ListenPort() int {
+	return envPort(ps.Spec.Env, constants.PolicyServerListenPortEnvVar, constants.PolicyServerListenPort)
+}
+
+// ReadinessProbePort returns the port of the readiness endpoint of the policy
+// server pods, which can be overridden with spec.env.
+func (ps *PolicyServer) ReadinessProbePort() int {
+	return envPort(ps.Spec.Env, constants.PolicyServerReadinessProbePortEnvVar, constants.PolicyServerReadinessProbePort)
+}
+
+// envPort returns the port set by the given environment variable, where the
+// last definition of the variable wins like in the container environment.
+// The default port is returned when the variable is not set or is not a
+// number.
+func envPort(env []corev1.EnvVar, name string, defaultPort int) int {
+	port := defaultPort
+	for _, envVar := range env {
+		if envVar.Name != name {
+			continue
+		}
+		if value, err := strconv.Atoi(envVar.Value); err == nil {
+			port = value
+		}
+	}
+
+	return port
 }
 
 func (ps *PolicyServer) AppLabel() string {
@@ -17,39 +17,149 @@ limitations under the License.
 package v1
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/api/validation"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	validationutils "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/distribution/reference"
 	"github.com/go-logr/logr"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
+	"github.com/kubewarden/kubewarden-controller/internal/tracing"
 )
 
+const (
+	// referenceLookupRetryInterval is the initial interval between the
+	// retries of the lookups of the objects referenced by a PolicyServer.
+	referenceLookupRetryInterval = 100 * time.Millisecond
+	// policyServerTerminationGracePeriodSeconds is the termination grace
+	// period of the policy server pods, the Kubernetes default.
+	policyServerTerminationGracePeriodSeconds = 30
+)
+
+// PolicyServerDefaulterOptions configures the optional defaults set by the
+// PolicyServer mutating webhook.
+type PolicyServerDefaulterOptions struct {
+	// DefaultMaxUnavailable is the maxUnavailable set on the PolicyServers
+	// running more than one replica without minAvailable and maxUnavailable,
+	// so that a PodDisruptionBudget protects them. No default is set when
+	// nil.
+	DefaultMaxUnavailable *intstr.IntOrString
+}
+
+// PolicyServerValidatorOptions configures the optional checks performed by
+// the PolicyServer validating webhook.
+type PolicyServerValidatorOptions struct {
+	// EnvDenyList is the list of environment variable names that should not
+	// be set in the PolicyServer spec.env field.
+	EnvDenyList []string
+	// RejectDeniedEnv rejects the PolicyServers setting an environment
+	// variable of the EnvDenyList. When false, a warning is returned instead.
+	RejectDeniedEnv bool
+	// RequiredResources is the list of resource names that must be set in
+	// both the PolicyServer spec.requests and spec.limits fields, e.g. to
+	// satisfy the LimitRanges of the cluster.
+	RequiredResources []corev1.ResourceName
+	// AllowLoadBalancerServices accepts the PolicyServers exposed by a
+	// LoadBalancer Service with a warning, instead of rejecting them.
+	AllowLoadBalancerServices bool
+	// ManagedOtelEnv is the list of OpenTelemetry environment variable names
+	// set by the controller in the policy server container, overriding the
+	// ones set in the PolicyServer spec.env field.
+	ManagedOtelEnv []string
+	// MetricsPort is the port the policy server pods expose the metrics on,
+	// which the other ports cannot collide with. Defaults to
+	// constants.PolicyServerMetricsPort when zero.
+	MetricsPort int32
+	// ReferenceLookupRetries is the number of times the lookups of the
+	// objects referenced by the PolicyServer, like the image pull secrets,
+	// are retried with backoff when the objects are not found. The objects
+	// can be missing from the cache of the client right after their
+	// creation.
+	ReferenceLookupRetries int
+}
+
+// DefaultPolicyServerEnvDenyList returns the environment variables disabling
+// security features of the policy server.
+func DefaultPolicyServerEnvDenyList() []string {
+	return []string{
+		"KUBEWARDEN_ALWAYS_ACCEPT_ADMISSION_REVIEWS_ON_NAMESPACE",
+		constants.PolicyServerContinueOnErrorsEnvVar,
+		constants.PolicyServerDisableTimeoutProtectionEnvVar,
+		constants.PolicyServerEnablePprofEnvVar,
+		"KUBEWARDEN_IGNORE_KUBERNETES_CONNECTION_FAILURE",
+	}
+}
+
+// PolicyServerFeatureFlagsEnv returns the environment variables of the policy
+// server toggled by the feature flags, indexed by the feature name.
+func PolicyServerFeatureFlagsEnv() map[string]string {
+	return map[string]string{
+		"continue-on-errors":         constants.PolicyServerContinueOnErrorsEnvVar,
+		"disable-timeout-protection": constants.PolicyServerDisableTimeoutProtectionEnvVar,
+		"enable-pprof":               constants.PolicyServerEnablePprofEnvVar,
+	}
+}
+
+// PolicyServerLogLevels returns the log levels accepted by the policy server.
+func PolicyServerLogLevels() []string {
+	return []string{
+		"trace",
+		"debug",
+		"info",
+		"warn",
+		"error",
+	}
+}
+
 // SetupWebhookWithManager registers the PolicyServer webhook with the controller manager.
-func (ps *PolicyServer) SetupWebhookWithManager(mgr ctrl.Manager, deploymentsNamespace string) error {
+func (ps *PolicyServer) SetupWebhookWithManager(
+	mgr ctrl.Manager,
+	deploymentsNamespace string,
+	defaulterOptions PolicyServerDefaulterOptions,
+	validatorOptions PolicyServerValidatorOptions,
+) error {
 	logger := mgr.GetLogger().WithName("policyserver-webhook")
 
 	err := ctrl.NewWebhookManagedBy(mgr).
 		For(ps).
 		WithDefaulter(&policyServerDefaulter{
-			logger: logger,
+			logger:  logger,
+			options: defaulterOptions,
 		}).
 		WithValidator(&policyServerValidator{
 			deploymentsNamespace: deploymentsNamespace,
 			k8sClient:            mgr.GetClient(),
 			logger:               logger,
+			options:              validatorOptions,
 		}).
 		Complete()
 	if err != nil {
@@ -63,27 +173,49 @@ func (ps *PolicyServer) SetupWebhookWithManager(mgr ctrl.Manager, deploymentsNam
 
 // policyServerDefaulter sets defaults of PolicyServer objects when they are created or updated.
 type policyServerDefaulter struct {
-	logger logr.Logger
+	logger  logr.Logger
+	options PolicyServerDefaulterOptions
 }
 
 var _ webhook.CustomDefaulter = &policyServerDefaulter{}
 
 // Default implements webhook.CustomDefaulter so a webhook will be registered for the type.
-func (d *policyServerDefaulter) Default(_ context.Context, obj runtime.Object) error {
+func (d *policyServerDefaulter) Default(ctx context.Context, obj runtime.Object) error {
 	policyServer, ok := obj.(*PolicyServer)
 	if !ok {
 		return fmt.Errorf("expected a PolicyServer object, got %T", obj)
 	}
 
+	tracing.RecordAdmission(ctx, policyServer)
+
 	d.logger.Info("Defaulting PolicyServer", "name", policyServer.GetName())
 
 	if policyServer.ObjectMeta.DeletionTimestamp == nil {
 		controllerutil.AddFinalizer(policyServer, constants.KubewardenFinalizer)
 	}
 
+	defaultMaxUnavailable(&policyServer.Spec, d.options.DefaultMaxUnavailable)
+
 	return nil
 }
 
+// defaultMaxUnavailable sets the maxUnavailable of the PolicyServers running
+// more than one replica when neither minAvailable nor maxUnavailable are set,
+// so that a PodDisruptionBudget is created for them. The values set by the
+// user are never changed, and the PolicyServers using the Recreate strategy
+// are left untouched, since the strategy cannot be used together with a
+// PodDisruptionBudget.
+func defaultMaxUnavailable(spec *PolicyServerSpec, maxUnavailable *intstr.IntOrString) {
+	if maxUnavailable == nil || spec.Replicas <= 1 || spec.MinAvailable != nil || spec.MaxUnavailable != nil {
+		return
+	}
+	if spec.Strategy != nil && spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType {
+		return
+	}
+
+	spec.MaxUnavailable = ptr.To(*maxUnavailable)
+}
+
 // +kubebuilder:webhook:path=/validate-policies-kubewarden-io-v1-policyserver,mutating=false,failurePolicy=fail,sideEffects=None,groups=policies.kubewarden.io,resources=policyservers,verbs=create;update,versions=v1,name=vpolicyserver.kb.io,admissionReviewVersions=v1
 
 // polyServerCustomValidator validates PolicyServers when they are created, updated, or deleted.
@@ -91,6 +223,7 @@ type policyServerValidator struct {
 	deploymentsNamespace string
 	k8sClient            client.Client
 	logger               logr.Logger
+	options              PolicyServerValidatorOptions
 }
 
 var _ webhook.CustomValidator = &policyServerValidator{}
@@ -104,7 +237,13 @@ func (v *policyServerValidator) ValidateCreate(ctx context.Context, obj runtime.
 
 	v.logger.Info("Validating PolicyServer create", "name", policyServer.GetName())
 
-	return nil, v.validate(ctx, policyServer)
+	// The name cannot be changed, hence it is validated only on creation: the
+	// PolicyServers created before a naming rule was introduced can still be
+	// updated, and their finalizer removed.
+	allErrs := validatePolicyServerName(policyServer.GetName())
+	allErrs = append(allErrs, v.validateFields(ctx, policyServer)...)
+
+	return v.warnings(ctx, policyServer), invalidPolicyServerError(ctx, policyServer, allErrs)
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.)
@@ -116,7 +255,7 @@ func (v *policyServerValidator) ValidateUpdate(ctx context.Context, _, newObj ru
 
 	v.logger.Info("Validating PolicyServer update", "name", policyServer.GetName())
 
-	return nil, v.validate(ctx, policyServer)
+	return v.warnings(ctx, policyServer), v.validate(ctx, policyServer)
 }
 
 // ValdidaeDelete implements webhook.CustomValidator so a webhook will be registered for the type.
@@ -133,51 +272,989 @@ func (v *policyServerValidator) ValidateDelete(_ context.Context, obj runtime.Ob
 
 // validate validates a the fields PolicyServer object.
 func (v *policyServerValidator) validate(ctx context.Context, policyServer *PolicyServer) error {
+	return invalidPolicyServerError(ctx, policyServer, v.validateFields(ctx, policyServer))
+}
+
+// invalidPolicyServerError returns the error rejecting the PolicyServer with
+// the given field errors, nil when there are none.
+func invalidPolicyServerError(ctx context.Context, policyServer *PolicyServer, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	recordValidationRejection(ctx, "PolicyServer", allErrs)
+	return apierrors.NewInvalid(GroupVersion.WithKind("PolicyServer").GroupKind(), policyServer.Name, allErrs)
+}
+
+// validateFields returns the errors of the fields of the PolicyServer that
+// can be changed.
+func (v *policyServerValidator) validateFields(ctx context.Context, policyServer *PolicyServer) field.ErrorList {
 	var allErrs field.ErrorList
 
-	// The PolicyServer name must be maximum 63 like all Kubernetes objects to fit in a DNS subdomain name
-	if len(policyServer.GetName()) > validationutils.DNS1035LabelMaxLength {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("metadata").Child("name"), policyServer.GetName(), fmt.Sprintf("the PolicyServer name cannot be longer than %d characters", validationutils.DNS1035LabelMaxLength)))
+	allErrs = append(allErrs, v.validateImagePullSecrets(ctx, policyServer)...)
+
+	allErrs = append(allErrs, v.validateEnvFrom(ctx, policyServer.Spec.EnvFrom)...)
+
+	if err := v.validateVerificationConfigSource(ctx, policyServer.Spec); err != nil {
+		allErrs = append(allErrs, err)
 	}
 
-	if policyServer.Spec.ImagePullSecret != "" {
-		if err := validateImagePullSecret(ctx, v.k8sClient, policyServer.Spec.ImagePullSecret, v.deploymentsNamespace); err != nil {
-			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("imagePullSecret"), policyServer.Spec.ImagePullSecret, err.Error()))
+	allErrs = append(allErrs, ValidatePolicyServerSpec(policyServer.Spec)...)
+
+	allErrs = append(allErrs, validatePorts(policyServer.Spec.Env, v.metricsPort())...)
+
+	allErrs = append(allErrs, validateInitContainers(policyServer)...)
+
+	if v.options.RejectDeniedEnv {
+		allErrs = append(allErrs, v.validateDeniedEnv(policyServer.Spec.Env)...)
+		allErrs = append(allErrs, v.validateDeniedFeatureFlags(policyServer.Spec.FeatureFlags)...)
+		allErrs = append(allErrs, v.validateDeniedEnvFrom(v.envFromVariables(ctx, policyServer.Spec.EnvFrom))...)
+	}
+
+	allErrs = append(allErrs, v.validateRequiredResources(policyServer.Spec)...)
+
+	if policyServer.Spec.ServiceType == corev1.ServiceTypeLoadBalancer && !v.options.AllowLoadBalancerServices {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("serviceType"), "the LoadBalancer Service type exposes the policy server outside of the cluster and is not allowed by the controller configuration"))
+	}
+
+	return allErrs
+}
+
+// validatePolicyServerName checks the names of the resources derived from the
+// PolicyServer name are valid and do not collide with the names reserved for
+// the resources of other policy servers.
+func validatePolicyServerName(name string) field.ErrorList {
+	var allErrs field.ErrorList
+	namePath := field.NewPath("metadata").Child("name")
+
+	// The names of the derived resources, like the Service, are prefixed
+	// and must be maximum 63 characters to fit in a DNS label.
+	if maxLength := validationutils.DNS1035LabelMaxLength - len(constants.PolicyServerNamePrefix); len(name) > maxLength {
+		return append(allErrs, field.Invalid(namePath, name, fmt.Sprintf("the PolicyServer name cannot be longer than %d characters, the names of the policy server resources are prefixed with %q and cannot be longer than %d characters",
+			maxLength, constants.PolicyServerNamePrefix, validationutils.DNS1035LabelMaxLength)))
+	}
+
+	// The derived resources, like the Service, require DNS labels: names
+	// with dots are valid cluster-wide names but not valid Service names.
+	for _, msg := range validationutils.IsDNS1123Label(name) {
+		allErrs = append(allErrs, field.Invalid(namePath, name, "the PolicyServer name must be a valid DNS label: "+msg))
+	}
+
+	if strings.HasPrefix(name, constants.PolicyServerNamePrefix) {
+		allErrs = append(allErrs, field.Invalid(namePath, name, fmt.Sprintf("the PolicyServer name cannot start with %q, the prefix is added to the names of the policy server resources", constants.PolicyServerNamePrefix)))
+	}
+
+	if strings.HasSuffix(name, constants.PolicyServerImagePullSecretsSuffix) {
+		allErrs = append(allErrs, field.Invalid(namePath, name, fmt.Sprintf("the PolicyServer name cannot end with %q, the suffix is reserved for the image pull secrets of the policy servers", constants.PolicyServerImagePullSecretsSuffix)))
+	}
+
+	return allErrs
+}
+
+// validateInitContainers checks the init container names are unique and do
+// not collide with the name of the policy server container.
+func validateInitContainers(policyServer *PolicyServer) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := map[string]struct{}{policyServer.NameWithPrefix(): {}}
+	for i, initContainer := range policyServer.Spec.InitContainers {
+		path := field.NewPath("spec").Child("initContainers").Index(i).Child("name")
+		if _, found := names[initContainer.Name]; found {
+			allErrs = append(allErrs, field.Duplicate(path, initContainer.Name))
 		}
+		names[initContainer.Name] = struct{}{}
 	}
 
+	return allErrs
+}
+
+// ValidatePolicyServerSpec validates the PolicyServer spec against the rules
+// enforced by the PolicyServer validating webhook that do not depend on the
+// cluster state or on the controller configuration. It can be used to
+// validate a PolicyServer manifest without a live cluster.
+func ValidatePolicyServerSpec(spec PolicyServerSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
 	// Kubernetes does not allow to set both MinAvailable and MaxUnavailable at the same time
-	if policyServer.Spec.MinAvailable != nil && policyServer.Spec.MaxUnavailable != nil {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("spec"), fmt.Sprintf("minAvailable: %s, maxUnavailable: %s", policyServer.Spec.MinAvailable, policyServer.Spec.MaxUnavailable), "minAvailable and maxUnavailable cannot be both set"))
+	if spec.MinAvailable != nil && spec.MaxUnavailable != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec"), fmt.Sprintf("minAvailable: %s, maxUnavailable: %s", spec.MinAvailable, spec.MaxUnavailable), "minAvailable and maxUnavailable cannot be both set"))
 	}
 
-	allErrs = append(allErrs, validateLimitsAndRequests(policyServer.Spec.Limits, policyServer.Spec.Requests)...)
+	if err := validateStrategy(spec); err != nil {
+		allErrs = append(allErrs, err)
+	}
 
-	if len(allErrs) == 0 {
+	if err := validateDNSConfig(spec); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if spec.HeadlessService && spec.ServiceType != "" && spec.ServiceType != corev1.ServiceTypeClusterIP {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("serviceType"), spec.ServiceType, "a headless Service must be of type ClusterIP"))
+	}
+
+	if err := validateDedicatedServiceAccount(spec); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := validateSeccompProfile(spec.SecurityContexts.SeccompProfile); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := validateLivenessProbe(spec.Probes.Liveness); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	allErrs = append(allErrs, validateSourceAuthorities(spec.SourceAuthorities)...)
+
+	allErrs = append(allErrs, validateLimitsAndRequests(spec.Limits, spec.Requests)...)
+
+	allErrs = append(allErrs, validateEnvNames(spec.Env)...)
+
+	allErrs = append(allErrs, validation.ValidateAnnotations(spec.ServiceAnnotations, field.NewPath("spec").Child("serviceAnnotations"))...)
+
+	allErrs = append(allErrs, validateFeatureFlags(spec.FeatureFlags)...)
+
+	if spec.DefaultPolicyTimeoutSeconds != nil && (*spec.DefaultPolicyTimeoutSeconds < 1 || *spec.DefaultPolicyTimeoutSeconds > 30) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("defaultPolicyTimeoutSeconds"), *spec.DefaultPolicyTimeoutSeconds, "the timeout must be between 1 and 30 seconds"))
+	}
+
+	if spec.ServiceAccountToken != nil {
+		if err := validateTokenAudience(field.NewPath("spec").Child("serviceAccountToken").Child("audience"), spec.ServiceAccountToken.Audience); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	if spec.ProjectedToken != nil {
+		if err := validateTokenAudience(field.NewPath("spec").Child("projectedToken").Child("audience"), spec.ProjectedToken.Audience); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	if spec.Workers != nil && *spec.Workers < 1 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("workers"), *spec.Workers, "must be a positive integer"))
+	}
+
+	if spec.PreStopSleepSeconds != nil && *spec.PreStopSleepSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("preStopSleepSeconds"), *spec.PreStopSleepSeconds, "must be greater than or equal to 0"))
+	}
+
+	if spec.LogLevel != "" && !slices.Contains(PolicyServerLogLevels(), spec.LogLevel) {
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("spec").Child("logLevel"), spec.LogLevel, PolicyServerLogLevels()))
+	}
+
+	return allErrs
+}
+
+// validateFeatureFlags checks that the known feature flags are set to "true"
+// or "false". The unknown ones are ignored and only get a warning.
+func validateFeatureFlags(featureFlags map[string]string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for _, name := range slices.Sorted(maps.Keys(featureFlags)) {
+		if _, found := PolicyServerFeatureFlagsEnv()[name]; !found {
+			continue
+		}
+		if value := featureFlags[name]; value != "true" && value != "false" {
+			allErrs = append(allErrs, field.NotSupported(field.NewPath("spec").Child("featureFlags").Key(name), value, []string{"true", "false"}))
+		}
+	}
+
+	return allErrs
+}
+
+// validateStrategy checks that the Recreate strategy is not used together with
+// a PodDisruptionBudget. The Recreate strategy terminates all the policy server
+// pods before creating the new ones, which cannot be honored when a
+// PodDisruptionBudget is in place.
+func validateStrategy(spec PolicyServerSpec) *field.Error {
+	if spec.Strategy != nil && spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType &&
+		(spec.MinAvailable != nil || spec.MaxUnavailable != nil) {
+		return field.Invalid(field.NewPath("spec").Child("strategy").Child("type"), spec.Strategy.Type, "the Recreate strategy cannot be used when minAvailable or maxUnavailable are set")
+	}
+	return nil
+}
+
+// validateLivenessProbe checks that the successThreshold of the liveness probe
+// is 1, as required by Kubernetes.
+func validateLivenessProbe(liveness *ProbeConfiguration) *field.Error {
+	if liveness == nil || liveness.SuccessThreshold == nil || *liveness.SuccessThreshold == 1 {
+		return nil
+	}
+	return field.Invalid(field.NewPath("spec").Child("probes").Child("liveness").Child("successThreshold"), *liveness.SuccessThreshold, "must be 1 for the liveness probe")
+}
+
+// validateDNSConfig checks that the DNS configuration is only set with the
+// None DNS policy, which is the only one building the pod resolver
+// configuration from it.
+func validateDNSConfig(spec PolicyServerSpec) *field.Error {
+	if spec.DNSConfig == nil || (spec.DNSPolicy != nil && *spec.DNSPolicy == corev1.DNSNone) {
 		return nil
 	}
 
-	return apierrors.NewInvalid(GroupVersion.WithKind("PolicyServer").GroupKind(), policyServer.Name, allErrs)
+	dnsPolicy := ""
+	if spec.DNSPolicy != nil {
+		dnsPolicy = string(*spec.DNSPolicy)
+	}
+	return field.Invalid(field.NewPath("spec").Child("dnsPolicy"), dnsPolicy, "dnsConfig can only be set when dnsPolicy is None")
+}
+
+// validateTokenAudience checks that the audience of a projected service
+// account token is not empty and has no whitespaces. An audience with a
+// scheme must be an absolute URL with a host.
+func validateTokenAudience(fldPath *field.Path, audience string) *field.Error {
+	if audience == "" {
+		return field.Required(fldPath, "the audience of the token must be set")
+	}
+
+	if strings.IndexFunc(audience, unicode.IsSpace) >= 0 {
+		return field.Invalid(fldPath, audience, "the audience of the token must not contain whitespaces")
+	}
+
+	if strings.Contains(audience, "://") {
+		if audienceURL, err := url.Parse(audience); err != nil || audienceURL.Host == "" {
+			return field.Invalid(fldPath, audience, "the audience of the token must be an absolute URL with a host when it has a scheme")
+		}
+	}
+
+	return nil
+}
+
+// validateDedicatedServiceAccount checks that the dedicated ServiceAccount is
+// not requested together with a user provided ServiceAccount.
+func validateDedicatedServiceAccount(spec PolicyServerSpec) *field.Error {
+	if spec.DedicatedServiceAccount && spec.ServiceAccountName != "" {
+		return field.Invalid(field.NewPath("spec").Child("dedicatedServiceAccount"), spec.DedicatedServiceAccount, "dedicatedServiceAccount cannot be set together with serviceAccountName")
+	}
+	return nil
+}
+
+// validateSeccompProfile checks that the localhost profile is set only, and
+// always, with the Localhost seccomp profile type.
+func validateSeccompProfile(seccompProfile *corev1.SeccompProfile) *field.Error {
+	if seccompProfile == nil {
+		return nil
+	}
+
+	localhostProfilePath := field.NewPath("spec").Child("securityContexts").Child("seccompProfile").Child("localhostProfile")
+	isLocalhost := seccompProfile.Type == corev1.SeccompProfileTypeLocalhost
+	hasLocalhostProfile := seccompProfile.LocalhostProfile != nil && *seccompProfile.LocalhostProfile != ""
+	if isLocalhost && !hasLocalhostProfile {
+		return field.Required(localhostProfilePath, "localhostProfile must be set when the seccomp profile type is Localhost")
+	}
+	if !isLocalhost && seccompProfile.LocalhostProfile != nil {
+		return field.Forbidden(localhostProfilePath, "localhostProfile can only be set when the seccomp profile type is Localhost")
+	}
+
+	return nil
 }
 
-// validateImagePullSecret validates that the specified PolicyServer imagePullSecret exists and is of type kubernetes.io/dockerconfigjson.
-func validateImagePullSecret(ctx context.Context, k8sClient client.Client, imagePullSecret string, deploymentsNamespace string) error {
+// validateSourceAuthorities checks that the keys of the source authorities are
+// valid registry references.
+func validateSourceAuthorities(sourceAuthorities map[string][]string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for _, registry := range slices.Sorted(maps.Keys(sourceAuthorities)) {
+		path := field.NewPath("spec").Child("sourceAuthorities").Key(registry)
+		if !isValidRegistryReference(registry) {
+			allErrs = append(allErrs, field.Invalid(path, registry, "must be a registry host, with an optional port, optionally followed by a repository path"))
+		}
+
+		for i, certificate := range sourceAuthorities[registry] {
+			if err := validatePEMCertificates(certificate); err != nil {
+				allErrs = append(allErrs, field.Invalid(path.Index(i), field.OmitValueType{}, fmt.Sprintf("certificate %d of registry %q is not valid: %v", i, registry, err)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validatePEMCertificates checks that the given value contains only PEM
+// encoded X.509 certificates, and at least one of them.
+func validatePEMCertificates(value string) error {
+	rest := []byte(value)
+	found := false
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("unexpected PEM block of type %q", block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return fmt.Errorf("cannot parse X.509 certificate: %w", err)
+		}
+		found = true
+	}
+
+	if !found {
+		return errors.New("no PEM encoded certificate found")
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return errors.New("unexpected data after the PEM encoded certificates")
+	}
+
+	return nil
+}
+
+// isValidRegistryReference returns true when the given value is a registry
+// host[:port], optionally followed by the path of a repository.
+func isValidRegistryReference(registry string) bool {
+	domainRegexp := regexp.MustCompile("^(?:" + reference.DomainRegexp.String() + ")$")
+	nameRegexp := regexp.MustCompile("^(?:" + reference.NameRegexp.String() + ")$")
+
+	domain, _, hasPath := strings.Cut(registry, "/")
+	if !domainRegexp.MatchString(domain) {
+		return false
+	}
+	return !hasPath || nameRegexp.MatchString(registry)
+}
+
+// validateDeniedEnv checks that none of the environment variables is part of
+// the deny-list.
+func (v *policyServerValidator) validateDeniedEnv(env []corev1.EnvVar) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, envVar := range env {
+		if slices.Contains(v.options.EnvDenyList, envVar.Name) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("env").Index(i).Child("name"), fmt.Sprintf("the %s environment variable weakens the security of the policy server", envVar.Name)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateDeniedFeatureFlags checks that none of the enabled feature flags
+// sets an environment variable of the deny list.
+func (v *policyServerValidator) validateDeniedFeatureFlags(featureFlags map[string]string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for _, name := range slices.Sorted(maps.Keys(featureFlags)) {
+		envName, found := PolicyServerFeatureFlagsEnv()[name]
+		if found && featureFlags[name] == "true" && slices.Contains(v.options.EnvDenyList, envName) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("featureFlags").Key(name), fmt.Sprintf("the %s feature weakens the security of the policy server", name)))
+		}
+	}
+
+	return allErrs
+}
+
+// warnings returns the warnings about PolicyServer configurations that are
+// allowed but could lead to an unexpected behavior.
+func (v *policyServerValidator) warnings(ctx context.Context, policyServer *PolicyServer) admission.Warnings {
+	var warnings admission.Warnings
+
+	if policyServer.Spec.HeadlessService {
+		warnings = append(warnings, "spec.headlessService: the Kubernetes API server requires a Service with a ClusterIP to route admission requests to the policy server, policies hosted by this policy server may not be reachable")
+	}
+
+	if policyServer.Spec.ServiceType == corev1.ServiceTypeLoadBalancer && v.options.AllowLoadBalancerServices {
+		warnings = append(warnings, "spec.serviceType: the LoadBalancer Service type exposes the policy server outside of the cluster")
+	}
+
+	if nodeSelectorConflictsWithNodeAffinity(policyServer.Spec.NodeSelector, policyServer.Spec.Affinity) {
+		warnings = append(warnings, "spec.nodeSelector: the node selector conflicts with all the terms of spec.affinity.nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution, the policy server pods may never be scheduled")
+	}
+
+	if policyServer.Spec.PreStopSleepSeconds != nil && *policyServer.Spec.PreStopSleepSeconds >= policyServerTerminationGracePeriodSeconds {
+		warnings = append(warnings, fmt.Sprintf("spec.preStopSleepSeconds: the sleep does not end within the %d seconds termination grace period of the pods, the policy server is killed without shutting down gracefully", policyServerTerminationGracePeriodSeconds))
+	}
+
+	if policyServer.Spec.NodeName != "" {
+		warnings = append(warnings, "spec.nodeName: the policy server pods bypass the scheduler and run on a single node, the policy server is not highly available")
+	}
+
+	for _, source := range policyServer.Spec.InsecureSources {
+		if _, found := policyServer.Spec.SourceAuthorities[source]; found {
+			warnings = append(warnings, fmt.Sprintf("spec.insecureSources: %q is also listed in spec.sourceAuthorities, the source is accessed insecurely and its certificate authorities are ignored", source))
+		}
+	}
+
+	if policyServer.Spec.ImagePullSecret != "" {
+		warnings = append(warnings, "spec.imagePullSecret: the field is deprecated, use spec.imagePullSecrets instead")
+	}
+
+	if warning := rollingUpdateDisruptionBudgetWarning(policyServer.Spec); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	if warning := logLevelEnvWarning(policyServer.Spec); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	if warning := workersEnvWarning(policyServer.Spec); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	for i, envVar := range policyServer.Spec.Env {
+		if slices.Contains(v.options.ManagedOtelEnv, envVar.Name) {
+			warnings = append(warnings, fmt.Sprintf("spec.env[%d].name: %s is managed by the controller telemetry configuration, the value set in the PolicyServer is overridden", i, envVar.Name))
+		}
+	}
+
+	envFromVariables := v.envFromVariables(ctx, policyServer.Spec.EnvFrom)
+	warnings = append(warnings, v.envFromWarnings(policyServer.Spec, envFromVariables)...)
+
+	for _, name := range slices.Sorted(maps.Keys(policyServer.Spec.FeatureFlags)) {
+		if _, found := PolicyServerFeatureFlagsEnv()[name]; !found {
+			warnings = append(warnings, fmt.Sprintf("spec.featureFlags[%s]: unknown feature, it is ignored", name))
+		}
+	}
+
+	if !v.options.RejectDeniedEnv {
+		for _, err := range v.validateDeniedEnv(policyServer.Spec.Env) {
+			warnings = append(warnings, err.Error())
+		}
+		for _, err := range v.validateDeniedFeatureFlags(policyServer.Spec.FeatureFlags) {
+			warnings = append(warnings, err.Error())
+		}
+		for _, err := range v.validateDeniedEnvFrom(envFromVariables) {
+			warnings = append(warnings, err.Error())
+		}
+	}
+
+	return warnings
+}
+
+// logLevelEnvWarning returns a warning when the log level is set both by the
+// logLevel field and by an environment variable, the environment variable
+// taking precedence.
+func logLevelEnvWarning(spec PolicyServerSpec) string {
+	if spec.LogLevel == "" {
+		return ""
+	}
+	i := slices.IndexFunc(spec.Env, func(envVar corev1.EnvVar) bool {
+		return envVar.Name == constants.PolicyServerLogLevelEnvVar
+	})
+	if i < 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("spec.env[%d].name: %s overrides the log level set in spec.logLevel", i, constants.PolicyServerLogLevelEnvVar)
+}
+
+// workersEnvWarning returns a warning when the number of workers is set both
+// by the workers field and by an environment variable, the environment
+// variable taking precedence.
+func workersEnvWarning(spec PolicyServerSpec) string {
+	if spec.Workers == nil {
+		return ""
+	}
+	i := slices.IndexFunc(spec.Env, func(envVar corev1.EnvVar) bool {
+		return envVar.Name == constants.PolicyServerWorkersEnvVar
+	})
+	if i < 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("spec.env[%d].name: %s overrides the number of workers set in spec.workers", i, constants.PolicyServerWorkersEnvVar)
+}
+
+// rollingUpdateDisruptionBudgetWarning returns a warning when the rolling
+// update can take down more policy server pods than the disruptions allowed by
+// the PodDisruptionBudget, given the number of replicas. The Deployment
+// controller does not honor the PodDisruptionBudget, hence the evictions
+// needed to drain the nodes are blocked while the rollout is in progress, and
+// a rollout waiting for pods scheduled on the drained nodes can deadlock.
+func rollingUpdateDisruptionBudgetWarning(spec PolicyServerSpec) string {
+	if spec.MinAvailable == nil && spec.MaxUnavailable == nil {
+		return ""
+	}
+	if spec.Strategy != nil && spec.Strategy.Type != appsv1.RollingUpdateDeploymentStrategyType {
+		return ""
+	}
+
+	replicas := int(spec.Replicas)
+	allowedDisruptions, err := podDisruptionBudgetAllowedDisruptions(spec, replicas)
+	if err != nil {
+		return ""
+	}
+
+	// The Deployment defaults maxUnavailable to 25%, rounded down
+	rollingMaxUnavailable := intstr.FromString("25%")
+	if spec.Strategy != nil && spec.Strategy.RollingUpdate != nil && spec.Strategy.RollingUpdate.MaxUnavailable != nil {
+		rollingMaxUnavailable = *spec.Strategy.RollingUpdate.MaxUnavailable
+	}
+	rollingUnavailable, err := intstr.GetScaledValueFromIntOrPercent(&rollingMaxUnavailable, replicas, false)
+	if err != nil || rollingUnavailable <= allowedDisruptions {
+		return ""
+	}
+
+	return fmt.Sprintf("spec.strategy: the rolling update can take down %d of the %d replicas while the PodDisruptionBudget allows %d disruptions, "+
+		"node drains are blocked during the rollouts and a rollout waiting for the drained nodes can deadlock", rollingUnavailable, replicas, allowedDisruptions)
+}
+
+// podDisruptionBudgetAllowedDisruptions returns the number of policy server
+// pods the PodDisruptionBudget allows to disrupt when all the replicas are
+// available.
+func podDisruptionBudgetAllowedDisruptions(spec PolicyServerSpec, replicas int) (int, error) {
+	if spec.MinAvailable != nil {
+		minAvailable, err := intstr.GetScaledValueFromIntOrPercent(spec.MinAvailable, replicas, true)
+		if err != nil {
+			return 0, fmt.Errorf("invalid minAvailable: %w", err)
+		}
+		return max(replicas-minAvailable, 0), nil
+	}
+
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(spec.MaxUnavailable, replicas, true)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maxUnavailable: %w", err)
+	}
+	return maxUnavailable, nil
+}
+
+// nodeSelectorConflictsWithNodeAffinity is a best-effort check telling whether
+// a node carrying the labels required by the node selector cannot satisfy any
+// of the required node affinity terms. Node affinity terms are ORed, while the
+// expressions inside of a term are ANDed.
+func nodeSelectorConflictsWithNodeAffinity(nodeSelector map[string]string, affinity corev1.Affinity) bool {
+	if len(nodeSelector) == 0 || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return false
+	}
+
+	terms := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) == 0 {
+		return false
+	}
+
+	for _, term := range terms {
+		if !nodeSelectorTermConflicts(nodeSelector, term) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nodeSelectorTermConflicts returns true when one of the term's expressions
+// cannot be satisfied by a node carrying the labels required by the node selector.
+func nodeSelectorTermConflicts(nodeSelector map[string]string, term corev1.NodeSelectorTerm) bool {
+	for _, requirement := range term.MatchExpressions {
+		value, found := nodeSelector[requirement.Key]
+		if found && !nodeSelectorRequirementMatches(requirement, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nodeSelectorRequirementMatches returns true when a node label with the given
+// value satisfies the requirement. Requirements that cannot be evaluated are
+// considered satisfied.
+func nodeSelectorRequirementMatches(requirement corev1.NodeSelectorRequirement, value string) bool {
+	switch requirement.Operator {
+	case corev1.NodeSelectorOpIn:
+		return slices.Contains(requirement.Values, value)
+	case corev1.NodeSelectorOpNotIn:
+		return !slices.Contains(requirement.Values, value)
+	case corev1.NodeSelectorOpExists:
+		return true
+	case corev1.NodeSelectorOpDoesNotExist:
+		return false
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if len(requirement.Values) != 1 {
+			return true
+		}
+		labelValue, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return true
+		}
+		requirementValue, err := strconv.ParseInt(requirement.Values[0], 10, 64)
+		if err != nil {
+			return true
+		}
+		if requirement.Operator == corev1.NodeSelectorOpGt {
+			return labelValue > requirementValue
+		}
+		return labelValue < requirementValue
+	default:
+		return true
+	}
+}
+
+// validateImagePullSecrets validates all the image pull secrets referenced by
+// the PolicyServer, both by the deprecated imagePullSecret field and by the
+// imagePullSecrets list.
+func (v *policyServerValidator) validateImagePullSecrets(ctx context.Context, policyServer *PolicyServer) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if policyServer.Spec.ImagePullSecret != "" {
+		if err := v.validateImagePullSecret(ctx, policyServer.Spec.ImagePullSecret); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("imagePullSecret"), policyServer.Spec.ImagePullSecret, err.Error()))
+		}
+	}
+
+	for i, imagePullSecret := range policyServer.Spec.ImagePullSecrets {
+		path := field.NewPath("spec").Child("imagePullSecrets").Index(i).Child("name")
+		if imagePullSecret.Name == "" {
+			allErrs = append(allErrs, field.Required(path, "the secret name cannot be empty"))
+			continue
+		}
+		if err := v.validateImagePullSecret(ctx, imagePullSecret.Name); err != nil {
+			allErrs = append(allErrs, field.Invalid(path, imagePullSecret.Name, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// validateImagePullSecret validates that the specified PolicyServer image pull secret exists and is of type kubernetes.io/dockerconfigjson.
+func (v *policyServerValidator) validateImagePullSecret(ctx context.Context, imagePullSecret string) error {
 	secret := &corev1.Secret{}
-	err := k8sClient.Get(ctx, client.ObjectKey{
-		Namespace: deploymentsNamespace,
+	err := v.getWithRetry(ctx, client.ObjectKey{
+		Namespace: v.deploymentsNamespace,
 		Name:      imagePullSecret,
 	}, secret)
 	if err != nil {
-		return fmt.Errorf("cannot get spec.ImagePullSecret: %w", err)
+		return fmt.Errorf("cannot get image pull secret: %w", err)
 	}
 
-	if secret.Type != "kubernetes.io/dockerconfigjson" {
-		return fmt.Errorf("spec.ImagePullSecret secret \"%s\" is not of type kubernetes.io/dockerconfigjson", secret.Name)
+	if secret.Type != corev1.SecretTypeDockerConfigJson {
+		return fmt.Errorf("image pull secret \"%s\" is not of type kubernetes.io/dockerconfigjson", secret.Name)
 	}
 
 	return nil
 }
 
+// validateEnvFrom validates that each environment variables source references
+// either a ConfigMap or a Secret, and that the referenced object exists unless
+// it is optional.
+func (v *policyServerValidator) validateEnvFrom(ctx context.Context, envFrom []corev1.EnvFromSource) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, source := range envFrom {
+		path := field.NewPath("spec").Child("envFrom").Index(i)
+
+		if source.Prefix != "" {
+			for _, msg := range validationutils.IsEnvVarName(source.Prefix) {
+				allErrs = append(allErrs, field.Invalid(path.Child("prefix"), source.Prefix, msg))
+			}
+		}
+
+		switch {
+		case source.ConfigMapRef != nil && source.SecretRef != nil:
+			allErrs = append(allErrs, field.Invalid(path, field.OmitValueType{}, "configMapRef and secretRef cannot be both set"))
+		case source.ConfigMapRef != nil:
+			if err := v.validateEnvFromReference(ctx, path.Child("configMapRef").Child("name"), source.ConfigMapRef.Name, source.ConfigMapRef.Optional, &corev1.ConfigMap{}); err != nil {
+				allErrs = append(allErrs, err)
+			}
+		case source.SecretRef != nil:
+			if err := v.validateEnvFromReference(ctx, path.Child("secretRef").Child("name"), source.SecretRef.Name, source.SecretRef.Optional, &corev1.Secret{}); err != nil {
+				allErrs = append(allErrs, err)
+			}
+		default:
+			allErrs = append(allErrs, field.Required(path, "one of configMapRef or secretRef must be set"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateEnvFromReference validates that the ConfigMap or Secret referenced
+// by an environment variables source exists in the deployments namespace.
+// The optional references are allowed to be missing, as the kubelet starts
+// the container without them.
+func (v *policyServerValidator) validateEnvFromReference(ctx context.Context, path *field.Path, name string, optional *bool, obj client.Object) *field.Error {
+	if name == "" {
+		return field.Required(path, "the name cannot be empty")
+	}
+	if ptr.Deref(optional, false) {
+		return nil
+	}
+
+	err := v.getWithRetry(ctx, client.ObjectKey{
+		Namespace: v.deploymentsNamespace,
+		Name:      name,
+	}, obj)
+	if err != nil {
+		return field.Invalid(path, name, fmt.Sprintf("cannot get the referenced object: %v", err))
+	}
+
+	return nil
+}
+
+// envFromVariable is an environment variable defined by a ConfigMap or a
+// Secret referenced by spec.envFrom.
+type envFromVariable struct {
+	path *field.Path
+	name string
+}
+
+// envFromVariables returns the environment variables defined by the
+// ConfigMaps and Secrets referenced by spec.envFrom, with the prefix of their
+// source. The references that cannot be read are skipped, validateEnvFrom
+// reports them.
+func (v *policyServerValidator) envFromVariables(ctx context.Context, envFrom []corev1.EnvFromSource) []envFromVariable {
+	var variables []envFromVariable
+
+	for i, source := range envFrom {
+		var keys []string
+		key := client.ObjectKey{Namespace: v.deploymentsNamespace}
+		switch {
+		case source.ConfigMapRef != nil && source.SecretRef != nil:
+			continue
+		case source.ConfigMapRef != nil && source.ConfigMapRef.Name != "":
+			configMap := &corev1.ConfigMap{}
+			key.Name = source.ConfigMapRef.Name
+			if err := v.k8sClient.Get(ctx, key, configMap); err != nil {
+				continue
+			}
+			keys = slices.Sorted(maps.Keys(configMap.Data))
+		case source.SecretRef != nil && source.SecretRef.Name != "":
+			secret := &corev1.Secret{}
+			key.Name = source.SecretRef.Name
+			if err := v.k8sClient.Get(ctx, key, secret); err != nil {
+				continue
+			}
+			keys = slices.Sorted(maps.Keys(secret.Data))
+		default:
+			continue
+		}
+
+		for _, key := range keys {
+			variables = append(variables, envFromVariable{
+				path: field.NewPath("spec").Child("envFrom").Index(i),
+				name: source.Prefix + key,
+			})
+		}
+	}
+
+	return variables
+}
+
+// validateDeniedEnvFrom checks that none of the environment variables defined
+// by the sources of spec.envFrom is part of the deny-list.
+func (v *policyServerValidator) validateDeniedEnvFrom(variables []envFromVariable) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for _, variable := range variables {
+		if slices.Contains(v.options.EnvDenyList, variable.name) {
+			allErrs = append(allErrs, field.Forbidden(variable.path, fmt.Sprintf("the %s environment variable weakens the security of the policy server", variable.name)))
+		}
+	}
+
+	return allErrs
+}
+
+// envFromWarnings returns a warning for each environment variable defined by
+// the sources of spec.envFrom that is ignored, because the controller sets
+// the same variable in the container environment, which takes precedence.
+// This includes the ports, hence the sources cannot make them collide.
+func (v *policyServerValidator) envFromWarnings(spec PolicyServerSpec, variables []envFromVariable) []string {
+	var warnings []string
+
+	controllerEnv := []string{constants.PolicyServerListenPortEnvVar, constants.PolicyServerReadinessProbePortEnvVar}
+	if spec.LogLevel != "" {
+		controllerEnv = append(controllerEnv, constants.PolicyServerLogLevelEnvVar)
+	}
+	if _, found := spec.Limits[corev1.ResourceCPU]; found || spec.Workers != nil {
+		controllerEnv = append(controllerEnv, constants.PolicyServerWorkersEnvVar)
+	}
+	for name := range spec.FeatureFlags {
+		if envName, found := PolicyServerFeatureFlagsEnv()[name]; found {
+			controllerEnv = append(controllerEnv, envName)
+		}
+	}
+
+	for _, variable := range variables {
+		switch {
+		case slices.Contains(controllerEnv, variable.name):
+			warnings = append(warnings, fmt.Sprintf("%s: %s is set by the controller from the PolicyServer configuration, the value of the referenced object is ignored", variable.path, variable.name))
+		case slices.Contains(v.options.ManagedOtelEnv, variable.name):
+			warnings = append(warnings, fmt.Sprintf("%s: %s is managed by the controller telemetry configuration, the value of the referenced object is ignored", variable.path, variable.name))
+		}
+	}
+
+	return warnings
+}
+
+// getWithRetry gets the object, retrying with backoff while the object is not
+// found, up to ReferenceLookupRetries times. The retries stop when the
+// request context is done. The last lookup error is returned.
+func (v *policyServerValidator) getWithRetry(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	backoff := wait.Backoff{
+		Duration: referenceLookupRetryInterval,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    v.options.ReferenceLookupRetries + 1,
+	}
+
+	var err error
+	_ = wait.ExponentialBackoffWithContext(ctx, backoff, func(ctx context.Context) (bool, error) {
+		err = v.k8sClient.Get(ctx, key, obj)
+		return !apierrors.IsNotFound(err), nil
+	})
+
+	return err
+}
+
+// validateVerificationConfigSource validates the source of the Sigstore
+// verification configuration: either a ConfigMap or a Secret can be set.
+func (v *policyServerValidator) validateVerificationConfigSource(ctx context.Context, spec PolicyServerSpec) *field.Error {
+	if spec.VerificationConfig != "" && spec.VerificationConfigSecret != "" {
+		return field.Forbidden(field.NewPath("spec").Child("verificationConfigSecret"), "verificationConfig and verificationConfigSecret cannot be both set")
+	}
+
+	if spec.VerificationConfigSecret != "" {
+		return v.validateVerificationConfigSecret(ctx, spec.VerificationConfigSecret)
+	}
+
+	return v.validateVerificationConfigMap(ctx, spec.VerificationConfig)
+}
+
+// validateVerificationConfigMap validates that the verification config
+// ConfigMap exists and contains a valid Sigstore verification configuration.
+func (v *policyServerValidator) validateVerificationConfigMap(ctx context.Context, configMapName string) *field.Error {
+	if configMapName == "" {
+		return nil
+	}
+	path := field.NewPath("spec").Child("verificationConfig")
+
+	configMap := &corev1.ConfigMap{}
+	err := v.getWithRetry(ctx, client.ObjectKey{
+		Namespace: v.deploymentsNamespace,
+		Name:      configMapName,
+	}, configMap)
+	if err != nil {
+		return field.Invalid(path, configMapName, fmt.Sprintf("cannot get verification config ConfigMap: %v", err))
+	}
+
+	config, found := configMap.Data[constants.PolicyServerVerificationConfigEntry]
+	if !found {
+		return field.Invalid(path, configMapName, fmt.Sprintf("verification config ConfigMap %q has no %q key", configMapName, constants.PolicyServerVerificationConfigEntry))
+	}
+
+	return validateVerificationConfigEntry(path, configMapName, config)
+}
+
+// validateVerificationConfigSecret validates that the verification config
+// Secret exists and contains a valid Sigstore verification configuration.
+func (v *policyServerValidator) validateVerificationConfigSecret(ctx context.Context, secretName string) *field.Error {
+	path := field.NewPath("spec").Child("verificationConfigSecret")
+
+	secret := &corev1.Secret{}
+	err := v.getWithRetry(ctx, client.ObjectKey{
+		Namespace: v.deploymentsNamespace,
+		Name:      secretName,
+	}, secret)
+	if err != nil {
+		return field.Invalid(path, secretName, fmt.Sprintf("cannot get verification config Secret: %v", err))
+	}
+
+	config, found := secret.Data[constants.PolicyServerVerificationConfigEntry]
+	if !found {
+		return field.Invalid(path, secretName, fmt.Sprintf("verification config Secret %q has no %q key", secretName, constants.PolicyServerVerificationConfigEntry))
+	}
+
+	return validateVerificationConfigEntry(path, secretName, string(config))
+}
+
+// validateVerificationConfigEntry validates the verification configuration
+// stored in the ConfigMap or Secret with the given name.
+func validateVerificationConfigEntry(path *field.Path, name, config string) *field.Error {
+	configPath := field.NewPath("data").Key(constants.PolicyServerVerificationConfigEntry)
+	if errs := validateVerificationConfig(configPath, config); len(errs) > 0 {
+		return field.Invalid(path, name, fmt.Sprintf("invalid verification config: %v", errs.ToAggregate()))
+	}
+
+	return nil
+}
+
+// validateRequiredResources checks that the resources required by the
+// controller configuration are set in both the requests and the limits.
+func (v *policyServerValidator) validateRequiredResources(spec PolicyServerSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for _, resourceName := range v.options.RequiredResources {
+		if _, found := spec.Requests[resourceName]; !found {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("requests").Key(string(resourceName)), field.OmitValueType{}, fmt.Sprintf("the %s request is required", resourceName)))
+		}
+		if _, found := spec.Limits[resourceName]; !found {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("limits").Key(string(resourceName)), field.OmitValueType{}, fmt.Sprintf("the %s limit is required", resourceName)))
+		}
+	}
+
+	return allErrs
+}
+
+// policyServerPort is a port the policy server pods listen on.
+type policyServerPort struct {
+	name   string
+	envVar string
+	port   int
+}
+
+func (p policyServerPort) String() string {
+	if p.envVar == "" {
+		return p.name + " port"
+	}
+	return fmt.Sprintf("%s port (%s)", p.name, p.envVar)
+}
+
+// policyServerPorts returns the ports the policy server pods listen on. The
+// serving and the readiness probe ports can be overridden with the
+// environment variables of spec.env, where the last definition of a variable
+// wins like in the container environment. The metrics port is configured by
+// the controller.
+func policyServerPorts(env []corev1.EnvVar, metricsPort int32) []policyServerPort {
+	return []policyServerPort{
+		{name: "serving", envVar: constants.PolicyServerListenPortEnvVar, port: envPort(env, constants.PolicyServerListenPortEnvVar, constants.PolicyServerListenPort)},
+		{name: "readiness probe", envVar: constants.PolicyServerReadinessProbePortEnvVar, port: envPort(env, constants.PolicyServerReadinessProbePortEnvVar, constants.PolicyServerReadinessProbePort)},
+		{name: "metrics", port: int(metricsPort)},
+	}
+}
+
+// validateEnvNames checks that the environment variable names can be read by
+// the policy server and that each variable is defined only once, because the
+// precedence of the duplicated variables is undefined.
+func validateEnvNames(env []corev1.EnvVar) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := sets.New[string]()
+	for i, envVar := range env {
+		fieldPath := field.NewPath("spec").Child("env").Index(i).Child("name")
+		for _, msg := range validationutils.IsCIdentifier(envVar.Name) {
+			allErrs = append(allErrs, field.Invalid(fieldPath, envVar.Name, msg))
+		}
+		if names.Has(envVar.Name) {
+			allErrs = append(allErrs, field.Duplicate(fieldPath, envVar.Name))
+		}
+		names.Insert(envVar.Name)
+	}
+
+	return allErrs
+}
+
+// validatePorts checks that the ports the policy server pods listen on do
+// not overlap, otherwise the containers fail to bind them.
+func validatePorts(env []corev1.EnvVar, metricsPort int32) field.ErrorList {
+	var allErrs field.ErrorList
+
+	ports := policyServerPorts(env, metricsPort)
+	for i := range ports {
+		for j := i + 1; j < len(ports); j++ {
+			if ports[i].port != ports[j].port {
+				continue
+			}
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("env"), ports[i].port, fmt.Sprintf("the %s collides with the %s", ports[i], ports[j])))
+		}
+	}
+
+	return allErrs
+}
+
+// metricsPort returns the metrics port of the policy server pods configured
+// in the validator options, or the default one.
+func (v *policyServerValidator) metricsPort() int32 {
+	if v.options.MetricsPort != 0 {
+		return v.options.MetricsPort
+	}
+	return constants.PolicyServerMetricsPort
+}
+
 // validateLimitsAndRequests validates that the specified PolicyServer limits and requests are not negative and requests are less than or equal to limits.
 func validateLimitsAndRequests(limits, requests corev1.ResourceList) field.ErrorList {
 	var allErrs field.ErrorList
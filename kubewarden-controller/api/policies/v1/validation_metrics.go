@@ -0,0 +1,62 @@
+package v1
+
+import (
+	"context"
+	"regexp"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// The metrics are recorded with the global meter provider, which is
+// initialized by the internal/metrics package only when the metrics are
+// enabled. The internal/metrics package cannot be used here because it
+// depends on this package.
+const (
+	meterName                             = "kubewarden"
+	validationRejectionsMetricName        = "kubewarden_validation_rejections_total"
+	validationRejectionsMetricDescription = "How many objects have been rejected by the validating webhooks of the controller"
+)
+
+// Regex matching the indexes and keys of a field path.
+//
+//nolint:gochecknoglobals // Using a global variable to avoid recompiling the regex at every rejection
+var fieldPathSubscriptRegex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// recordValidationRejection increments the validation rejections counter for
+// the kind of the rejected object, once for each rejection reason of the
+// errors.
+func recordValidationRejection(ctx context.Context, kind string, allErrors field.ErrorList) {
+	meter := otel.Meter(meterName)
+	// The meter returns a no-op instrument on error, which is fine: a metric
+	// failure must not change the outcome of the validation.
+	counter, _ := meter.Int64Counter(validationRejectionsMetricName, metric.WithDescription(validationRejectionsMetricDescription))
+
+	for _, reason := range validationRejectionReasons(allErrors) {
+		counter.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("kind", kind),
+			attribute.String("reason", reason),
+		))
+	}
+}
+
+// validationRejectionReasons returns the sorted distinct reasons of the
+// errors: the path of the invalid field, without indexes and keys to bound the
+// cardinality of the metric, or the type of the error when the error is not
+// about a field.
+func validationRejectionReasons(allErrors field.ErrorList) []string {
+	reasons := sets.New[string]()
+	for _, err := range allErrors {
+		// A nil field path is rendered as "<nil>"
+		if err.Field == "" || err.Field == "<nil>" {
+			reasons.Insert(string(err.Type))
+			continue
+		}
+		reasons.Insert(fieldPathSubscriptRegex.ReplaceAllString(err.Field, ""))
+	}
+
+	return sets.List(reasons)
+}
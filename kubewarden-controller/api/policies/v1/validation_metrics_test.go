@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	metricSDK "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+func TestRecordValidationRejection(t *testing.T) {
+	reader := metricSDK.NewManualReader()
+	meterProvider := metricSDK.NewMeterProvider(metricSDK.WithReader(reader))
+	otel.SetMeterProvider(meterProvider)
+	t.Cleanup(func() {
+		_ = meterProvider.Shutdown(t.Context())
+	})
+
+	policyServerValidator := policyServerValidator{logger: logr.Discard()}
+	policyServer := NewPolicyServerFactory().
+		WithName(strings.Repeat("a", 50)).
+		WithMaxUnavailable(ptr.To(intstr.FromInt(2))).
+		WithMinAvailable(ptr.To(intstr.FromInt(2))).
+		Build()
+	_, err := policyServerValidator.ValidateCreate(t.Context(), policyServer)
+	require.Error(t, err)
+
+	clusterAdmissionPolicyValidator := clusterAdmissionPolicyValidator{logger: logr.Discard()}
+	_, err = clusterAdmissionPolicyValidator.ValidateCreate(t.Context(), NewClusterAdmissionPolicyFactory().WithRules(nil).Build())
+	require.Error(t, err)
+	_, err = clusterAdmissionPolicyValidator.ValidateCreate(t.Context(), NewClusterAdmissionPolicyFactory().WithRules(nil).Build())
+	require.Error(t, err)
+
+	var resourceMetrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &resourceMetrics))
+	require.Len(t, resourceMetrics.ScopeMetrics, 1)
+	require.Len(t, resourceMetrics.ScopeMetrics[0].Metrics, 1)
+
+	recordedMetric := resourceMetrics.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, validationRejectionsMetricName, recordedMetric.Name)
+
+	sum, ok := recordedMetric.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+
+	counts := make(map[[2]string]int64)
+	for _, dataPoint := range sum.DataPoints {
+		kind, _ := dataPoint.Attributes.Value(attribute.Key("kind"))
+		reason, _ := dataPoint.Attributes.Value(attribute.Key("reason"))
+		counts[[2]string{kind.AsString(), reason.AsString()}] = dataPoint.Value
+	}
+	assert.Equal(t, map[[2]string]int64{
+		{"PolicyServer", "metadata.name"}:        1,
+		{"PolicyServer", "spec"}:                 1,
+		{"ClusterAdmissionPolicy", "spec.rules"}: 2,
+	}, counts)
+}
+
+func TestValidationRejectionReasons(t *testing.T) {
+	allErrors := field.ErrorList{
+		field.Invalid(field.NewPath("spec").Child("env").Index(0).Child("name"), "foo-bar", "invalid"),
+		field.Duplicate(field.NewPath("spec").Child("env").Index(3).Child("name"), "FOO"),
+		field.Invalid(field.NewPath("spec").Child("policies").Key("foo"), "foo", "invalid"),
+		field.InternalError(nil, assert.AnError),
+	}
+
+	assert.Equal(t, []string{"InternalError", "spec.env.name", "spec.policies"}, validationRejectionReasons(allErrors))
+}
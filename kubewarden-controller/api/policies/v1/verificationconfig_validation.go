@@ -0,0 +1,225 @@
+package v1
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	verificationConfigAPIVersion = "v1"
+
+	signatureKindPubKey        = "pubKey"
+	signatureKindGenericIssuer = "genericIssuer"
+	signatureKindGithubAction  = "githubAction"
+	signatureKindCertificate   = "certificate"
+)
+
+// verificationConfig is the Sigstore verification configuration read by the
+// policy server. The signatures are decoded according to their kind.
+// +kubebuilder:object:generate=false
+type verificationConfig struct {
+	APIVersion string                   `json:"apiVersion"`
+	AllOf      []json.RawMessage        `json:"allOf,omitempty"`
+	AnyOf      *verificationConfigAnyOf `json:"anyOf,omitempty"`
+}
+
+// +kubebuilder:object:generate=false
+type verificationConfigAnyOf struct {
+	MinimumMatches *int              `json:"minimumMatches,omitempty"`
+	Signatures     []json.RawMessage `json:"signatures"`
+}
+
+// +kubebuilder:object:generate=false
+type pubKeySignature struct {
+	Kind        string            `json:"kind"`
+	Owner       string            `json:"owner,omitempty"`
+	Key         string            `json:"key"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// +kubebuilder:object:generate=false
+type genericIssuerSignature struct {
+	Kind        string               `json:"kind"`
+	Issuer      string               `json:"issuer"`
+	Subject     genericIssuerSubject `json:"subject"`
+	Annotations map[string]string    `json:"annotations,omitempty"`
+}
+
+// +kubebuilder:object:generate=false
+type genericIssuerSubject struct {
+	Equal     string `json:"equal,omitempty"`
+	URLPrefix string `json:"urlPrefix,omitempty"`
+}
+
+// +kubebuilder:object:generate=false
+type githubActionSignature struct {
+	Kind        string            `json:"kind"`
+	Owner       string            `json:"owner"`
+	Repo        string            `json:"repo,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// +kubebuilder:object:generate=false
+type certificateSignature struct {
+	Kind               string            `json:"kind"`
+	Certificate        string            `json:"certificate"`
+	CertificateChain   []string          `json:"certificateChain,omitempty"`
+	RequireRekorBundle *bool             `json:"requireRekorBundle,omitempty"`
+	Annotations        map[string]string `json:"annotations,omitempty"`
+}
+
+// validateVerificationConfig checks that the given value is a valid Sigstore
+// verification configuration. The paths of the returned errors are relative
+// to the given path of the configuration.
+func validateVerificationConfig(path *field.Path, value string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	var config verificationConfig
+	if err := yaml.UnmarshalStrict([]byte(value), &config); err != nil {
+		return append(allErrs, field.Invalid(path, field.OmitValueType{}, fmt.Sprintf("cannot parse the verification config: %v", err)))
+	}
+
+	if config.APIVersion != verificationConfigAPIVersion {
+		allErrs = append(allErrs, field.NotSupported(path.Child("apiVersion"), config.APIVersion, []string{verificationConfigAPIVersion}))
+	}
+
+	if len(config.AllOf) == 0 && (config.AnyOf == nil || len(config.AnyOf.Signatures) == 0) {
+		allErrs = append(allErrs, field.Required(path, "at least a signature must be set in allOf or anyOf"))
+	}
+
+	for i, signature := range config.AllOf {
+		allErrs = append(allErrs, validateSignature(path.Child("allOf").Index(i), signature)...)
+	}
+
+	if config.AnyOf != nil {
+		anyOfPath := path.Child("anyOf")
+		for i, signature := range config.AnyOf.Signatures {
+			allErrs = append(allErrs, validateSignature(anyOfPath.Child("signatures").Index(i), signature)...)
+		}
+		if minimumMatches := config.AnyOf.MinimumMatches; minimumMatches != nil && (*minimumMatches < 1 || *minimumMatches > len(config.AnyOf.Signatures)) {
+			allErrs = append(allErrs, field.Invalid(anyOfPath.Child("minimumMatches"), *minimumMatches, fmt.Sprintf("must be between 1 and the number of signatures (%d)", len(config.AnyOf.Signatures))))
+		}
+	}
+
+	return allErrs
+}
+
+// validateSignature decodes the given signature according to its kind and
+// validates its fields.
+func validateSignature(path *field.Path, rawSignature json.RawMessage) field.ErrorList {
+	var signature struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(rawSignature, &signature); err != nil {
+		return field.ErrorList{field.Invalid(path, field.OmitValueType{}, fmt.Sprintf("cannot parse the signature: %v", err))}
+	}
+
+	switch signature.Kind {
+	case signatureKindPubKey:
+		var pubKey pubKeySignature
+		if err := decodeSignature(rawSignature, &pubKey); err != nil {
+			return field.ErrorList{field.Invalid(path, field.OmitValueType{}, err.Error())}
+		}
+		return validatePubKeySignature(path, pubKey)
+	case signatureKindGenericIssuer:
+		var genericIssuer genericIssuerSignature
+		if err := decodeSignature(rawSignature, &genericIssuer); err != nil {
+			return field.ErrorList{field.Invalid(path, field.OmitValueType{}, err.Error())}
+		}
+		return validateGenericIssuerSignature(path, genericIssuer)
+	case signatureKindGithubAction:
+		var githubAction githubActionSignature
+		if err := decodeSignature(rawSignature, &githubAction); err != nil {
+			return field.ErrorList{field.Invalid(path, field.OmitValueType{}, err.Error())}
+		}
+		if githubAction.Owner == "" {
+			return field.ErrorList{field.Required(path.Child("owner"), "")}
+		}
+		return nil
+	case signatureKindCertificate:
+		var certificate certificateSignature
+		if err := decodeSignature(rawSignature, &certificate); err != nil {
+			return field.ErrorList{field.Invalid(path, field.OmitValueType{}, err.Error())}
+		}
+		return validateCertificateSignature(path, certificate)
+	default:
+		return field.ErrorList{field.NotSupported(path.Child("kind"), signature.Kind, []string{
+			signatureKindPubKey, signatureKindGenericIssuer, signatureKindGithubAction, signatureKindCertificate,
+		})}
+	}
+}
+
+// decodeSignature decodes the given signature, rejecting the fields not
+// supported by its kind.
+func decodeSignature(rawSignature json.RawMessage, signature any) error {
+	decoder := json.NewDecoder(bytes.NewReader(rawSignature))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(signature); err != nil {
+		return fmt.Errorf("cannot parse the signature: %w", err)
+	}
+
+	return nil
+}
+
+func validatePubKeySignature(path *field.Path, signature pubKeySignature) field.ErrorList {
+	if signature.Key == "" {
+		return field.ErrorList{field.Required(path.Child("key"), "")}
+	}
+
+	block, rest := pem.Decode([]byte(signature.Key))
+	if block == nil || block.Type != "PUBLIC KEY" || len(bytes.TrimSpace(rest)) > 0 {
+		return field.ErrorList{field.Invalid(path.Child("key"), field.OmitValueType{}, "must be a single PEM encoded public key")}
+	}
+	if _, err := x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+		return field.ErrorList{field.Invalid(path.Child("key"), field.OmitValueType{}, fmt.Sprintf("cannot parse the public key: %v", err))}
+	}
+
+	return nil
+}
+
+func validateGenericIssuerSignature(path *field.Path, signature genericIssuerSignature) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if signature.Issuer == "" {
+		allErrs = append(allErrs, field.Required(path.Child("issuer"), ""))
+	}
+
+	subjectPath := path.Child("subject")
+	switch {
+	case signature.Subject.Equal == "" && signature.Subject.URLPrefix == "":
+		allErrs = append(allErrs, field.Required(subjectPath, "one of equal or urlPrefix must be set"))
+	case signature.Subject.Equal != "" && signature.Subject.URLPrefix != "":
+		allErrs = append(allErrs, field.Invalid(subjectPath, field.OmitValueType{}, "only one of equal or urlPrefix can be set"))
+	case signature.Subject.URLPrefix != "":
+		if prefix, err := url.Parse(signature.Subject.URLPrefix); err != nil || prefix.Scheme == "" || prefix.Host == "" {
+			allErrs = append(allErrs, field.Invalid(subjectPath.Child("urlPrefix"), signature.Subject.URLPrefix, "must be an absolute URL"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateCertificateSignature(path *field.Path, signature certificateSignature) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if signature.Certificate == "" {
+		allErrs = append(allErrs, field.Required(path.Child("certificate"), ""))
+	} else if err := validatePEMCertificates(signature.Certificate); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("certificate"), field.OmitValueType{}, err.Error()))
+	}
+
+	for i, certificate := range signature.CertificateChain {
+		if err := validatePEMCertificates(certificate); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("certificateChain").Index(i), field.OmitValueType{}, err.Error()))
+		}
+	}
+
+	return allErrs
+}
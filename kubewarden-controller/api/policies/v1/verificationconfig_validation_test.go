@@ -0,0 +1,186 @@
+package v1
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateVerificationConfig(t *testing.T) {
+	publicKey := indent(generateTestPublicKey(t), 8)
+	certificate := indent(generateTestCertificate(t), 8)
+
+	tests := []struct {
+		name   string
+		config string
+		errors []string
+	}{
+		{
+			name: "valid config",
+			config: `apiVersion: v1
+allOf:
+  - kind: githubAction
+    owner: kubewarden
+    repo: policies
+  - kind: pubKey
+    owner: kubewarden
+    key: |
+` + publicKey + `
+    annotations:
+      env: prod
+anyOf:
+  minimumMatches: 2
+  signatures:
+    - kind: genericIssuer
+      issuer: https://token.actions.githubusercontent.com
+      subject:
+        urlPrefix: https://github.com/kubewarden/
+    - kind: genericIssuer
+      issuer: https://github.com/login/oauth
+      subject:
+        equal: user@example.com
+    - kind: certificate
+      certificate: |
+` + certificate + `
+      requireRekorBundle: true
+`,
+			errors: nil,
+		},
+		{
+			name:   "malformed YAML",
+			config: "apiVersion: v1\nallOf: [",
+			errors: []string{"data[verification-config]: Invalid value: cannot parse the verification config"},
+		},
+		{
+			name:   "unknown field",
+			config: "apiVersion: v1\noneOf: []\n",
+			errors: []string{`data[verification-config]: Invalid value: cannot parse the verification config: error unmarshaling JSON: while decoding JSON: json: unknown field "oneOf"`},
+		},
+		{
+			name:   "unsupported version and no signatures",
+			config: "apiVersion: v2\n",
+			errors: []string{
+				`data[verification-config].apiVersion: Unsupported value: "v2": supported values: "v1"`,
+				"data[verification-config]: Required value: at least a signature must be set in allOf or anyOf",
+			},
+		},
+		{
+			name: "unknown signature kind",
+			config: `apiVersion: v1
+allOf:
+  - kind: keyless
+`,
+			errors: []string{`data[verification-config].allOf[0].kind: Unsupported value: "keyless"`},
+		},
+		{
+			name: "field of another kind",
+			config: `apiVersion: v1
+allOf:
+  - kind: githubAction
+    owner: kubewarden
+    issuer: https://token.actions.githubusercontent.com
+`,
+			errors: []string{`data[verification-config].allOf[0]: Invalid value: cannot parse the signature: json: unknown field "issuer"`},
+		},
+		{
+			name: "missing required fields",
+			config: `apiVersion: v1
+allOf:
+  - kind: pubKey
+  - kind: githubAction
+  - kind: genericIssuer
+  - kind: certificate
+`,
+			errors: []string{
+				"data[verification-config].allOf[0].key: Required value",
+				"data[verification-config].allOf[1].owner: Required value",
+				"data[verification-config].allOf[2].issuer: Required value",
+				"data[verification-config].allOf[2].subject: Required value: one of equal or urlPrefix must be set",
+				"data[verification-config].allOf[3].certificate: Required value",
+			},
+		},
+		{
+			name: "invalid key, subject and certificate",
+			config: `apiVersion: v1
+anyOf:
+  minimumMatches: 4
+  signatures:
+    - kind: pubKey
+      key: not a key
+    - kind: genericIssuer
+      issuer: https://token.actions.githubusercontent.com
+      subject:
+        urlPrefix: kubewarden
+    - kind: genericIssuer
+      issuer: https://token.actions.githubusercontent.com
+      subject:
+        equal: user@example.com
+        urlPrefix: https://github.com/kubewarden/
+    - kind: certificate
+      certificate: not a certificate
+`,
+			errors: []string{
+				"data[verification-config].anyOf.signatures[0].key: Invalid value: must be a single PEM encoded public key",
+				`data[verification-config].anyOf.signatures[1].subject.urlPrefix: Invalid value: "kubewarden": must be an absolute URL`,
+				"data[verification-config].anyOf.signatures[2].subject: Invalid value: only one of equal or urlPrefix can be set",
+				"data[verification-config].anyOf.signatures[3].certificate: Invalid value: no PEM encoded certificate found",
+			},
+		},
+		{
+			name: "minimum matches greater than the signatures",
+			config: `apiVersion: v1
+anyOf:
+  minimumMatches: 2
+  signatures:
+    - kind: githubAction
+      owner: kubewarden
+`,
+			errors: []string{"data[verification-config].anyOf.minimumMatches: Invalid value: 2: must be between 1 and the number of signatures (1)"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := validateVerificationConfig(field.NewPath("data").Key("verification-config"), test.config)
+
+			if len(test.errors) == 0 {
+				require.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, len(test.errors))
+			for i, expectedError := range test.errors {
+				assert.Contains(t, errs[i].Error(), expectedError)
+			}
+		})
+	}
+}
+
+func generateTestPublicKey(t *testing.T) string {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))
+}
+
+// indent indents all the lines of the given value, to embed it in a YAML
+// block scalar.
+func indent(value string, spaces int) string {
+	lines := strings.Split(strings.TrimSpace(value), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Repeat(" ", spaces) + line
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -21,23 +21,32 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	traceSDK "go.opentelemetry.io/otel/sdk/trace"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	k8spoliciesv1 "k8s.io/api/policy/v1"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -46,13 +55,42 @@ import (
 
 	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
 	"github.com/kubewarden/kubewarden-controller/api/policies/v1alpha2"
+	"github.com/kubewarden/kubewarden-controller/internal/certs"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
 	"github.com/kubewarden/kubewarden-controller/internal/controller"
 	"github.com/kubewarden/kubewarden-controller/internal/featuregates"
 	"github.com/kubewarden/kubewarden-controller/internal/metrics"
+	"github.com/kubewarden/kubewarden-controller/internal/tracing"
 	//+kubebuilder:scaffold:imports
 )
 
+const (
+	// Same defaults used by controller-runtime when the client configuration
+	// does not set any rate limit.
+	defaultKubeAPIQPS   = 20.0
+	defaultKubeAPIBurst = 30
+	// Same default used by controller-runtime, the objects of a controller
+	// are reconciled one at a time.
+	defaultMaxConcurrentReconciles = 1
+	// Short enough to not delay the activation of a single policy, long
+	// enough to coalesce the policies applied by a single kubectl or helm
+	// invocation.
+	defaultPolicyChangesDebounce = 2 * time.Second
+	// Larger policy groups are almost always a mistake, their expression
+	// becomes hard to reason about.
+	defaultPolicyGroupMaxMembers = 32
+	// Same leader election defaults used by controller-runtime.
+	defaultLeaderElectionID            = "a4ddbf36.kubewarden.io"
+	defaultLeaderElectionLeaseDuration = 15 * time.Second
+	defaultLeaderElectionRenewDeadline = 10 * time.Second
+	defaultLeaderElectionRetryPeriod   = 2 * time.Second
+	// Same default used by controller-runtime.
+	defaultGracefulShutdownTimeout = 30 * time.Second
+	// Sampling one trace out of ten keeps the volume of the traces of the
+	// busy Policy Servers manageable.
+	defaultTracingSamplerRatio = 0.1
+)
+
 //nolint:gochecknoglobals // Following the kubebuilder pattern
 var (
 	scheme   = runtime.NewScheme()
@@ -60,18 +98,57 @@ var (
 )
 
 type ManagerOptions struct {
-	DeploymentsNamespace string
-	EnableLeaderElection bool
-	EnableMutualTLS      bool
-	MetricsAddr          string
-	ProbeAddr            string
+	DeploymentsNamespace        string
+	EnableLeaderElection        bool
+	EnableMutualTLS             bool
+	EnableTracing               bool
+	GracefulShutdownTimeout     time.Duration
+	LeaderElectionID            string
+	LeaderElectionNamespace     string
+	LeaderElectionLeaseDuration time.Duration
+	LeaderElectionRenewDeadline time.Duration
+	LeaderElectionRetryPeriod   time.Duration
+	MetricsAddr                 string
+	ProbeAddr                   string
+	KubeAPIQPS                  float64
+	KubeAPIBurst                int
+	WebhookPort                 int
 }
 
 type Configuration struct {
+	ActivePoliciesConfigMapName                        string
+	AllowPolicyServerLoadBalancerServices              bool
 	AlwaysAcceptAdmissionReviewsOnDeploymentsNamespace bool
+	CACertDuration                                     time.Duration
+	CertExpirationWarningThreshold                     time.Duration
+	CertKeyAlgorithm                                   string
+	CertRenewalWindow                                  time.Duration
 	ClientCAConfigMapName                              string
+	EnableAdmissionPolicyController                    bool
+	EnableAdmissionPolicyGroupController               bool
+	EnableCertController                               bool
+	EnableClusterAdmissionPolicyController             bool
+	EnableClusterAdmissionPolicyGroupController        bool
+	EnablePolicyServerController                       bool
 	FeatureGateAdmissionWebhookMatchConditions         bool
+	ManageNetworkPolicies                              bool
+	NamespaceAllowList                                 string
+	NetworkPolicyAPIServerCIDRs                        string
+	NetworkPolicyMonitoringNamespace                   string
+	ServerCertDuration                                 time.Duration
+	RejectClusterScopedResourcesInAdmissionPolicies    bool
+	PolicyChangesDebounce                              time.Duration
+	PolicyGroupMaxMembers                              int
+	PolicyMaxConcurrentReconciles                      int
+	PolicyServerDefaultMaxUnavailable                  string
+	PolicyServerEnvDenyList                            string
+	RejectPolicyServerDeniedEnv                        bool
+	PolicyServerMaxConcurrentReconciles                int
+	PolicyServerRequiredResources                      string
+	PolicyServerRestartGracePeriod                     time.Duration
+	PolicyServerValidationLookupRetries                int
 	WebhookServiceName                                 string
+	ZoneAntiAffinity                                   bool
 }
 
 func init() {
@@ -89,22 +166,51 @@ func main() {
 	var mgrOpts ManagerOptions
 	var config Configuration
 	var enableMetrics bool
+	var metricsExportInterval time.Duration
 	var enableTracing bool
+	var tracingSampler string
+	var tracingSamplerRatio float64
 	var enableOtelSidecar bool
+	var enableServiceMonitor bool
+	var enablePodMonitor bool
 	var openTelemetryClientCertificateSecret string
 	var openTelemetryCertificateSecret string
 
 	flag.StringVar(&mgrOpts.MetricsAddr, "metrics-bind-address", ":8088", "The address the metric endpoint binds to.")
 	flag.StringVar(&mgrOpts.ProbeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.IntVar(&mgrOpts.WebhookPort, "webhook-port", webhook.DefaultPort,
+		"The port the webhook server of the controller binds to. The targetPort of the webhook Service must match it.")
 	flag.BoolVar(&mgrOpts.EnableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&mgrOpts.LeaderElectionID, "leader-election-id", defaultLeaderElectionID,
+		"The name of the leader election Lease. The instances of the controller running different controllers must use different names, otherwise only one of them is active.")
+	flag.StringVar(&mgrOpts.LeaderElectionNamespace, "leader-election-namespace", "",
+		"The namespace where the leader election Lease is created. The namespace the controller runs in is used when empty.")
+	flag.DurationVar(&mgrOpts.LeaderElectionLeaseDuration, "leader-election-lease-duration", defaultLeaderElectionLeaseDuration,
+		"The duration the non-leader candidates wait before trying to acquire the leadership. It must be longer than the leader-election-renew-deadline.")
+	flag.DurationVar(&mgrOpts.LeaderElectionRenewDeadline, "leader-election-renew-deadline", defaultLeaderElectionRenewDeadline,
+		"The duration the leader retries to renew the leadership before giving it up. It must be longer than the leader-election-retry-period.")
+	flag.DurationVar(&mgrOpts.LeaderElectionRetryPeriod, "leader-election-retry-period", defaultLeaderElectionRetryPeriod,
+		"The duration the leader election candidates wait between two attempts to acquire or renew the leadership.")
+	flag.DurationVar(&mgrOpts.GracefulShutdownTimeout, "graceful-shutdown-timeout", defaultGracefulShutdownTimeout,
+		"The time given to the in-flight reconciliations to complete when the controller is stopped, before the metrics are flushed and the controller exits. Set to 0 to exit without waiting.")
 	flag.BoolVar(&enableMetrics, "enable-metrics", false,
 		"Enable metrics collection for all Policy Servers and the Kubewarden Controller")
+	flag.DurationVar(&metricsExportInterval, "metrics-export-interval", metrics.DefaultExportInterval,
+		"The interval between two exports of the controller metrics to the OpenTelemetry collector.")
 	flag.BoolVar(&enableTracing, "enable-tracing", false,
-		"Enable tracing collection for all Policy Servers")
+		"Enable tracing collection for all Policy Servers and the Kubewarden Controller")
+	flag.StringVar(&tracingSampler, "tracing-sampler", controller.TracingSamplerRatio,
+		"The sampler of the traces of the Policy Servers: always, never or ratio. The samplers, but never, honor the sampling decision of the parent span.")
+	flag.Float64Var(&tracingSamplerRatio, "tracing-sampler-ratio", defaultTracingSamplerRatio,
+		"The ratio, between 0 and 1, of the traces sampled by the ratio tracing-sampler.")
 	flag.BoolVar(&enableOtelSidecar, "enable-otel-sidecar", false,
 		"Enable OpenTelemetry sidecar in Policy Servers")
+	flag.BoolVar(&enableServiceMonitor, "enable-service-monitor", false,
+		"Create a Prometheus Operator ServiceMonitor for each Policy Server. Requires metrics to be enabled")
+	flag.BoolVar(&enablePodMonitor, "enable-pod-monitor", false,
+		"Create a Prometheus Operator PodMonitor for each Policy Server, scraping its pods directly. Requires metrics to be enabled")
 	flag.StringVar(&openTelemetryClientCertificateSecret, "opentelemetry-client-certificate-secret", "", "")
 	flag.StringVar(&openTelemetryCertificateSecret, "opentelemetry-certificate-secret", "", "")
 	flag.StringVar(&mgrOpts.DeploymentsNamespace,
@@ -120,15 +226,128 @@ func main() {
 		false,
 		"Always accept admission reviews targeting the deployments-namespace.")
 	flag.StringVar(&config.ClientCAConfigMapName, "client-ca-configmap-name", "", "The name of the ConfigMap containing the client CA certificate. If provided, mTLS will be enabled.")
+	flag.BoolVar(&config.RejectClusterScopedResourcesInAdmissionPolicies,
+		"reject-cluster-scoped-resources-in-admission-policies",
+		false,
+		"Reject AdmissionPolicies targeting cluster-scoped resources instead of accepting them with a warning.")
+	flag.StringVar(&config.PolicyServerEnvDenyList,
+		"policy-server-env-deny-list",
+		strings.Join(policiesv1.DefaultPolicyServerEnvDenyList(), ","),
+		"Comma separated list of environment variables that weaken the security of the Policy Servers when set in their spec.env field.")
+	flag.BoolVar(&config.RejectPolicyServerDeniedEnv,
+		"reject-policy-server-denied-env",
+		false,
+		"Reject PolicyServers setting an environment variable of the deny-list instead of accepting them with a warning.")
+	flag.BoolVar(&config.AllowPolicyServerLoadBalancerServices,
+		"allow-policy-server-load-balancer-services",
+		false,
+		"Accept PolicyServers exposed by a LoadBalancer Service with a warning instead of rejecting them.")
+	flag.StringVar(&config.PolicyServerDefaultMaxUnavailable,
+		"policy-server-default-max-unavailable",
+		"",
+		"The percentage, e.g. 50%, set as maxUnavailable of the PolicyServers running more than one replica without minAvailable and maxUnavailable, to protect them with a PodDisruptionBudget. No default is set when empty.")
+	flag.StringVar(&config.PolicyServerRequiredResources,
+		"required-resources",
+		"",
+		"Comma separated list of resource names, e.g. cpu,memory, that must be set in both the requests and the limits of the Policy Servers.")
+	flag.IntVar(&config.PolicyGroupMaxMembers,
+		"policy-group-max-members",
+		defaultPolicyGroupMaxMembers,
+		"The maximum number of policies of a policy group. The creation of larger groups is rejected. Set to 0 to disable the limit.")
+	flag.IntVar(&config.PolicyServerValidationLookupRetries,
+		"policy-server-validation-lookup-retries",
+		constants.DefaultPolicyServerValidationLookupRetries,
+		"The number of times the lookups of the Secrets and ConfigMaps referenced by a PolicyServer are retried with backoff when validating the PolicyServer, to tolerate the objects missing from the cache right after their creation. Set to 0 to disable the retries.")
+	flag.DurationVar(&config.PolicyServerRestartGracePeriod,
+		"policy-server-restart-grace-period",
+		constants.DefaultPolicyServerRestartGracePeriod,
+		"The time during which the status of an active policy is held as reconciling after its Policy Server restarts. Set to 0 to disable it.")
+	flag.DurationVar(&config.CertExpirationWarningThreshold,
+		"cert-expiration-warning-threshold",
+		constants.DefaultCertExpirationWarningThreshold,
+		"The time before the expiration of a Policy Server certificate after which the CertificateValid condition of the Policy Server is set to false.")
+	flag.DurationVar(&config.CertRenewalWindow,
+		"cert-renewal-window",
+		constants.CertLookahead,
+		"The time before the expiration of the CA root and of the server certificates after which the certificates are rotated. It must be shorter than the ca-cert-duration and the server-cert-duration, a certificate is valid for its duration minus the renewal window before being rotated.")
+	flag.StringVar(&config.CertKeyAlgorithm,
+		"cert-key-algorithm",
+		string(certs.DefaultKeyAlgorithm),
+		"The algorithm of the private keys of the certificates generated by the controller: rsa, ecdsa-p256 or ecdsa-p384. The CA root and the server certificates using a different algorithm are rotated.")
+	flag.DurationVar(&config.CACertDuration,
+		"ca-cert-duration",
+		constants.CACertExpiration,
+		"The validity of the CA root certificates generated by the controller. It must be longer than the cert-renewal-window.")
+	flag.DurationVar(&config.ServerCertDuration,
+		"server-cert-duration",
+		constants.ServerCertExpiration,
+		"The validity of the webhook server and Policy Server certificates generated by the controller. It must be longer than the cert-renewal-window.")
+	flag.StringVar(&config.ActivePoliciesConfigMapName,
+		"active-policies-configmap-name",
+		"",
+		"The name of a ConfigMap, created in the deployments-namespace, listing the active policies and their Policy Servers. The ConfigMap is not created when empty. It is maintained only when the PolicyServer controller is enabled.")
+	flag.BoolVar(&config.ManageNetworkPolicies,
+		"manage-network-policies",
+		false,
+		"Create a NetworkPolicy for each Policy Server, allowing the ingress traffic to the webhook port only from the Kubernetes API server and the audit scanner, and to the metrics port only from the monitoring namespace.")
+	flag.StringVar(&config.NetworkPolicyAPIServerCIDRs,
+		"network-policy-api-server-cidrs",
+		"",
+		"Comma separated list of the CIDRs of the Kubernetes API server, allowed to reach the webhook port of the Policy Servers. Required when manage-network-policies is set.")
+	flag.StringVar(&config.NetworkPolicyMonitoringNamespace,
+		"network-policy-monitoring-namespace",
+		"monitoring",
+		"The namespace allowed to reach the metrics port of the Policy Servers when manage-network-policies is set.")
+	flag.StringVar(&config.NamespaceAllowList,
+		"namespace-allowlist",
+		"",
+		"Comma separated list of the namespaces whose AdmissionPolicies are reconciled. The webhooks of the AdmissionPolicies of the other namespaces are removed, the policies are not loaded by the Policy Servers and report the PolicyNamespaceNotAllowed condition. The AdmissionPolicies of all the namespaces are reconciled when empty.")
+	flag.BoolVar(&config.ZoneAntiAffinity,
+		"zone-anti-affinity",
+		false,
+		"Spread the replicas of the Policy Servers across the availability zones when the nodes are labeled with topology.kubernetes.io/zone. The pod anti-affinity set in a PolicyServer always takes precedence.")
+	flag.Float64Var(&mgrOpts.KubeAPIQPS, "kube-api-qps", defaultKubeAPIQPS,
+		"The maximum queries per second sent by the controller to the Kubernetes API server.")
+	flag.IntVar(&mgrOpts.KubeAPIBurst, "kube-api-burst", defaultKubeAPIBurst,
+		"The maximum burst of queries sent by the controller to the Kubernetes API server.")
+	// The same object is never reconciled by two workers at the same time.
+	// The policies write only their own webhook configurations and status,
+	// the ConfigMap shared by the policies of a Policy Server is written only
+	// by the reconciliation of the Policy Server itself. The certificates and
+	// the active policies ConfigMap are always reconciled by a single worker,
+	// because they are shared by all the Policy Servers.
+	flag.IntVar(&config.PolicyServerMaxConcurrentReconciles, "policy-server-max-concurrent-reconciles", defaultMaxConcurrentReconciles,
+		"The maximum number of Policy Servers reconciled concurrently.")
+	flag.IntVar(&config.PolicyMaxConcurrentReconciles, "policy-max-concurrent-reconciles", defaultMaxConcurrentReconciles,
+		"The maximum number of policies reconciled concurrently by each of the AdmissionPolicy, ClusterAdmissionPolicy, AdmissionPolicyGroup and ClusterAdmissionPolicyGroup controllers.")
+	flag.DurationVar(&config.PolicyChangesDebounce, "policy-changes-debounce", defaultPolicyChangesDebounce,
+		"The time window during which the changes of the policies are coalesced in a single update of the configuration of their Policy Server, reducing the Policy Server reloads when many policies are applied at once. Set to 0 to update the configuration at every change.")
+
+	// The controllers can be split across several instances of the
+	// controller. Every controller must be enabled in exactly one of them,
+	// and each instance must use its own leader election ID.
+	flag.BoolVar(&config.EnablePolicyServerController, "enable-policyserver-controller", true,
+		"Enable the controller of the PolicyServers.")
+	flag.BoolVar(&config.EnableAdmissionPolicyController, "enable-admissionpolicy-controller", true,
+		"Enable the controller of the AdmissionPolicies.")
+	flag.BoolVar(&config.EnableClusterAdmissionPolicyController, "enable-clusteradmissionpolicy-controller", true,
+		"Enable the controller of the ClusterAdmissionPolicies.")
+	flag.BoolVar(&config.EnableAdmissionPolicyGroupController, "enable-admissionpolicygroup-controller", true,
+		"Enable the controller of the AdmissionPolicyGroups.")
+	flag.BoolVar(&config.EnableClusterAdmissionPolicyGroupController, "enable-clusteradmissionpolicygroup-controller", true,
+		"Enable the controller of the ClusterAdmissionPolicyGroups.")
+	flag.BoolVar(&config.EnableCertController, "enable-cert-controller", true,
+		"Enable the controller rotating the CA root and the webhook server certificates.")
 
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 	mgrOpts.EnableMutualTLS = config.ClientCAConfigMapName != ""
+	mgrOpts.EnableTracing = enableTracing
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
 	if enableMetrics {
-		shutdown, err := metrics.New()
+		shutdown, err := metrics.New(metricsExportInterval)
 		if err != nil {
 			setupLog.Error(err, "unable to initialize metrics provider")
 			retcode = 1
@@ -150,6 +369,35 @@ func main() {
 		}()
 	}
 
+	if err := validateTracingSampler(tracingSampler, tracingSamplerRatio); err != nil {
+		setupLog.Error(err, "invalid tracing sampler")
+		retcode = 1
+		return
+	}
+
+	if enableTracing {
+		shutdown, err := tracing.New(newTracesSampler(tracingSampler, tracingSamplerRatio))
+		if err != nil {
+			setupLog.Error(err, "unable to initialize tracer provider")
+			retcode = 1
+			return
+		}
+		setupLog.Info("Tracing initialized")
+
+		// flush the spans on application exit
+		defer func() {
+			// Do not make the application hang when it is shutdown.
+			ctx, cancel := context.WithTimeout(context.Background(), constants.MetricsShutdownTimeout)
+			defer cancel()
+
+			if err := shutdown(ctx); err != nil { //nolint:govet // err is shadowed in purpose
+				setupLog.Error(err, "Unable to shutdown tracing")
+				retcode = 1
+				return
+			}
+		}()
+	}
+
 	mgr, err := setupManager(mgrOpts)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -165,9 +413,13 @@ func main() {
 	otelConfiguration := controller.TelemetryConfiguration{
 		MetricsEnabled:              enableMetrics,
 		TracingEnabled:              enableTracing,
+		TracingSampler:              tracingSampler,
+		TracingSamplerRatio:         tracingSamplerRatio,
 		OtelSidecarEnabled:          enableOtelSidecar,
 		OtelCertificateSecret:       openTelemetryCertificateSecret,
 		OtelClientCertificateSecret: openTelemetryClientCertificateSecret,
+		ServiceMonitorEnabled:       enableServiceMonitor,
+		PodMonitorEnabled:           enablePodMonitor,
 	}
 	if err = setupReconcilers(mgr,
 		mgrOpts.DeploymentsNamespace,
@@ -179,12 +431,24 @@ func main() {
 		return
 	}
 
-	if err = setupWebhooks(mgr, mgrOpts.DeploymentsNamespace); err != nil {
+	if err = setupWebhooks(mgr, mgrOpts.DeploymentsNamespace, config, otelConfiguration); err != nil {
 		setupLog.Error(err, "unable to create webhooks")
 		retcode = 1
 		return
 	}
 
+	// The policies are counted by a single instance of the controller, the
+	// one running the PolicyServer controller.
+	if enableMetrics && config.EnablePolicyServerController {
+		if err = metrics.RegisterPolicyCount(func(ctx context.Context) ([]policiesv1.Policy, error) {
+			return controller.ListPolicies(ctx, mgr.GetClient())
+		}); err != nil {
+			setupLog.Error(err, "unable to register the policy count metric")
+			retcode = 1
+			return
+		}
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	if err = setupProbes(mgr); err != nil {
@@ -201,7 +465,53 @@ func main() {
 	}
 }
 
+// newRestConfig returns the configuration used by the manager to talk with
+// the Kubernetes API server, applying the client rate limiting options.
+func newRestConfig(mgrOpts ManagerOptions) (*rest.Config, error) {
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client configuration: %w", err)
+	}
+
+	if err = configureClientRateLimits(restConfig, mgrOpts); err != nil {
+		return nil, err
+	}
+
+	return restConfig, nil
+}
+
+// configureClientRateLimits applies the QPS and burst options to the given
+// Kubernetes client configuration.
+func configureClientRateLimits(restConfig *rest.Config, mgrOpts ManagerOptions) error {
+	if mgrOpts.KubeAPIQPS <= 0 {
+		return fmt.Errorf("invalid kube-api-qps value %v: must be greater than zero", mgrOpts.KubeAPIQPS)
+	}
+	if mgrOpts.KubeAPIBurst <= 0 {
+		return fmt.Errorf("invalid kube-api-burst value %d: must be greater than zero", mgrOpts.KubeAPIBurst)
+	}
+
+	restConfig.QPS = float32(mgrOpts.KubeAPIQPS)
+	restConfig.Burst = mgrOpts.KubeAPIBurst
+
+	return nil
+}
+
 func setupManager(mgrOpts ManagerOptions) (ctrl.Manager, error) {
+	if err := validateWebhookPort(mgrOpts.WebhookPort); err != nil {
+		return nil, err
+	}
+	if err := validateLeaderElectionDurations(mgrOpts); err != nil {
+		return nil, err
+	}
+	if mgrOpts.GracefulShutdownTimeout < 0 {
+		return nil, fmt.Errorf("invalid graceful-shutdown-timeout value %s: it must not be negative", mgrOpts.GracefulShutdownTimeout)
+	}
+
+	restConfig, err := newRestConfig(mgrOpts)
+	if err != nil {
+		return nil, err
+	}
+
 	namespaceSelector := cache.ByObject{
 		Field: fields.ParseSelectorOrDie("metadata.namespace=" + mgrOpts.DeploymentsNamespace),
 	}
@@ -215,14 +525,30 @@ func setupManager(mgrOpts ManagerOptions) (ctrl.Manager, error) {
 		clientCAName = filepath.Join("client-ca", constants.ClientCACert)
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	webhookServer := webhook.NewServer(webhook.Options{
+		Port:         mgrOpts.WebhookPort,
+		ClientCAName: clientCAName,
+	})
+	if mgrOpts.EnableTracing {
+		webhookServer = tracing.NewWebhookServer(webhookServer)
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
 			BindAddress: mgrOpts.MetricsAddr,
 		},
-		HealthProbeBindAddress: mgrOpts.ProbeAddr,
-		LeaderElection:         mgrOpts.EnableLeaderElection,
-		LeaderElectionID:       "a4ddbf36.kubewarden.io",
+		HealthProbeBindAddress:  mgrOpts.ProbeAddr,
+		LeaderElection:          mgrOpts.EnableLeaderElection,
+		LeaderElectionID:        mgrOpts.LeaderElectionID,
+		LeaderElectionNamespace: mgrOpts.LeaderElectionNamespace,
+		LeaseDuration:           &mgrOpts.LeaderElectionLeaseDuration,
+		RenewDeadline:           &mgrOpts.LeaderElectionRenewDeadline,
+		RetryPeriod:             &mgrOpts.LeaderElectionRetryPeriod,
+		// On SIGTERM the manager waits for the controllers to complete their
+		// in-flight reconciliations before returning, the metrics are
+		// flushed afterwards by the deferred shutdown of main.
+		GracefulShutdownTimeout: &mgrOpts.GracefulShutdownTimeout,
 		// Warning: the manager creates a client, which then uses Watches to monitor
 		// certain resources. By default, the client is not going to be namespaced,
 		// it will be able to watch resources across the entire cluster. This is of
@@ -252,14 +578,14 @@ func setupManager(mgrOpts ManagerOptions) (ctrl.Manager, error) {
 				&corev1.Secret{}:                     namespaceSelector,
 				&corev1.Pod{}:                        namespaceSelector,
 				&corev1.Service{}:                    namespaceSelector,
+				&corev1.ServiceAccount{}:             namespaceSelector,
 				&k8spoliciesv1.PodDisruptionBudget{}: namespaceSelector,
 				&corev1.ConfigMap{}:                  namespaceSelector,
 				&appsv1.Deployment{}:                 namespaceSelector,
+				&networkingv1.NetworkPolicy{}:        namespaceSelector,
 			},
 		},
-		WebhookServer: webhook.NewServer(webhook.Options{
-			ClientCAName: clientCAName,
-		}),
+		WebhookServer: webhookServer,
 	})
 	if err != nil {
 		return mgr, fmt.Errorf("failed to setup manager: %w", err)
@@ -282,85 +608,344 @@ func setupReconcilers(mgr ctrl.Manager,
 	config Configuration,
 	otelConfiguration controller.TelemetryConfiguration,
 ) error {
-	if err := (&controller.PolicyServerReconciler{
-		Client:               mgr.GetClient(),
-		Scheme:               mgr.GetScheme(),
-		Log:                  ctrl.Log.WithName("policy-server-reconciler"),
-		DeploymentsNamespace: deploymentsNamespace,
-		AlwaysAcceptAdmissionReviewsInDeploymentsNamespace: config.AlwaysAcceptAdmissionReviewsOnDeploymentsNamespace,
-		TelemetryConfiguration:                             otelConfiguration,
-		ClientCAConfigMapName:                              config.ClientCAConfigMapName,
-	}).SetupWithManager(mgr); err != nil {
-		return errors.Join(errors.New("unable to create PolicyServer controller"), err)
-	}
-
-	if err := (&controller.AdmissionPolicyReconciler{
-		Client:               mgr.GetClient(),
-		Scheme:               mgr.GetScheme(),
-		Log:                  ctrl.Log.WithName("admission-policy-reconciler"),
-		DeploymentsNamespace: deploymentsNamespace,
-		FeatureGateAdmissionWebhookMatchConditions: config.FeatureGateAdmissionWebhookMatchConditions,
-	}).SetupWithManager(mgr); err != nil {
-		return errors.Join(errors.New("unable to create AdmissionPolicy controller"), err)
-	}
-
-	if err := (&controller.ClusterAdmissionPolicyReconciler{
-		Client:               mgr.GetClient(),
-		Scheme:               mgr.GetScheme(),
-		Log:                  ctrl.Log.WithName("cluster-admission-policy-reconciler"),
-		DeploymentsNamespace: deploymentsNamespace,
-		FeatureGateAdmissionWebhookMatchConditions: config.FeatureGateAdmissionWebhookMatchConditions,
-	}).SetupWithManager(mgr); err != nil {
-		return errors.Join(errors.New("unable to create ClusterAdmissionPolicy controller"), err)
-	}
-
-	if err := (&controller.CertReconciler{
-		Client:                      mgr.GetClient(),
-		Log:                         ctrl.Log.WithName("cert-recociler"),
-		DeploymentsNamespace:        deploymentsNamespace,
-		WebhookServiceName:          config.WebhookServiceName,
-		CARootSecretName:            constants.CARootSecretName,
-		WebhookServerCertSecretName: constants.WebhookServerCertSecretName,
-	}).SetupWithManager(mgr); err != nil {
-		return errors.Join(errors.New("unable to create Cert controller"), err)
-	}
-
-	if err := (&controller.AdmissionPolicyGroupReconciler{
-		Client:               mgr.GetClient(),
-		Scheme:               mgr.GetScheme(),
-		Log:                  ctrl.Log.WithName("admission-policy-group-reconciler"),
-		DeploymentsNamespace: deploymentsNamespace,
-		FeatureGateAdmissionWebhookMatchConditions: config.FeatureGateAdmissionWebhookMatchConditions,
-	}).SetupWithManager(mgr); err != nil {
-		return errors.Join(errors.New("unable to create AdmissionPolicyGroup controller"), err)
-	}
-
-	if err := (&controller.ClusterAdmissionPolicyGroupReconciler{
-		Client:               mgr.GetClient(),
-		Scheme:               mgr.GetScheme(),
-		Log:                  ctrl.Log.WithName("cluster-admission-policy-group-reconciler"),
-		DeploymentsNamespace: deploymentsNamespace,
-		FeatureGateAdmissionWebhookMatchConditions: config.FeatureGateAdmissionWebhookMatchConditions,
-	}).SetupWithManager(mgr); err != nil {
-		return errors.Join(errors.New("unable to create ClusterAdmissionPolicyGroup controller"), err)
+	networkPolicyAPIServerCIDRs, err := parseNetworkPolicyAPIServerCIDRs(config)
+	if err != nil {
+		return err
+	}
+	if err = validateCertDurations(config); err != nil {
+		return err
+	}
+	if err = validateMaxConcurrentReconciles(config); err != nil {
+		return err
+	}
+	if err = validateEnabledControllers(config); err != nil {
+		return err
+	}
+	certKeyAlgorithm, err := certs.ParseKeyAlgorithm(config.CertKeyAlgorithm)
+	if err != nil {
+		return fmt.Errorf("invalid cert-key-algorithm value: %w", err)
+	}
+
+	if config.EnablePolicyServerController ||
+		config.EnableAdmissionPolicyController ||
+		config.EnableClusterAdmissionPolicyController ||
+		config.EnableAdmissionPolicyGroupController ||
+		config.EnableClusterAdmissionPolicyGroupController {
+		if err = controller.SetupPolicyServerIndexes(context.Background(), mgr); err != nil {
+			return errors.Join(errors.New("unable to index the policies by policy server"), err)
+		}
+	}
+
+	if config.EnablePolicyServerController {
+		if err = (&controller.PolicyServerReconciler{
+			Client:               mgr.GetClient(),
+			Scheme:               mgr.GetScheme(),
+			Log:                  ctrl.Log.WithName("policy-server-reconciler"),
+			DeploymentsNamespace: deploymentsNamespace,
+			AlwaysAcceptAdmissionReviewsInDeploymentsNamespace: config.AlwaysAcceptAdmissionReviewsOnDeploymentsNamespace,
+			TelemetryConfiguration:                             otelConfiguration,
+			ClientCAConfigMapName:                              config.ClientCAConfigMapName,
+			ManageNetworkPolicies:                              config.ManageNetworkPolicies,
+			NetworkPolicyAPIServerCIDRs:                        networkPolicyAPIServerCIDRs,
+			NetworkPolicyMonitoringNamespace:                   config.NetworkPolicyMonitoringNamespace,
+			ZoneAntiAffinity:                                   config.ZoneAntiAffinity,
+			ServerCertDuration:                                 config.ServerCertDuration,
+			CertKeyAlgorithm:                                   certKeyAlgorithm,
+			MaxConcurrentReconciles:                            config.PolicyServerMaxConcurrentReconciles,
+			PolicyChangesDebounce:                              config.PolicyChangesDebounce,
+			Tracer:                                             tracing.Tracer(),
+			NamespaceAllowList:                                 parseCommaSeparatedList(config.NamespaceAllowList),
+		}).SetupWithManager(mgr); err != nil {
+			return errors.Join(errors.New("unable to create PolicyServer controller"), err)
+		}
+	}
+
+	if err = setupPolicyReconcilers(mgr, deploymentsNamespace, config); err != nil {
+		return err
+	}
+
+	if config.EnableCertController {
+		if err = (&controller.CertReconciler{
+			Client:                         mgr.GetClient(),
+			Log:                            ctrl.Log.WithName("cert-recociler"),
+			DeploymentsNamespace:           deploymentsNamespace,
+			WebhookServiceName:             config.WebhookServiceName,
+			CARootSecretName:               constants.CARootSecretName,
+			WebhookServerCertSecretName:    constants.WebhookServerCertSecretName,
+			CertExpirationWarningThreshold: config.CertExpirationWarningThreshold,
+			CertRenewalWindow:              config.CertRenewalWindow,
+			CACertDuration:                 config.CACertDuration,
+			ServerCertDuration:             config.ServerCertDuration,
+			CertKeyAlgorithm:               certKeyAlgorithm,
+		}).SetupWithManager(mgr); err != nil {
+			return errors.Join(errors.New("unable to create Cert controller"), err)
+		}
+	}
+
+	// The ConfigMap lists the policies of all the Policy Servers, hence it is
+	// written by a single instance of the controller.
+	if config.EnablePolicyServerController && config.ActivePoliciesConfigMapName != "" {
+		if err = (&controller.ActivePoliciesReconciler{
+			Client:               mgr.GetClient(),
+			Log:                  ctrl.Log.WithName("active-policies-reconciler"),
+			DeploymentsNamespace: deploymentsNamespace,
+			ConfigMapName:        config.ActivePoliciesConfigMapName,
+			MaxSize:              constants.ActivePoliciesConfigMapMaxSize,
+		}).SetupWithManager(mgr); err != nil {
+			return errors.Join(errors.New("unable to create ActivePolicies controller"), err)
+		}
+	}
+	return nil
+}
+
+// setupPolicyReconcilers registers the enabled reconcilers of the policies.
+func setupPolicyReconcilers(mgr ctrl.Manager, deploymentsNamespace string, config Configuration) error {
+	if config.EnableAdmissionPolicyController {
+		if err := (&controller.AdmissionPolicyReconciler{
+			Client:               mgr.GetClient(),
+			Scheme:               mgr.GetScheme(),
+			Log:                  ctrl.Log.WithName("admission-policy-reconciler"),
+			DeploymentsNamespace: deploymentsNamespace,
+			FeatureGateAdmissionWebhookMatchConditions: config.FeatureGateAdmissionWebhookMatchConditions,
+			PolicyServerRestartGracePeriod:             config.PolicyServerRestartGracePeriod,
+			MaxConcurrentReconciles:                    config.PolicyMaxConcurrentReconciles,
+			Tracer:                                     tracing.Tracer(),
+			NamespaceAllowList:                         parseCommaSeparatedList(config.NamespaceAllowList),
+		}).SetupWithManager(mgr); err != nil {
+			return errors.Join(errors.New("unable to create AdmissionPolicy controller"), err)
+		}
 	}
+
+	if config.EnableClusterAdmissionPolicyController {
+		if err := (&controller.ClusterAdmissionPolicyReconciler{
+			Client:               mgr.GetClient(),
+			Scheme:               mgr.GetScheme(),
+			Log:                  ctrl.Log.WithName("cluster-admission-policy-reconciler"),
+			DeploymentsNamespace: deploymentsNamespace,
+			FeatureGateAdmissionWebhookMatchConditions: config.FeatureGateAdmissionWebhookMatchConditions,
+			PolicyServerRestartGracePeriod:             config.PolicyServerRestartGracePeriod,
+			MaxConcurrentReconciles:                    config.PolicyMaxConcurrentReconciles,
+			Tracer:                                     tracing.Tracer(),
+		}).SetupWithManager(mgr); err != nil {
+			return errors.Join(errors.New("unable to create ClusterAdmissionPolicy controller"), err)
+		}
+	}
+
+	if config.EnableAdmissionPolicyGroupController {
+		if err := (&controller.AdmissionPolicyGroupReconciler{
+			Client:               mgr.GetClient(),
+			Scheme:               mgr.GetScheme(),
+			Log:                  ctrl.Log.WithName("admission-policy-group-reconciler"),
+			DeploymentsNamespace: deploymentsNamespace,
+			FeatureGateAdmissionWebhookMatchConditions: config.FeatureGateAdmissionWebhookMatchConditions,
+			PolicyServerRestartGracePeriod:             config.PolicyServerRestartGracePeriod,
+			MaxConcurrentReconciles:                    config.PolicyMaxConcurrentReconciles,
+			Tracer:                                     tracing.Tracer(),
+		}).SetupWithManager(mgr); err != nil {
+			return errors.Join(errors.New("unable to create AdmissionPolicyGroup controller"), err)
+		}
+	}
+
+	if config.EnableClusterAdmissionPolicyGroupController {
+		if err := (&controller.ClusterAdmissionPolicyGroupReconciler{
+			Client:               mgr.GetClient(),
+			Scheme:               mgr.GetScheme(),
+			Log:                  ctrl.Log.WithName("cluster-admission-policy-group-reconciler"),
+			DeploymentsNamespace: deploymentsNamespace,
+			FeatureGateAdmissionWebhookMatchConditions: config.FeatureGateAdmissionWebhookMatchConditions,
+			PolicyServerRestartGracePeriod:             config.PolicyServerRestartGracePeriod,
+			MaxConcurrentReconciles:                    config.PolicyMaxConcurrentReconciles,
+			Tracer:                                     tracing.Tracer(),
+		}).SetupWithManager(mgr); err != nil {
+			return errors.Join(errors.New("unable to create ClusterAdmissionPolicyGroup controller"), err)
+		}
+	}
+
 	return nil
 }
 
-func setupWebhooks(mgr ctrl.Manager, deploymentsNamespace string) error {
-	if err := (&policiesv1.PolicyServer{}).SetupWebhookWithManager(mgr, deploymentsNamespace); err != nil {
+// parseCommaSeparatedList splits a comma separated flag value, ignoring the
+// whitespaces and the empty items.
+func parseCommaSeparatedList(value string) []string {
+	items := []string{}
+	for item := range strings.SplitSeq(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// validateEnabledControllers checks at least one controller is enabled, an
+// instance of the controller running none of them is misconfigured.
+func validateEnabledControllers(config Configuration) error {
+	if !config.EnablePolicyServerController &&
+		!config.EnableAdmissionPolicyController &&
+		!config.EnableClusterAdmissionPolicyController &&
+		!config.EnableAdmissionPolicyGroupController &&
+		!config.EnableClusterAdmissionPolicyGroupController &&
+		!config.EnableCertController {
+		return errors.New("no controller is enabled: at least one of the enable-*-controller flags must be set")
+	}
+
+	return nil
+}
+
+// validateWebhookPort checks the webhook server port is a valid TCP port.
+// Zero is rejected as well, since controller-runtime would silently replace
+// it with its default port.
+func validateWebhookPort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid webhook-port value %d: it must be between 1 and 65535", port)
+	}
+
+	return nil
+}
+
+// validateLeaderElectionDurations checks the leader can renew its leadership
+// before it expires, otherwise the controller would keep losing it.
+func validateLeaderElectionDurations(mgrOpts ManagerOptions) error {
+	if mgrOpts.LeaderElectionRetryPeriod <= 0 {
+		return fmt.Errorf("invalid leader-election-retry-period value %s: it must be greater than 0", mgrOpts.LeaderElectionRetryPeriod)
+	}
+	if mgrOpts.LeaderElectionRenewDeadline <= mgrOpts.LeaderElectionRetryPeriod {
+		return fmt.Errorf("invalid leader-election-renew-deadline value %s: it must be longer than the leader-election-retry-period %s",
+			mgrOpts.LeaderElectionRenewDeadline, mgrOpts.LeaderElectionRetryPeriod)
+	}
+	if mgrOpts.LeaderElectionLeaseDuration <= mgrOpts.LeaderElectionRenewDeadline {
+		return fmt.Errorf("invalid leader-election-lease-duration value %s: it must be longer than the leader-election-renew-deadline %s",
+			mgrOpts.LeaderElectionLeaseDuration, mgrOpts.LeaderElectionRenewDeadline)
+	}
+
+	return nil
+}
+
+// newTracesSampler returns the sampler of the traces of the controller, the
+// same one used by the Policy Servers.
+func newTracesSampler(sampler string, ratio float64) traceSDK.Sampler {
+	switch sampler {
+	case controller.TracingSamplerAlways:
+		return traceSDK.ParentBased(traceSDK.AlwaysSample())
+	case controller.TracingSamplerNever:
+		return traceSDK.NeverSample()
+	default:
+		return traceSDK.ParentBased(traceSDK.TraceIDRatioBased(ratio))
+	}
+}
+
+// validateTracingSampler checks the sampler of the traces of the Policy
+// Servers is known and, for the ratio sampler, that the ratio is a valid
+// probability.
+func validateTracingSampler(sampler string, ratio float64) error {
+	switch sampler {
+	case controller.TracingSamplerAlways, controller.TracingSamplerNever:
+		return nil
+	case controller.TracingSamplerRatio:
+		if ratio < 0 || ratio > 1 {
+			return fmt.Errorf("invalid tracing-sampler-ratio value %v: it must be between 0 and 1", ratio)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown tracing-sampler %q, supported values are %q, %q and %q",
+			sampler, controller.TracingSamplerAlways, controller.TracingSamplerNever, controller.TracingSamplerRatio)
+	}
+}
+
+// parseNetworkPolicyAPIServerCIDRs returns the CIDRs of the Kubernetes API
+// server allowed by the Policy Server NetworkPolicies. At least one CIDR is
+// required when the NetworkPolicies are managed, otherwise the webhook port
+// would be open to any source.
+func parseNetworkPolicyAPIServerCIDRs(config Configuration) ([]string, error) {
+	cidrs := parseCommaSeparatedList(config.NetworkPolicyAPIServerCIDRs)
+	for _, cidr := range cidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, fmt.Errorf("invalid network-policy-api-server-cidrs value %q: %w", cidr, err)
+		}
+	}
+	if config.ManageNetworkPolicies && len(cidrs) == 0 {
+		return nil, errors.New("network-policy-api-server-cidrs is required when manage-network-policies is set")
+	}
+
+	return cidrs, nil
+}
+
+// validateCertDurations checks the certificates renewal window is shorter
+// than the validity of the generated certificates, otherwise the
+// certificates would be rotated at every reconciliation.
+func validateCertDurations(config Configuration) error {
+	if config.CertRenewalWindow <= 0 {
+		return fmt.Errorf("invalid cert-renewal-window value %s: it must be greater than 0", config.CertRenewalWindow)
+	}
+	if config.CACertDuration <= config.CertRenewalWindow {
+		return fmt.Errorf("invalid ca-cert-duration value %s: it must be longer than the cert-renewal-window %s", config.CACertDuration, config.CertRenewalWindow)
+	}
+	if config.ServerCertDuration <= config.CertRenewalWindow {
+		return fmt.Errorf("invalid server-cert-duration value %s: it must be longer than the cert-renewal-window %s", config.ServerCertDuration, config.CertRenewalWindow)
+	}
+
+	return nil
+}
+
+// newPolicyServerDefaulterOptions returns the options of the PolicyServer
+// mutating webhook. Only percentages are accepted as default maxUnavailable,
+// scaling with the replicas, and 0% is rejected since it would block the node
+// drains.
+func newPolicyServerDefaulterOptions(config Configuration) (policiesv1.PolicyServerDefaulterOptions, error) {
+	value := config.PolicyServerDefaultMaxUnavailable
+	if value == "" {
+		return policiesv1.PolicyServerDefaulterOptions{}, nil
+	}
+
+	percentage, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil || !strings.HasSuffix(value, "%") || percentage < 1 || percentage > 100 {
+		return policiesv1.PolicyServerDefaulterOptions{},
+			fmt.Errorf("invalid policy-server-default-max-unavailable value %q: it must be a percentage between 1%% and 100%%", value)
+	}
+
+	return policiesv1.PolicyServerDefaulterOptions{DefaultMaxUnavailable: ptr.To(intstr.FromString(value))}, nil
+}
+
+func validateMaxConcurrentReconciles(config Configuration) error {
+	if config.PolicyServerMaxConcurrentReconciles < 1 {
+		return fmt.Errorf("invalid policy-server-max-concurrent-reconciles value %d: it must be greater than 0", config.PolicyServerMaxConcurrentReconciles)
+	}
+	if config.PolicyMaxConcurrentReconciles < 1 {
+		return fmt.Errorf("invalid policy-max-concurrent-reconciles value %d: it must be greater than 0", config.PolicyMaxConcurrentReconciles)
+	}
+
+	return nil
+}
+
+func setupWebhooks(mgr ctrl.Manager, deploymentsNamespace string, config Configuration, otelConfiguration controller.TelemetryConfiguration) error {
+	policyServerDefaulterOptions, err := newPolicyServerDefaulterOptions(config)
+	if err != nil {
+		return err
+	}
+	policyServerValidatorOptions := policiesv1.PolicyServerValidatorOptions{
+		EnvDenyList:               parseCommaSeparatedList(config.PolicyServerEnvDenyList),
+		RejectDeniedEnv:           config.RejectPolicyServerDeniedEnv,
+		AllowLoadBalancerServices: config.AllowPolicyServerLoadBalancerServices,
+		ManagedOtelEnv:            otelConfiguration.ManagedOtelEnvVars(),
+		MetricsPort:               controller.PolicyServerMetricsPort(),
+		ReferenceLookupRetries:    config.PolicyServerValidationLookupRetries,
+	}
+	for _, resourceName := range parseCommaSeparatedList(config.PolicyServerRequiredResources) {
+		policyServerValidatorOptions.RequiredResources = append(policyServerValidatorOptions.RequiredResources, corev1.ResourceName(resourceName))
+	}
+	if err := (&policiesv1.PolicyServer{}).SetupWebhookWithManager(mgr, deploymentsNamespace, policyServerDefaulterOptions, policyServerValidatorOptions); err != nil {
 		return errors.Join(errors.New("unable to create webhook for policy servers"), err)
 	}
 	if err := (&policiesv1.ClusterAdmissionPolicy{}).SetupWebhookWithManager(mgr); err != nil {
 		return errors.Join(errors.New("unable to create webhook for cluster admission policies"), err)
 	}
-	if err := (&policiesv1.AdmissionPolicy{}).SetupWebhookWithManager(mgr); err != nil {
+	if err := (&policiesv1.AdmissionPolicy{}).SetupWebhookWithManager(mgr, config.RejectClusterScopedResourcesInAdmissionPolicies); err != nil {
 		return errors.Join(errors.New("unable to create webhook for admission policies"), err)
 	}
-	if err := (&policiesv1.AdmissionPolicyGroup{}).SetupWebhookWithManager(mgr); err != nil {
+	if err := (&policiesv1.AdmissionPolicyGroup{}).SetupWebhookWithManager(mgr, config.PolicyGroupMaxMembers); err != nil {
 		return errors.Join(errors.New("unable to create webhook for admission policies groups"), err)
 	}
-	if err := (&policiesv1.ClusterAdmissionPolicyGroup{}).SetupWebhookWithManager(mgr); err != nil {
+	if err := (&policiesv1.ClusterAdmissionPolicyGroup{}).SetupWebhookWithManager(mgr, config.PolicyGroupMaxMembers); err != nil {
 		return errors.Join(errors.New("unable to create webhook for cluster admission policies groups"), err)
 	}
 	return nil
@@ -0,0 +1,517 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+func TestConfigureClientRateLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		qps   float64
+		burst int
+		error string
+	}{
+		{
+			name:  "valid values",
+			qps:   50,
+			burst: 100,
+			error: "",
+		},
+		{
+			name:  "zero qps",
+			qps:   0,
+			burst: 100,
+			error: "invalid kube-api-qps value 0: must be greater than zero",
+		},
+		{
+			name:  "negative qps",
+			qps:   -1,
+			burst: 100,
+			error: "invalid kube-api-qps value -1: must be greater than zero",
+		},
+		{
+			name:  "zero burst",
+			qps:   50,
+			burst: 0,
+			error: "invalid kube-api-burst value 0: must be greater than zero",
+		},
+		{
+			name:  "negative burst",
+			qps:   50,
+			burst: -10,
+			error: "invalid kube-api-burst value -10: must be greater than zero",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			restConfig := &rest.Config{}
+
+			err := configureClientRateLimits(restConfig, ManagerOptions{
+				KubeAPIQPS:   test.qps,
+				KubeAPIBurst: test.burst,
+			})
+
+			if test.error != "" {
+				require.EqualError(t, err, test.error)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, test.qps, restConfig.QPS, 0.001)
+			assert.Equal(t, test.burst, restConfig.Burst)
+		})
+	}
+}
+
+func TestParseCommaSeparatedList(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+	}{
+		{
+			name:     "empty value",
+			value:    "",
+			expected: []string{},
+		},
+		{
+			name:     "single item",
+			value:    "FOO",
+			expected: []string{"FOO"},
+		},
+		{
+			name:     "items with whitespaces and empty items",
+			value:    " FOO, ,BAR ,",
+			expected: []string{"FOO", "BAR"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, parseCommaSeparatedList(test.value))
+		})
+	}
+}
+
+func TestParseNetworkPolicyAPIServerCIDRs(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Configuration
+		expected []string
+		error    string
+	}{
+		{
+			name:     "network policies not managed",
+			config:   Configuration{},
+			expected: []string{},
+		},
+		{
+			name: "valid CIDRs",
+			config: Configuration{
+				ManageNetworkPolicies:       true,
+				NetworkPolicyAPIServerCIDRs: "10.0.0.0/24, 192.168.1.1/32",
+			},
+			expected: []string{"10.0.0.0/24", "192.168.1.1/32"},
+		},
+		{
+			name: "invalid CIDR",
+			config: Configuration{
+				ManageNetworkPolicies:       true,
+				NetworkPolicyAPIServerCIDRs: "10.0.0.1",
+			},
+			error: `invalid network-policy-api-server-cidrs value "10.0.0.1": invalid CIDR address: 10.0.0.1`,
+		},
+		{
+			name: "missing CIDRs",
+			config: Configuration{
+				ManageNetworkPolicies: true,
+			},
+			error: "network-policy-api-server-cidrs is required when manage-network-policies is set",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cidrs, err := parseNetworkPolicyAPIServerCIDRs(test.config)
+
+			if test.error != "" {
+				require.EqualError(t, err, test.error)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, cidrs)
+		})
+	}
+}
+
+func TestValidateCertDurations(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Configuration
+		error  string
+	}{
+		{
+			name: "default durations",
+			config: Configuration{
+				CertRenewalWindow:  constants.CertLookahead,
+				CACertDuration:     constants.CACertExpiration,
+				ServerCertDuration: constants.ServerCertExpiration,
+			},
+		},
+		{
+			name: "short server certificates",
+			config: Configuration{
+				CertRenewalWindow:  30 * 24 * time.Hour,
+				CACertDuration:     constants.CACertExpiration,
+				ServerCertDuration: 90 * 24 * time.Hour,
+			},
+		},
+		{
+			name: "zero renewal window",
+			config: Configuration{
+				CACertDuration:     constants.CACertExpiration,
+				ServerCertDuration: constants.ServerCertExpiration,
+			},
+			error: "invalid cert-renewal-window value 0s: it must be greater than 0",
+		},
+		{
+			name: "CA certificates shorter than the renewal window",
+			config: Configuration{
+				CertRenewalWindow:  constants.CertLookahead,
+				CACertDuration:     30 * 24 * time.Hour,
+				ServerCertDuration: constants.ServerCertExpiration,
+			},
+			error: "invalid ca-cert-duration value 720h0m0s: it must be longer than the cert-renewal-window 1440h0m0s",
+		},
+		{
+			name: "server certificates as long as the renewal window",
+			config: Configuration{
+				CertRenewalWindow:  constants.CertLookahead,
+				CACertDuration:     constants.CACertExpiration,
+				ServerCertDuration: constants.CertLookahead,
+			},
+			error: "invalid server-cert-duration value 1440h0m0s: it must be longer than the cert-renewal-window 1440h0m0s",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateCertDurations(test.config)
+
+			if test.error != "" {
+				require.EqualError(t, err, test.error)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateMaxConcurrentReconciles(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Configuration
+		error  string
+	}{
+		{
+			name: "default concurrency",
+			config: Configuration{
+				PolicyServerMaxConcurrentReconciles: defaultMaxConcurrentReconciles,
+				PolicyMaxConcurrentReconciles:       defaultMaxConcurrentReconciles,
+			},
+		},
+		{
+			name: "concurrent policy reconciliation",
+			config: Configuration{
+				PolicyServerMaxConcurrentReconciles: 2,
+				PolicyMaxConcurrentReconciles:       10,
+			},
+		},
+		{
+			name: "zero policy server workers",
+			config: Configuration{
+				PolicyMaxConcurrentReconciles: defaultMaxConcurrentReconciles,
+			},
+			error: "invalid policy-server-max-concurrent-reconciles value 0: it must be greater than 0",
+		},
+		{
+			name: "negative policy workers",
+			config: Configuration{
+				PolicyServerMaxConcurrentReconciles: defaultMaxConcurrentReconciles,
+				PolicyMaxConcurrentReconciles:       -1,
+			},
+			error: "invalid policy-max-concurrent-reconciles value -1: it must be greater than 0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateMaxConcurrentReconciles(test.config)
+
+			if test.error != "" {
+				require.EqualError(t, err, test.error)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateEnabledControllers(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Configuration
+		error  string
+	}{
+		{
+			name: "all controllers enabled",
+			config: Configuration{
+				EnablePolicyServerController:                true,
+				EnableAdmissionPolicyController:             true,
+				EnableClusterAdmissionPolicyController:      true,
+				EnableAdmissionPolicyGroupController:        true,
+				EnableClusterAdmissionPolicyGroupController: true,
+				EnableCertController:                        true,
+			},
+		},
+		{
+			name: "only the cert controller enabled",
+			config: Configuration{
+				EnableCertController: true,
+			},
+		},
+		{
+			name:   "no controller enabled",
+			config: Configuration{},
+			error:  "no controller is enabled: at least one of the enable-*-controller flags must be set",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateEnabledControllers(test.config)
+
+			if test.error != "" {
+				require.EqualError(t, err, test.error)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNewPolicyServerDefaulterOptions(t *testing.T) {
+	tests := []struct {
+		name                  string
+		value                 string
+		defaultMaxUnavailable *intstr.IntOrString
+		error                 string
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:                  "percentage",
+			value:                 "50%",
+			defaultMaxUnavailable: ptr.To(intstr.FromString("50%")),
+		},
+		{
+			name:  "integer",
+			value: "1",
+			error: `invalid policy-server-default-max-unavailable value "1": it must be a percentage between 1% and 100%`,
+		},
+		{
+			name:  "zero percentage",
+			value: "0%",
+			error: `invalid policy-server-default-max-unavailable value "0%": it must be a percentage between 1% and 100%`,
+		},
+		{
+			name:  "percentage greater than 100",
+			value: "150%",
+			error: `invalid policy-server-default-max-unavailable value "150%": it must be a percentage between 1% and 100%`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options, err := newPolicyServerDefaulterOptions(Configuration{PolicyServerDefaultMaxUnavailable: test.value})
+
+			if test.error != "" {
+				require.EqualError(t, err, test.error)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.defaultMaxUnavailable, options.DefaultMaxUnavailable)
+		})
+	}
+}
+
+func TestValidateWebhookPort(t *testing.T) {
+	tests := []struct {
+		name  string
+		port  int
+		error string
+	}{
+		{
+			name:  "default port",
+			port:  9443,
+			error: "",
+		},
+		{
+			name:  "custom port",
+			port:  10250,
+			error: "",
+		},
+		{
+			name:  "zero port",
+			port:  0,
+			error: "invalid webhook-port value 0: it must be between 1 and 65535",
+		},
+		{
+			name:  "negative port",
+			port:  -1,
+			error: "invalid webhook-port value -1: it must be between 1 and 65535",
+		},
+		{
+			name:  "port out of range",
+			port:  65536,
+			error: "invalid webhook-port value 65536: it must be between 1 and 65535",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateWebhookPort(test.port)
+			if test.error == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.error)
+			}
+		})
+	}
+}
+
+func TestValidateLeaderElectionDurations(t *testing.T) {
+	tests := []struct {
+		name          string
+		leaseDuration time.Duration
+		renewDeadline time.Duration
+		retryPeriod   time.Duration
+		error         string
+	}{
+		{
+			name:          "default durations",
+			leaseDuration: 15 * time.Second,
+			renewDeadline: 10 * time.Second,
+			retryPeriod:   2 * time.Second,
+			error:         "",
+		},
+		{
+			name:          "zero retry period",
+			leaseDuration: 15 * time.Second,
+			renewDeadline: 10 * time.Second,
+			retryPeriod:   0,
+			error:         "invalid leader-election-retry-period value 0s: it must be greater than 0",
+		},
+		{
+			name:          "renew deadline shorter than the retry period",
+			leaseDuration: 15 * time.Second,
+			renewDeadline: time.Second,
+			retryPeriod:   2 * time.Second,
+			error:         "invalid leader-election-renew-deadline value 1s: it must be longer than the leader-election-retry-period 2s",
+		},
+		{
+			name:          "lease duration equal to the renew deadline",
+			leaseDuration: 10 * time.Second,
+			renewDeadline: 10 * time.Second,
+			retryPeriod:   2 * time.Second,
+			error:         "invalid leader-election-lease-duration value 10s: it must be longer than the leader-election-renew-deadline 10s",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateLeaderElectionDurations(ManagerOptions{
+				LeaderElectionLeaseDuration: test.leaseDuration,
+				LeaderElectionRenewDeadline: test.renewDeadline,
+				LeaderElectionRetryPeriod:   test.retryPeriod,
+			})
+			if test.error == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.error)
+			}
+		})
+	}
+}
+
+func TestValidateTracingSampler(t *testing.T) {
+	tests := []struct {
+		name    string
+		sampler string
+		ratio   float64
+		error   string
+	}{
+		{
+			name:    "always",
+			sampler: "always",
+			error:   "",
+		},
+		{
+			name:    "never",
+			sampler: "never",
+			error:   "",
+		},
+		{
+			name:    "ratio",
+			sampler: "ratio",
+			ratio:   0.1,
+			error:   "",
+		},
+		{
+			name:    "ratio greater than 1",
+			sampler: "ratio",
+			ratio:   1.5,
+			error:   "invalid tracing-sampler-ratio value 1.5: it must be between 0 and 1",
+		},
+		{
+			name:    "negative ratio",
+			sampler: "ratio",
+			ratio:   -0.1,
+			error:   "invalid tracing-sampler-ratio value -0.1: it must be between 0 and 1",
+		},
+		{
+			name:    "unknown sampler",
+			sampler: "sometimes",
+			error:   `unknown tracing-sampler "sometimes", supported values are "always", "never" and "ratio"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateTracingSampler(test.sampler, test.ratio)
+			if test.error == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.error)
+			}
+		})
+	}
+}
+
+func TestNewTracesSampler(t *testing.T) {
+	assert.Equal(t, "ParentBased{root:AlwaysOnSampler,remoteParentSampled:AlwaysOnSampler,remoteParentNotSampled:AlwaysOffSampler,localParentSampled:AlwaysOnSampler,localParentNotSampled:AlwaysOffSampler}",
+		newTracesSampler("always", 0).Description())
+	assert.Equal(t, "AlwaysOffSampler", newTracesSampler("never", 0).Description())
+	assert.Equal(t, "ParentBased{root:TraceIDRatioBased{0.1},remoteParentSampled:AlwaysOnSampler,remoteParentNotSampled:AlwaysOffSampler,localParentSampled:AlwaysOnSampler,localParentNotSampled:AlwaysOffSampler}",
+		newTracesSampler("ratio", 0.1).Description())
+}
@@ -2,13 +2,16 @@ package certs
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
@@ -18,18 +21,47 @@ const (
 	startValue   = 1
 	maxBitLength = 128
 	caCommonName = "kubewarden-controller-ca"
+	rsaKeySize   = 3072
 )
 
+// KeyAlgorithm is the algorithm of the private keys of the generated
+// certificates.
+type KeyAlgorithm string
+
+const (
+	KeyAlgorithmRSA       KeyAlgorithm = "rsa"
+	KeyAlgorithmECDSAP256 KeyAlgorithm = "ecdsa-p256"
+	KeyAlgorithmECDSAP384 KeyAlgorithm = "ecdsa-p384"
+	// DefaultKeyAlgorithm is the algorithm used when none is configured.
+	DefaultKeyAlgorithm = KeyAlgorithmECDSAP256
+)
+
+// ParseKeyAlgorithm returns the KeyAlgorithm with the given name.
+func ParseKeyAlgorithm(name string) (KeyAlgorithm, error) {
+	switch keyAlgorithm := KeyAlgorithm(name); keyAlgorithm {
+	case KeyAlgorithmRSA, KeyAlgorithmECDSAP256, KeyAlgorithmECDSAP384:
+		return keyAlgorithm, nil
+	default:
+		return "", fmt.Errorf("unsupported key algorithm %q, supported values are %s, %s and %s", name, KeyAlgorithmRSA, KeyAlgorithmECDSAP256, KeyAlgorithmECDSAP384)
+	}
+}
+
 // GenerateCA generates a self-signed CA root certificate and private key in PEM format.
 // It accepts validity bounds as parameters.
 func GenerateCA(notBefore, notAfter time.Time) ([]byte, []byte, error) {
+	return GenerateCAWithKeyAlgorithm(notBefore, notAfter, DefaultKeyAlgorithm)
+}
+
+// GenerateCAWithKeyAlgorithm generates a self-signed CA root certificate and
+// private key in PEM format, using a private key of the given algorithm.
+func GenerateCAWithKeyAlgorithm(notBefore, notAfter time.Time, keyAlgorithm KeyAlgorithm) ([]byte, []byte, error) {
 	serialNumberUpperBound := new(big.Int).Lsh(big.NewInt(startValue), maxBitLength)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberUpperBound)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot init serial number: %w", err)
 	}
 
-	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	privateKey, err := generatePrivateKey(keyAlgorithm)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot create private key: %w", err)
 	}
@@ -51,7 +83,7 @@ func GenerateCA(notBefore, notAfter time.Time) ([]byte, []byte, error) {
 		rand.Reader,
 		&caCert,
 		&caCert,
-		&privateKey.PublicKey,
+		privateKey.Public(),
 		privateKey)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot create certificate: %w", err)
@@ -77,6 +109,19 @@ func GenerateCert(caCertPEM []byte,
 	notBefore time.Time,
 	notAfter time.Time,
 	dnsName string,
+) ([]byte, []byte, error) {
+	return GenerateCertWithKeyAlgorithm(caCertPEM, caPrivateKeyPEM, notBefore, notAfter, dnsName, DefaultKeyAlgorithm)
+}
+
+// GenerateCertWithKeyAlgorithm generates a certificate and private key signed
+// by the provided CA in PEM format, using a private key of the given
+// algorithm.
+func GenerateCertWithKeyAlgorithm(caCertPEM []byte,
+	caPrivateKeyPEM []byte,
+	notBefore time.Time,
+	notAfter time.Time,
+	dnsName string,
+	keyAlgorithm KeyAlgorithm,
 ) ([]byte, []byte, error) {
 	caCertBlock, _ := pem.Decode(caCertPEM)
 	caCert, err := x509.ParseCertificate(caCertBlock.Bytes)
@@ -85,7 +130,7 @@ func GenerateCert(caCertPEM []byte,
 	}
 
 	caPrivateKeyBlock, _ := pem.Decode(caPrivateKeyPEM)
-	caPrivateKey, err := x509.ParseECPrivateKey(caPrivateKeyBlock.Bytes)
+	caPrivateKey, err := parsePrivateKey(caPrivateKeyBlock.Bytes)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error parsing ca root private key: %w", err)
 	}
@@ -96,7 +141,7 @@ func GenerateCert(caCertPEM []byte,
 		return nil, nil, fmt.Errorf("cannot generate serialNumber for certificate: %w", err)
 	}
 
-	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	privateKey, err := generatePrivateKey(keyAlgorithm)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot create private key: %w", err)
 	}
@@ -117,7 +162,7 @@ func GenerateCert(caCertPEM []byte,
 		rand.Reader,
 		&cert,
 		caCert,
-		&privateKey.PublicKey,
+		privateKey.Public(),
 		caPrivateKey)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot create certificate: %w", err)
@@ -151,15 +196,58 @@ func pemEncodeCertificate(certificate []byte) ([]byte, error) {
 	return certificatePEM.Bytes(), nil
 }
 
-// pemEncodePrivateKey encodes a private key to PEM format.
-func pemEncodePrivateKey(privateKey *ecdsa.PrivateKey) ([]byte, error) {
-	privateKeyBytes, err := x509.MarshalECPrivateKey(privateKey)
+// generatePrivateKey generates a private key of the given algorithm.
+func generatePrivateKey(keyAlgorithm KeyAlgorithm) (crypto.Signer, error) {
+	switch keyAlgorithm {
+	case KeyAlgorithmRSA:
+		return rsa.GenerateKey(rand.Reader, rsaKeySize)
+	case KeyAlgorithmECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyAlgorithmECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported key algorithm %q", keyAlgorithm)
+	}
+}
+
+// parsePrivateKey parses a DER encoded RSA or ECDSA private key.
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
 	if err != nil {
-		return nil, fmt.Errorf("cannot marshalprivate key: %w", err)
+		return nil, errors.New("the private key is not a valid RSA or ECDSA key")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("the private key cannot be used to sign certificates")
+	}
+
+	return signer, nil
+}
+
+// pemEncodePrivateKey encodes a private key to PEM format.
+func pemEncodePrivateKey(privateKey crypto.Signer) ([]byte, error) {
+	var privateKeyBytes []byte
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		privateKeyBytes = x509.MarshalPKCS1PrivateKey(key)
+	case *ecdsa.PrivateKey:
+		var err error
+		privateKeyBytes, err = x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshalprivate key: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", privateKey)
 	}
 	privateKeyPEM := new(bytes.Buffer)
 
-	err = pem.Encode(privateKeyPEM, &pem.Block{
+	err := pem.Encode(privateKeyPEM, &pem.Block{
 		Type:  "RSA PRIVATE KEY",
 		Bytes: privateKeyBytes,
 	})
@@ -208,6 +296,9 @@ func VerifyCA(caCertPEM, caPrivateKeyPEM []byte, at time.Time) error {
 func VerifyCert(certPEM, privateKeyPEM []byte, certPool *x509.CertPool, dnsName string, at time.Time) error {
 	// Decode and parse the certificate
 	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return errors.New("failed to decode certificate PEM")
+	}
 	cert, err := x509.ParseCertificate(certBlock.Bytes)
 	if err != nil {
 		return fmt.Errorf("error parsing certificate: %w", err)
@@ -235,6 +326,49 @@ func VerifyCert(certPEM, privateKeyPEM []byte, certPool *x509.CertPool, dnsName
 	return nil
 }
 
+// CertificateNotAfter returns the expiration time of a PEM-encoded certificate.
+func CertificateNotAfter(certPEM []byte) (time.Time, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return time.Time{}, errors.New("failed to decode certificate PEM")
+	}
+
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing certificate: %w", err)
+	}
+
+	return cert.NotAfter, nil
+}
+
+// CertificateKeyAlgorithm returns the algorithm of the key of a PEM-encoded
+// certificate.
+func CertificateKeyAlgorithm(certPEM []byte) (KeyAlgorithm, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return "", errors.New("failed to decode certificate PEM")
+	}
+
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("error parsing certificate: %w", err)
+	}
+
+	switch publicKey := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return KeyAlgorithmRSA, nil
+	case *ecdsa.PublicKey:
+		switch publicKey.Curve {
+		case elliptic.P256():
+			return KeyAlgorithmECDSAP256, nil
+		case elliptic.P384():
+			return KeyAlgorithmECDSAP384, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported certificate key algorithm %s", cert.PublicKeyAlgorithm)
+}
+
 func DNSName(serviceName, namespace string) string {
 	return fmt.Sprintf("%s.%s.svc", serviceName, namespace)
 }
@@ -2,6 +2,7 @@ package certs
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
 	corev1 "k8s.io/api/core/v1"
@@ -50,3 +51,33 @@ func ExtractServerCertFromSecret(serverCertSecret *corev1.Secret) ([]byte, []byt
 
 	return serverCert, serverPrivateKey, nil
 }
+
+// ExtractCABundleFromExternalCertSecret validates a secret holding an
+// externally managed server certificate, like the ones created by
+// cert-manager, and extracts its CA certificate. The secret must contain the
+// server certificate, its private key and the PEM encoded CA certificate.
+// The server certificate must be signed by the CA, be currently valid and
+// include the given DNS name among its SANs.
+func ExtractCABundleFromExternalCertSecret(externalCertSecret *corev1.Secret, dnsName string) ([]byte, error) {
+	cert, privateKey, err := ExtractServerCertFromSecret(externalCertSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	caCert, found := externalCertSecret.Data[constants.CARootCert]
+	if !found {
+		return nil, fmt.Errorf("CA could not be extracted from secret: %s", externalCertSecret.GetName())
+	}
+	if len(caCert) == 0 {
+		return nil, fmt.Errorf("CA certificate is empty in secret: %s", externalCertSecret.GetName())
+	}
+	certPool, err := NewCertPool(caCert)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA certificate in secret %s: %w", externalCertSecret.GetName(), err)
+	}
+	if err = VerifyCert(cert, privateKey, certPool, dnsName, time.Now()); err != nil {
+		return nil, fmt.Errorf("invalid server certificate in secret %s: %w", externalCertSecret.GetName(), err)
+	}
+
+	return caCert, nil
+}
@@ -7,16 +7,32 @@ const (
 	// policies does not have a policy server name defined.
 	DefaultPolicyServer = "default"
 
-	PolicyServerEnableMetricsEnvVar                 = "KUBEWARDEN_ENABLE_METRICS"
-	PolicyServerDeploymentConfigVersionAnnotation   = "kubewarden/config-version"
-	PolicyServerDeploymentPodSpecConfigVersionLabel = "kubewarden/config-version"
-	PolicyServerListenPort                          = 8443
-	PolicyServerServicePort                         = 443
-	PolicyServerMetricsPortEnvVar                   = "KUBEWARDEN_POLICY_SERVER_SERVICES_METRICS_PORT"
-	PolicyServerMetricsPort                         = 8080
-	PolicyServerReadinessProbePort                  = 8081
-	PolicyServerReadinessProbe                      = "/readiness"
-	PolicyServerLogFmtEnvVar                        = "KUBEWARDEN_LOG_FMT"
+	PolicyServerEnableMetricsEnvVar               = "KUBEWARDEN_ENABLE_METRICS"
+	PolicyServerDeploymentConfigVersionAnnotation = "kubewarden/config-version"
+	PolicyServerServiceAnnotationsAnnotation      = "kubewarden/service-annotations"
+	PolicyServerPodConfigChecksumAnnotation       = "kubewarden/config-checksum"
+	PolicyServerListenPort                        = 8443
+	PolicyServerListenPortEnvVar                  = "KUBEWARDEN_PORT"
+	PolicyServerServicePort                       = 443
+	PolicyServerMetricsPortEnvVar                 = "KUBEWARDEN_POLICY_SERVER_SERVICES_METRICS_PORT"
+	PolicyServerMetricsPort                       = 8080
+	PolicyServerReadinessProbePort                = 8081
+	PolicyServerReadinessProbePortEnvVar          = "KUBEWARDEN_READINESS_PROBE_PORT"
+	PolicyServerReadinessProbe                    = "/readiness"
+	PolicyServerLogFmtEnvVar                      = "KUBEWARDEN_LOG_FMT"
+	PolicyServerLogLevelEnvVar                    = "KUBEWARDEN_LOG_LEVEL"
+	PolicyServerWorkersEnvVar                     = "KUBEWARDEN_WORKERS"
+	PolicyServerEnablePprofEnvVar                 = "KUBEWARDEN_ENABLE_PPROF"
+	PolicyServerContinueOnErrorsEnvVar            = "KUBEWARDEN_CONTINUE_ON_ERRORS"
+	PolicyServerDisableTimeoutProtectionEnvVar    = "KUBEWARDEN_DISABLE_TIMEOUT_PROTECTION"
+	PolicyServerDefaultRevisionHistoryLimit       = 3
+	PolicyServerDefaultProgressDeadlineSeconds    = 600
+	// PolicyServerNamePrefix is prepended to the policy server name to build
+	// the names of the policy server resources.
+	PolicyServerNamePrefix = "policy-server-"
+	// PolicyServerImagePullSecretsSuffix is appended to the prefixed policy
+	// server name to build the name of its merged image pull secret.
+	PolicyServerImagePullSecretsSuffix = "-image-pull-secrets"
 
 	PolicyServerConfigPoliciesEntry         = "policies.yml"
 	PolicyServerDeploymentRestartAnnotation = "kubectl.kubernetes.io/restartedAt"
@@ -26,6 +42,15 @@ const (
 	PolicyServerVerificationConfigEntry         = "verification-config"
 	PolicyServerVerificationConfigContainerPath = "/verification"
 
+	// Active policies ConfigMap entries.
+	ActivePoliciesConfigMapPoliciesEntry  = "policies.json"
+	ActivePoliciesConfigMapTotalEntry     = "total"
+	ActivePoliciesConfigMapTruncatedEntry = "truncated"
+	// ActivePoliciesConfigMapMaxSize is the maximum size of the policies
+	// listed in the active policies ConfigMap, well below the 1MiB limit of
+	// the ConfigMaps.
+	ActivePoliciesConfigMapMaxSize = 512 * 1024
+
 	// Policy Server Labels.
 
 	// AppLabelKey is the label used to identify the pod template in the deployment
@@ -34,6 +59,9 @@ const (
 	AppLabelKey                     = "app"
 	PolicyServerLabelKey            = "kubewarden/policy-server"
 	ComponentPolicyServerLabelValue = "policy-server"
+	// ComponentAuditScannerLabelValue is the component label of the audit
+	// scanner pods, deployed by the helm chart.
+	ComponentAuditScannerLabelValue = "audit-scanner"
 	InstanceLabelKey                = "app.kubernetes.io/instance"
 	ComponentLabelKey               = "app.kubernetes.io/component"
 	PartOfLabelKey                  = "app.kubernetes.io/part-of"
@@ -41,6 +69,10 @@ const (
 	ManagedByKey                    = "app.kubernetes.io/managed-by"
 
 	PolicyServerIndexKey = ".spec.policyServer"
+	// PolicyServerSecretsIndexKey indexes the PolicyServers by the names of
+	// the secrets they reference: the image pull secrets and the certificate
+	// secret.
+	PolicyServerSecretsIndexKey = ".spec.secrets"
 
 	KubewardenFinalizerPre114 = "kubewarden"
 	KubewardenFinalizer       = "kubewarden.io/finalizer"
@@ -49,6 +81,26 @@ const (
 
 	OptelInjectAnnotation = "sidecar.opentelemetry.io/inject"
 
+	// PausedAnnotation pauses the reconciliation of the annotated PolicyServer
+	// or policy when set to "true".
+	PausedAnnotation = "kubewarden.io/paused"
+
+	// OwnerReferencesAnnotation selects how the PolicyServer owns the
+	// Deployment, Service, ConfigMap and the other resources generated for
+	// it. By default the resources are owned by the PolicyServer and garbage
+	// collected together with it. The "block-owner-deletion" value sets
+	// blockOwnerDeletion on the owner references, so that a foreground
+	// deletion of the PolicyServer waits for the resources to be deleted. The
+	// "orphan" value does not set any owner reference, so that the resources
+	// survive the deletion of the PolicyServer and are adopted when it is
+	// created again, as when a GitOps tool replaces it. Changes to orphaned
+	// Deployments do not trigger the reconciliation of the PolicyServer. The
+	// certificate secret and the dedicated ServiceAccount resources are always
+	// owned.
+	OwnerReferencesAnnotation             = "kubewarden.io/owner-references"
+	OwnerReferencesBlockOwnerDeletionMode = "block-owner-deletion"
+	OwnerReferencesOrphanMode             = "orphan"
+
 	WebhookConfigurationPolicyNameAnnotationKey      = "kubewardenPolicyName"
 	WebhookConfigurationPolicyNamespaceAnnotationKey = "kubewardenPolicyNamespace"
 
@@ -59,6 +111,15 @@ const (
 	TimeToRequeuePolicyReconciliation = 2 * time.Second
 	MetricsShutdownTimeout            = 5 * time.Second
 
+	// DefaultPolicyServerRestartGracePeriod is the default Duration during which
+	// the status of an active policy is held after its policy server restarts.
+	DefaultPolicyServerRestartGracePeriod = 30 * time.Second
+
+	// DefaultPolicyServerValidationLookupRetries is the default number of
+	// retries of the lookups of the objects referenced by a PolicyServer
+	// during its validation.
+	DefaultPolicyServerValidationLookupRetries = 3
+
 	WebhookServerCertSecretName = "kubewarden-webhook-server-cert" //nolint:gosec // This is not a credential
 	ServerCert                  = "tls.crt"
 	ServerPrivateKey            = "tls.key"
@@ -74,4 +135,9 @@ const (
 	CACertExpiration     = 10 * 365 * 24 * time.Hour
 	ServerCertExpiration = 1 * 365 * 24 * time.Hour
 	CertLookahead        = 60 * 24 * time.Hour
+
+	// DefaultCertExpirationWarningThreshold is the default Duration before
+	// the expiration of a policy server certificate after which the
+	// certificate is reported as about to expire.
+	DefaultCertExpirationWarningThreshold = 30 * 24 * time.Hour
 )
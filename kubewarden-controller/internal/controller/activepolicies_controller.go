@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+// ActivePoliciesReconciler maintains a ConfigMap listing the active policies
+// and the policy servers hosting them, for the consumers that can read
+// ConfigMaps but cannot list the Kubewarden custom resources.
+type ActivePoliciesReconciler struct {
+	client.Client
+	Log                  logr.Logger
+	DeploymentsNamespace string
+	// ConfigMapName is the name of the ConfigMap created in the deployments
+	// namespace.
+	ConfigMapName string
+	// MaxSize is the maximum size of the policies listed in the ConfigMap.
+	// The list is truncated when it does not fit.
+	MaxSize int
+}
+
+// activePolicy is an entry of the active policies ConfigMap.
+type activePolicy struct {
+	Kind         string `json:"kind"`
+	Namespace    string `json:"namespace,omitempty"`
+	Name         string `json:"name"`
+	PolicyServer string `json:"policyServer"`
+	Mode         string `json:"mode,omitempty"`
+}
+
+func (r *ActivePoliciesReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	policies, err := ListPolicies(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	activePolicies, err := r.activePolicies(policies)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	listed, truncated, err := marshalActivePolicies(activePolicies, r.MaxSize)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.ConfigMapName,
+			Namespace: r.DeploymentsNamespace,
+		},
+	}
+	_, err = controllerutil.CreateOrPatch(ctx, r.Client, configMap, func() error {
+		configMap.Labels = map[string]string{
+			constants.PartOfLabelKey: constants.PartOfLabelValue,
+		}
+		configMap.Data = map[string]string{
+			constants.ActivePoliciesConfigMapPoliciesEntry:  listed,
+			constants.ActivePoliciesConfigMapTotalEntry:     strconv.Itoa(len(activePolicies)),
+			constants.ActivePoliciesConfigMapTruncatedEntry: strconv.FormatBool(truncated),
+		}
+		return nil
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile active policies ConfigMap: %w", err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// activePolicies returns the active policies, sorted by kind, namespace and
+// name.
+func (r *ActivePoliciesReconciler) activePolicies(policies []policiesv1.Policy) ([]activePolicy, error) {
+	activePolicies := []activePolicy{}
+	for _, policy := range policies {
+		if policy.GetStatus().PolicyStatus != policiesv1.PolicyStatusActive {
+			continue
+		}
+
+		gvk, err := apiutil.GVKForObject(policy, r.Scheme())
+		if err != nil {
+			return nil, fmt.Errorf("cannot get the kind of policy %q: %w", policy.GetUniqueName(), err)
+		}
+		activePolicies = append(activePolicies, activePolicy{
+			Kind:         gvk.Kind,
+			Namespace:    policy.GetNamespace(),
+			Name:         policy.GetName(),
+			PolicyServer: policy.GetPolicyServer(),
+			Mode:         string(policy.GetStatus().PolicyMode),
+		})
+	}
+
+	slices.SortFunc(activePolicies, func(a, b activePolicy) int {
+		return cmp.Or(
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	return activePolicies, nil
+}
+
+// marshalActivePolicies returns the JSON list of the given policies. The
+// policies not fitting in maxSize bytes are left out, and truncated is true.
+func marshalActivePolicies(activePolicies []activePolicy, maxSize int) (string, bool, error) {
+	listed := []byte("[")
+	for i, policy := range activePolicies {
+		entry, err := json.Marshal(policy)
+		if err != nil {
+			return "", false, fmt.Errorf("cannot marshal active policy: %w", err)
+		}
+		// Room for the separator and the closing bracket
+		if len(listed)+len(entry)+2 > maxSize {
+			return string(append(listed, ']')), true, nil
+		}
+		if i > 0 {
+			listed = append(listed, ',')
+		}
+		listed = append(listed, entry...)
+	}
+
+	return string(append(listed, ']')), false, nil
+}
+
+func (r *ActivePoliciesReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.ConfigMapName == "" {
+		return errors.New("the active policies ConfigMap name is required")
+	}
+
+	// All the events are mapped to the same request, the whole list is
+	// rebuilt on each reconciliation.
+	enqueue := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+		return []reconcile.Request{
+			{NamespacedName: types.NamespacedName{Namespace: r.DeploymentsNamespace, Name: r.ConfigMapName}},
+		}
+	})
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named("active-policies").
+		Watches(&policiesv1.AdmissionPolicy{}, enqueue).
+		Watches(&policiesv1.AdmissionPolicyGroup{}, enqueue).
+		Watches(&policiesv1.ClusterAdmissionPolicy{}, enqueue).
+		Watches(&policiesv1.ClusterAdmissionPolicyGroup{}, enqueue).
+		// Watch the ConfigMap to restore it when changed or deleted
+		Watches(&corev1.ConfigMap{}, enqueue, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			return object.GetNamespace() == r.DeploymentsNamespace && object.GetName() == r.ConfigMapName
+		}))).
+		Complete(r)
+	if err != nil {
+		return errors.Join(errors.New("failed enrolling controller with manager"), err)
+	}
+
+	return nil
+}
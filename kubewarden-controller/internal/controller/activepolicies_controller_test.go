@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+var _ = Describe("Active policies controller", func() {
+	ctx := context.Background()
+	configMapKey := types.NamespacedName{Namespace: "kubewarden", Name: "active-policies"}
+
+	var k8sClient client.Client
+	var reconciler *ActivePoliciesReconciler
+
+	activePolicy := func(policy policiesv1.Policy) policiesv1.Policy {
+		policy.SetStatus(policiesv1.PolicyStatusActive)
+		policy.SetPolicyModeStatus(policiesv1.PolicyModeStatusProtect)
+		return policy
+	}
+
+	reconcileAndGetConfigMap := func() *corev1.ConfigMap {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: configMapKey})
+		Expect(err).ToNot(HaveOccurred())
+
+		configMap := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, configMapKey, configMap)).To(Succeed())
+		return configMap
+	}
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(testScheme)).To(Succeed())
+		Expect(policiesv1.AddToScheme(testScheme)).To(Succeed())
+
+		k8sClient = fake.NewClientBuilder().WithScheme(testScheme).Build()
+		reconciler = &ActivePoliciesReconciler{
+			Client:               k8sClient,
+			DeploymentsNamespace: configMapKey.Namespace,
+			ConfigMapName:        configMapKey.Name,
+			MaxSize:              constants.ActivePoliciesConfigMapMaxSize,
+		}
+	})
+
+	It("should list the active policies and their policy servers", func() {
+		Expect(k8sClient.Create(ctx, activePolicy(policiesv1.NewClusterAdmissionPolicyFactory().WithName("cluster-policy").WithPolicyServer("default").Build()))).To(Succeed())
+		Expect(k8sClient.Create(ctx, activePolicy(policiesv1.NewAdmissionPolicyFactory().WithName("policy").WithNamespace("team").WithPolicyServer("reserved").Build()))).To(Succeed())
+		pendingPolicy := policiesv1.NewAdmissionPolicyFactory().WithName("pending-policy").WithNamespace("team").Build()
+		pendingPolicy.SetStatus(policiesv1.PolicyStatusPending)
+		Expect(k8sClient.Create(ctx, pendingPolicy)).To(Succeed())
+
+		configMap := reconcileAndGetConfigMap()
+
+		Expect(configMap.Labels).To(HaveKeyWithValue(constants.PartOfLabelKey, constants.PartOfLabelValue))
+		Expect(configMap.Data).To(Equal(map[string]string{
+			constants.ActivePoliciesConfigMapPoliciesEntry: `[` +
+				`{"kind":"AdmissionPolicy","namespace":"team","name":"policy","policyServer":"reserved","mode":"protect"},` +
+				`{"kind":"ClusterAdmissionPolicy","name":"cluster-policy","policyServer":"default","mode":"protect"}` +
+				`]`,
+			constants.ActivePoliciesConfigMapTotalEntry:     "2",
+			constants.ActivePoliciesConfigMapTruncatedEntry: "false",
+		}))
+	})
+
+	It("should reflect the policies added and removed", func() {
+		Expect(reconcileAndGetConfigMap().Data).To(HaveKeyWithValue(constants.ActivePoliciesConfigMapPoliciesEntry, "[]"))
+
+		By("adding a policy")
+		policy := activePolicy(policiesv1.NewClusterAdmissionPolicyGroupFactory().WithName("group").WithPolicyServer("default").Build())
+		Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+		Expect(reconcileAndGetConfigMap().Data).To(And(
+			HaveKeyWithValue(constants.ActivePoliciesConfigMapPoliciesEntry, `[{"kind":"ClusterAdmissionPolicyGroup","name":"group","policyServer":"default","mode":"protect"}]`),
+			HaveKeyWithValue(constants.ActivePoliciesConfigMapTotalEntry, "1"),
+		))
+
+		By("removing the policy")
+		Expect(k8sClient.Delete(ctx, policy)).To(Succeed())
+		Expect(reconcileAndGetConfigMap().Data).To(And(
+			HaveKeyWithValue(constants.ActivePoliciesConfigMapPoliciesEntry, "[]"),
+			HaveKeyWithValue(constants.ActivePoliciesConfigMapTotalEntry, "0"),
+		))
+	})
+
+	It("should truncate the policies not fitting in the ConfigMap", func() {
+		for _, name := range []string{"policy-a", "policy-b", "policy-c"} {
+			Expect(k8sClient.Create(ctx, activePolicy(policiesv1.NewClusterAdmissionPolicyFactory().WithName(name).WithPolicyServer("default").Build()))).To(Succeed())
+		}
+		reconciler.MaxSize = 200
+
+		Expect(reconcileAndGetConfigMap().Data).To(Equal(map[string]string{
+			constants.ActivePoliciesConfigMapPoliciesEntry: `[` +
+				`{"kind":"ClusterAdmissionPolicy","name":"policy-a","policyServer":"default","mode":"protect"},` +
+				`{"kind":"ClusterAdmissionPolicy","name":"policy-b","policyServer":"default","mode":"protect"}` +
+				`]`,
+			constants.ActivePoliciesConfigMapTotalEntry:     "3",
+			constants.ActivePoliciesConfigMapTruncatedEntry: "true",
+		}))
+	})
+})
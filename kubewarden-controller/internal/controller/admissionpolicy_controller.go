@@ -20,15 +20,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
@@ -55,7 +65,22 @@ type AdmissionPolicyReconciler struct {
 	Scheme                                     *runtime.Scheme
 	DeploymentsNamespace                       string
 	FeatureGateAdmissionWebhookMatchConditions bool
-	policySubReconciler                        *policySubReconciler
+	// PolicyServerRestartGracePeriod is the time during which the status of
+	// an active policy is held after its policy server restarts.
+	PolicyServerRestartGracePeriod time.Duration
+	// MaxConcurrentReconciles is the maximum number of policies reconciled
+	// concurrently. The controller-runtime default of one is used when it
+	// is not set.
+	MaxConcurrentReconciles int
+	// Tracer starts the spans of the reconciliations. The spans are not
+	// recorded when it is nil.
+	Tracer trace.Tracer
+	// NamespaceAllowList is the list of the namespaces whose AdmissionPolicies
+	// are reconciled. The webhooks of the AdmissionPolicies of the other
+	// namespaces are removed. The AdmissionPolicies of all the namespaces are
+	// reconciled when it is empty.
+	NamespaceAllowList  []string
+	policySubReconciler *policySubReconciler
 }
 
 // Reconcile reconciles admission policies.
@@ -68,9 +93,57 @@ func (r *AdmissionPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, nil
 	}
 
+	// The deletion of the policies is always reconciled, otherwise the
+	// policies created before their namespace was removed from the
+	// allow-list would be stuck on their finalizer.
+	if admissionPolicy.GetDeletionTimestamp() == nil && !isNamespaceAllowed(r.NamespaceAllowList, admissionPolicy.GetNamespace()) {
+		return r.reconcileNotAllowedPolicy(ctx, &admissionPolicy)
+	}
+	apimeta.RemoveStatusCondition(&admissionPolicy.Status.Conditions, string(policiesv1.PolicyNamespaceNotAllowed))
+
 	return r.policySubReconciler.reconcile(ctx, &admissionPolicy)
 }
 
+// reconcileNotAllowedPolicy removes the webhook of an AdmissionPolicy outside
+// of the namespace allow-list, the policy is not loaded by its policy server
+// either. The policy is reported as unscheduled. The status is updated only
+// when it changes, to not trigger a new reconciliation on each update.
+func (r *AdmissionPolicyReconciler) reconcileNotAllowedPolicy(ctx context.Context, admissionPolicy *policiesv1.AdmissionPolicy) (ctrl.Result, error) {
+	if err := r.policySubReconciler.deletePolicyWebhookConfiguration(ctx, admissionPolicy); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	originalStatus := admissionPolicy.Status.DeepCopy()
+	apimeta.SetStatusCondition(&admissionPolicy.Status.Conditions, metav1.Condition{
+		Type:    string(policiesv1.PolicyNamespaceNotAllowed),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(policiesv1.NamespaceNotAllowed),
+		Message: fmt.Sprintf("The namespace %s is not in the namespace allow-list of the controller", admissionPolicy.GetNamespace()),
+	})
+	apimeta.SetStatusCondition(&admissionPolicy.Status.Conditions, metav1.Condition{
+		Type:    string(policiesv1.PolicyActive),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(policiesv1.NamespaceNotAllowed),
+		Message: "The policy webhook has not been created",
+	})
+	admissionPolicy.SetStatus(policiesv1.PolicyStatusUnscheduled)
+	if equality.Semantic.DeepEqual(originalStatus, &admissionPolicy.Status) {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.Status().Update(ctx, admissionPolicy); err != nil {
+		return ctrl.Result{}, fmt.Errorf("update admission policy status error: %w", err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// isNamespaceAllowed returns true when the AdmissionPolicies of the given
+// namespace are reconciled with the given namespace allow-list.
+func isNamespaceAllowed(namespaceAllowList []string, namespace string) bool {
+	return len(namespaceAllowList) == 0 || slices.Contains(namespaceAllowList, namespace)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *AdmissionPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.policySubReconciler = &policySubReconciler{
@@ -78,6 +151,9 @@ func (r *AdmissionPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		r.Log,
 		r.DeploymentsNamespace,
 		r.FeatureGateAdmissionWebhookMatchConditions,
+		r.PolicyServerRestartGracePeriod,
+		clock.RealClock{},
+		r.Tracer,
 	}
 
 	err := ctrl.NewControllerManagedBy(mgr).
@@ -86,6 +162,19 @@ func (r *AdmissionPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&corev1.Pod{},
 			handler.EnqueueRequestsFromMapFunc(r.findAdmissionPoliciesForPod),
 		).
+		// Watch the policy servers to apply the changes of their policy
+		// defaults to the bound policies
+		Watches(
+			&policiesv1.PolicyServer{},
+			handler.EnqueueRequestsFromMapFunc(r.findAdmissionPoliciesForPolicyServer),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		// Watch the externally managed certificates of the policy servers to
+		// refresh the CA bundle of the webhooks
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.findAdmissionPoliciesForSecret),
+		).
 		Watches(
 			&admissionregistrationv1.ValidatingWebhookConfiguration{},
 			handler.EnqueueRequestsFromMapFunc(r.findAdmissionPolicyForWebhookConfiguration),
@@ -94,6 +183,7 @@ func (r *AdmissionPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&admissionregistrationv1.MutatingWebhookConfiguration{},
 			handler.EnqueueRequestsFromMapFunc(r.findAdmissionPolicyForWebhookConfiguration),
 		).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 	if err != nil {
 		return errors.Join(errors.New("failed enrolling controller with manager"), err)
@@ -106,6 +196,14 @@ func (r *AdmissionPolicyReconciler) findAdmissionPoliciesForPod(ctx context.Cont
 	return findPoliciesForPod(ctx, r.Client, object)
 }
 
+func (r *AdmissionPolicyReconciler) findAdmissionPoliciesForPolicyServer(ctx context.Context, object client.Object) []reconcile.Request {
+	return findPoliciesForPolicyServer(ctx, r.Client, &policiesv1.AdmissionPolicyList{}, object)
+}
+
+func (r *AdmissionPolicyReconciler) findAdmissionPoliciesForSecret(ctx context.Context, object client.Object) []reconcile.Request {
+	return findPoliciesForCertificateSecret(ctx, r.Client, r.DeploymentsNamespace, &policiesv1.AdmissionPolicyList{}, object)
+}
+
 func (r *AdmissionPolicyReconciler) findAdmissionPolicyForWebhookConfiguration(_ context.Context, webhookConfiguration client.Object) []reconcile.Request {
 	if !hasKubewardenLabel(webhookConfiguration.GetLabels()) {
 		return []reconcile.Request{}
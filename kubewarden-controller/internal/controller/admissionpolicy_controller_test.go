@@ -22,11 +22,16 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
@@ -373,3 +378,95 @@ var _ = Describe("AdmissionPolicy controller", Label("real-cluster"), func() {
 		})
 	})
 })
+
+var _ = Describe("AdmissionPolicy namespace allow-list", func() {
+	ctx := context.Background()
+
+	var reconciler *AdmissionPolicyReconciler
+
+	BeforeEach(func() {
+		allowedPolicy := policiesv1.NewAdmissionPolicyFactory().WithName("allowed").WithNamespace("allowed").WithPolicyServer("").Build()
+		notAllowedPolicy := policiesv1.NewAdmissionPolicyFactory().WithName("not-allowed").WithNamespace("not-allowed").WithPolicyServer("").Build()
+		notAllowedPolicyWebhook := &admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: notAllowedPolicy.GetUniqueName()},
+		}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(newFakeClientTestScheme()).
+			WithObjects(allowedPolicy, notAllowedPolicy, notAllowedPolicyWebhook).
+			WithStatusSubresource(allowedPolicy, notAllowedPolicy).
+			Build()
+		reconciler = &AdmissionPolicyReconciler{
+			Client:             fakeClient,
+			NamespaceAllowList: []string{"allowed"},
+			policySubReconciler: &policySubReconciler{
+				Client:               fakeClient,
+				deploymentsNamespace: deploymentsNamespace,
+			},
+		}
+	})
+
+	reconcilePolicy := func(namespace, name string) *policiesv1.AdmissionPolicy {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}})
+		Expect(err).ToNot(HaveOccurred())
+		storedPolicy := &policiesv1.AdmissionPolicy{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, storedPolicy)).To(Succeed())
+		return storedPolicy
+	}
+
+	It("should reconcile the policies of the allowed namespaces", func() {
+		storedPolicy := reconcilePolicy("allowed", "allowed")
+
+		Expect(apimeta.FindStatusCondition(storedPolicy.Status.Conditions, string(policiesv1.PolicyNamespaceNotAllowed))).To(BeNil())
+		Expect(storedPolicy.Status.PolicyStatus).To(Equal(policiesv1.PolicyStatusUnscheduled))
+	})
+
+	It("should remove the webhooks of the policies of the other namespaces", func() {
+		storedPolicy := reconcilePolicy("not-allowed", "not-allowed")
+
+		Expect(apimeta.FindStatusCondition(storedPolicy.Status.Conditions, string(policiesv1.PolicyNamespaceNotAllowed))).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionTrue),
+			"Reason": Equal(string(policiesv1.NamespaceNotAllowed)),
+		})))
+		Expect(apimeta.FindStatusCondition(storedPolicy.Status.Conditions, string(policiesv1.PolicyActive))).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionFalse),
+			"Reason": Equal(string(policiesv1.NamespaceNotAllowed)),
+		})))
+		Expect(storedPolicy.Status.PolicyStatus).To(Equal(policiesv1.PolicyStatusUnscheduled))
+
+		err := reconciler.Get(ctx, types.NamespacedName{Name: storedPolicy.GetUniqueName()}, &admissionregistrationv1.ValidatingWebhookConfiguration{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should not update the status of the policies of the other namespaces when it does not change", func() {
+		storedPolicy := reconcilePolicy("not-allowed", "not-allowed")
+
+		Expect(reconcilePolicy("not-allowed", "not-allowed").GetResourceVersion()).To(Equal(storedPolicy.GetResourceVersion()))
+	})
+
+	It("should reconcile the policies of all the namespaces when the allow-list is empty", func() {
+		reconciler.NamespaceAllowList = nil
+
+		storedPolicy := reconcilePolicy("not-allowed", "not-allowed")
+
+		Expect(apimeta.FindStatusCondition(storedPolicy.Status.Conditions, string(policiesv1.PolicyNamespaceNotAllowed))).To(BeNil())
+		Expect(storedPolicy.Status.PolicyStatus).To(Equal(policiesv1.PolicyStatusUnscheduled))
+	})
+})
+
+var _ = Describe("AdmissionPolicy policy server watch", func() {
+	It("should enqueue the policies bound to the policy server", func() {
+		policyServer := policiesv1.NewPolicyServerFactory().WithName("default").Build()
+		boundPolicy := policiesv1.NewAdmissionPolicyFactory().WithName("bound").WithNamespace("default").WithPolicyServer("default").Build()
+		otherPolicy := policiesv1.NewAdmissionPolicyFactory().WithName("other").WithNamespace("default").WithPolicyServer("other").Build()
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(newFakeClientTestScheme()).
+			WithObjects(boundPolicy, otherPolicy).
+			WithIndex(&policiesv1.AdmissionPolicy{}, constants.PolicyServerIndexKey, indexPolicyServer).
+			Build()
+		reconciler := &AdmissionPolicyReconciler{Client: fakeClient}
+
+		Expect(reconciler.findAdmissionPoliciesForPolicyServer(context.Background(), policyServer)).To(Equal([]ctrl.Request{
+			{NamespacedName: types.NamespacedName{Namespace: "default", Name: "bound"}},
+		}))
+	})
+})
@@ -9,16 +9,32 @@ import (
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
 	"github.com/kubewarden/kubewarden-controller/internal/certs"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
 )
 
-const tickerDuration = 12 * time.Hour
+const (
+	// tickerDuration is the maximum Duration between two reconciliations.
+	tickerDuration = 12 * time.Hour
+	// retryDuration is the minimum Duration between two reconciliations,
+	// used when the reconciliation fails or when a certificate is still
+	// within its renewal window after a reconciliation.
+	retryDuration = 5 * time.Minute
+)
 
 type CertReconciler struct {
 	client.Client
@@ -27,37 +43,67 @@ type CertReconciler struct {
 	WebhookServiceName          string
 	CARootSecretName            string
 	WebhookServerCertSecretName string
+	// CertExpirationWarningThreshold is the Duration before the expiration
+	// of a policy server certificate after which the CertificateValid
+	// condition of the policy server is set to false.
+	CertExpirationWarningThreshold time.Duration
+	// CertRenewalWindow is the Duration before the expiration of a
+	// certificate after which the certificate is rotated. Defaults to
+	// constants.CertLookahead when zero.
+	CertRenewalWindow time.Duration
+	// CACertDuration is the validity of the generated CA root certificates.
+	// Defaults to constants.CACertExpiration when zero.
+	CACertDuration time.Duration
+	// ServerCertDuration is the validity of the generated server
+	// certificates. Defaults to constants.ServerCertExpiration when zero.
+	ServerCertDuration time.Duration
+	// CertKeyAlgorithm is the algorithm of the private keys of the generated
+	// certificates. The certificates using a different algorithm are
+	// rotated. Defaults to certs.DefaultKeyAlgorithm when empty.
+	CertKeyAlgorithm certs.KeyAlgorithm
+
+	// nextReconcile is the earliest time a certificate observed by the last
+	// reconciliation enters its renewal window, crosses the expiration
+	// warning threshold or expires.
+	nextReconcile time.Time
 }
 
-// Start begins the periodic reconciler.
-// Implements the Runnable inteface, see https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable.
-func (r *CertReconciler) Start(ctx context.Context) error {
-	r.Log.Info("Starting CertController ticker")
-
-	ticker := time.NewTicker(tickerDuration)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			r.Log.Info("Stopping CertController")
-			return nil
-		case <-ticker.C:
-			if err := r.reconcile(ctx); err != nil {
-				r.Log.Error(err, "Failed to")
-			}
-		}
+// Reconcile reconciles the certificates. The certificates are reconciled when
+// the controller starts and then when the earliest certificate enters its
+// renewal window or crosses the expiration warning threshold, at least every
+// tickerDuration.
+func (r *CertReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	err := r.reconcile(ctx)
+	if err != nil {
+		r.Log.Error(err, "Failed to reconcile certificates")
 	}
+	delay := r.nextReconcileDelay(err)
+	r.Log.Info("Next certificates reconciliation scheduled", "after", delay)
+
+	return ctrl.Result{RequeueAfter: delay}, nil
 }
 
-// NeedLeaderElection returns true to ensure that only one instance of the controller is running at a time.
-// Implements the LeaderElectionRunnable interface, see https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#LeaderElectionRunnable.
-func (r *CertReconciler) NeedLeaderElection() bool {
-	return true
+// controllerOptions returns the options of the cert controller. The cert
+// controller always runs a single reconciliation at a time, regardless of the
+// MaxConcurrentReconciles configured for the controllers of the manager,
+// because the reconciliations rotate shared certificates.
+func (r *CertReconciler) controllerOptions() controller.Options {
+	return controller.Options{MaxConcurrentReconciles: 1}
 }
 
+// SetupWithManager sets up the controller with the Manager. The certificates
+// are not bound to a watched resource: a single request is enqueued when the
+// controller starts, and requeued by each reconciliation.
 func (r *CertReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	if err := mgr.Add(r); err != nil {
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named("cert").
+		WithOptions(r.controllerOptions()).
+		WatchesRawSource(source.Func(func(_ context.Context, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) error {
+			queue.Add(ctrl.Request{NamespacedName: types.NamespacedName{Name: r.CARootSecretName, Namespace: r.DeploymentsNamespace}})
+			return nil
+		})).
+		Complete(r)
+	if err != nil {
 		return fmt.Errorf("failed enrolling controller with manager: %w", err)
 	}
 
@@ -66,6 +112,8 @@ func (r *CertReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 // reconcile reconciles the CA root and server certificates by rotating them if they are about to expire.
 func (r *CertReconciler) reconcile(ctx context.Context) error {
+	r.nextReconcile = time.Time{}
+
 	caCertSecret := &corev1.Secret{}
 	if err := r.Get(ctx, types.NamespacedName{Name: r.CARootSecretName, Namespace: r.DeploymentsNamespace}, caCertSecret); err != nil {
 		return fmt.Errorf("failed to get CA cert secret: %w", err)
@@ -93,11 +141,15 @@ func (r *CertReconciler) reconcileCARoot(ctx context.Context, caRootSecret *core
 		return fmt.Errorf("failed to extract CA root from secret: %w", err)
 	}
 
-	if err = certs.VerifyCA(caCert, caPrivateKey, time.Now().Add(constants.CertLookahead)); err != nil {
+	err = certs.VerifyCA(caCert, caPrivateKey, time.Now().Add(r.renewalWindow()))
+	if err == nil {
+		err = r.verifyKeyAlgorithm(caCert)
+	}
+	if err != nil {
 		r.Log.Info("CA root certificate verification failed, rotating CA root the certificate", "verification error", err)
 
 		oldCACert := caCert
-		caCert, caPrivateKey, err = certs.GenerateCA(time.Now(), time.Now().Add(constants.CACertExpiration))
+		caCert, caPrivateKey, err = certs.GenerateCAWithKeyAlgorithm(time.Now(), time.Now().Add(durationOrDefault(r.CACertDuration, constants.CACertExpiration)), r.keyAlgorithm())
 		if err != nil {
 			return fmt.Errorf("failed to generate CA cert: %w", err)
 		}
@@ -116,6 +168,7 @@ func (r *CertReconciler) reconcileCARoot(ctx context.Context, caRootSecret *core
 
 		r.Log.Info("CA root certificate rotated successfully")
 	}
+	r.scheduleRenewal(caCert)
 
 	return nil
 }
@@ -148,6 +201,11 @@ func (r *CertReconciler) reconcileOldCARoot(ctx context.Context, caRootSecret *c
 		}
 
 		r.Log.Info("Old CA root certificate removed successfully")
+
+		return nil
+	}
+	if notAfter, err := certs.CertificateNotAfter(oldCACert); err == nil {
+		r.scheduleReconcile(notAfter)
 	}
 
 	return nil
@@ -157,8 +215,13 @@ func (r *CertReconciler) reconcileOldCARoot(ctx context.Context, caRootSecret *c
 // Note that we are using RetryOnConflict to handle potential conflicts when updating the webhook configurations.
 // This is necessary because the webhook configurations could be update by the AdmissionPolicy and ClusterAdmissionPolicy controllers.
 func (r *CertReconciler) reconcileWebhookConfigurations(ctx context.Context, caBundle []byte) error {
+	externalCertServiceNames, err := r.externalCertServiceNames(ctx)
+	if err != nil {
+		return err
+	}
+
 	validatingWebhookConfigurationList := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
-	if err := r.List(ctx, validatingWebhookConfigurationList, client.MatchingLabels{
+	if err = r.List(ctx, validatingWebhookConfigurationList, client.MatchingLabels{
 		constants.PartOfLabelKey: constants.PartOfLabelValue,
 	}); err != nil {
 		return fmt.Errorf("failed to list validating webhook configurations: %w", err)
@@ -167,10 +230,13 @@ func (r *CertReconciler) reconcileWebhookConfigurations(ctx context.Context, caB
 	for _, validatingWebhookConfiguration := range validatingWebhookConfigurationList.Items {
 		original := validatingWebhookConfiguration.DeepCopy()
 		for i := range validatingWebhookConfiguration.Webhooks {
-			validatingWebhookConfiguration.Webhooks[i].ClientConfig.CABundle = caBundle
+			validatingWebhookConfiguration.Webhooks[i].ClientConfig.CABundle = webhookCABundle(validatingWebhookConfiguration.Webhooks[i].ClientConfig, caBundle, externalCertServiceNames)
+		}
+		if equality.Semantic.DeepEqual(original.Webhooks, validatingWebhookConfiguration.Webhooks) {
+			continue
 		}
 
-		err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 			return r.Patch(ctx, &validatingWebhookConfiguration, client.MergeFrom(original))
 		})
 		if err != nil {
@@ -179,7 +245,7 @@ func (r *CertReconciler) reconcileWebhookConfigurations(ctx context.Context, caB
 	}
 
 	mutatingWebhookConfigurationList := &admissionregistrationv1.MutatingWebhookConfigurationList{}
-	if err := r.List(ctx, mutatingWebhookConfigurationList, client.MatchingLabels{
+	if err = r.List(ctx, mutatingWebhookConfigurationList, client.MatchingLabels{
 		constants.PartOfLabelKey: constants.PartOfLabelValue,
 	}); err != nil {
 		return fmt.Errorf("failed to list mutating webhook configurations: %w", err)
@@ -188,10 +254,13 @@ func (r *CertReconciler) reconcileWebhookConfigurations(ctx context.Context, caB
 	for _, mutatingWebhookConfiguration := range mutatingWebhookConfigurationList.Items {
 		original := mutatingWebhookConfiguration.DeepCopy()
 		for i := range mutatingWebhookConfiguration.Webhooks {
-			mutatingWebhookConfiguration.Webhooks[i].ClientConfig.CABundle = caBundle
+			mutatingWebhookConfiguration.Webhooks[i].ClientConfig.CABundle = webhookCABundle(mutatingWebhookConfiguration.Webhooks[i].ClientConfig, caBundle, externalCertServiceNames)
+		}
+		if equality.Semantic.DeepEqual(original.Webhooks, mutatingWebhookConfiguration.Webhooks) {
+			continue
 		}
 
-		err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 			return r.Patch(ctx, &mutatingWebhookConfiguration, client.MergeFrom(original))
 		})
 		if err != nil {
@@ -202,6 +271,37 @@ func (r *CertReconciler) reconcileWebhookConfigurations(ctx context.Context, caB
 	return nil
 }
 
+// externalCertServiceNames returns the names of the Services of the policy
+// servers using an externally managed certificate. The CA bundle of the
+// webhooks served by these policy servers is owned by the policy reconcilers,
+// which read it from the certificate secret.
+func (r *CertReconciler) externalCertServiceNames(ctx context.Context) (sets.Set[string], error) {
+	policyServerList := &policiesv1.PolicyServerList{}
+	if err := r.List(ctx, policyServerList); err != nil {
+		return nil, fmt.Errorf("failed to list policy servers: %w", err)
+	}
+
+	serviceNames := sets.New[string]()
+	for _, policyServer := range policyServerList.Items {
+		if policyServer.Spec.CertificateSecret != "" {
+			serviceNames.Insert(policyServer.NameWithPrefix())
+		}
+	}
+
+	return serviceNames, nil
+}
+
+// webhookCABundle returns the CA bundle of the webhook client configuration:
+// caBundle, unless the webhook is served by a policy server using an
+// externally managed certificate, whose current CA bundle is kept.
+func webhookCABundle(clientConfig admissionregistrationv1.WebhookClientConfig, caBundle []byte, externalCertServiceNames sets.Set[string]) []byte {
+	if clientConfig.Service != nil && externalCertServiceNames.Has(clientConfig.Service.Name) {
+		return clientConfig.CABundle
+	}
+
+	return caBundle
+}
+
 // reconcileServerCerts reconciles the webhook server and policy server certificates by rotating them if they are about to expire.
 func (r *CertReconciler) reconcileServerCerts(ctx context.Context, caRootSecret *corev1.Secret) error {
 	webhookServerCertSecret := &corev1.Secret{}
@@ -227,10 +327,22 @@ func (r *CertReconciler) reconcileServerCerts(ctx context.Context, caRootSecret
 	}
 
 	for _, serverCertSecret := range serverCertSecretList.Items {
+		var orphaned bool
+		orphaned, err = r.deleteOrphanedPolicyServerCertSecret(ctx, &serverCertSecret)
+		if err != nil {
+			return fmt.Errorf("failed to delete orphaned policy server cert secret: %w", err)
+		}
+		if orphaned {
+			continue
+		}
+
 		dnsName = certs.DNSName(serverCertSecret.GetName(), r.DeploymentsNamespace)
 		if err = r.reconcileServerCert(ctx, &serverCertSecret, caRootSecret, dnsName); err != nil {
 			return fmt.Errorf("failed to rotate server cert: %w", err)
 		}
+		if err = r.reconcilePolicyServerCertStatus(ctx, &serverCertSecret); err != nil {
+			return fmt.Errorf("failed to update policy server certificate status: %w", err)
+		}
 	}
 
 	return nil
@@ -253,11 +365,15 @@ func (r *CertReconciler) reconcileServerCert(ctx context.Context, serverCertSecr
 		return fmt.Errorf("failed to create cert pool: %w", err)
 	}
 
-	if err = certs.VerifyCert(cert, privateKey, pool, dnsName, time.Now().Add(constants.CertLookahead)); err != nil {
+	err = certs.VerifyCert(cert, privateKey, pool, dnsName, time.Now().Add(r.renewalWindow()))
+	if err == nil {
+		err = r.verifyKeyAlgorithm(cert)
+	}
+	if err != nil {
 		r.Log.Info("Certificate verification failed, rotating the certificate", "dnsName", dnsName, "verification error", err)
 
 		var newCert, newPrivateKey []byte
-		newCert, newPrivateKey, err = certs.GenerateCert(caCert, caPrivateKey, time.Now(), time.Now().Add(constants.ServerCertExpiration), dnsName)
+		newCert, newPrivateKey, err = certs.GenerateCertWithKeyAlgorithm(caCert, caPrivateKey, time.Now(), time.Now().Add(durationOrDefault(r.ServerCertDuration, constants.ServerCertExpiration)), dnsName, r.keyAlgorithm())
 		if err != nil {
 			return fmt.Errorf("failed to generate cert: %w", err)
 		}
@@ -271,6 +387,173 @@ func (r *CertReconciler) reconcileServerCert(ctx context.Context, serverCertSecr
 
 		r.Log.Info("Certificate rotated successfully", "dnsName", dnsName)
 	}
+	r.scheduleRenewal(serverCertSecret.Data[constants.ServerCert])
+
+	return nil
+}
+
+// renewalWindow returns the Duration before the expiration of a certificate
+// after which the certificate is rotated.
+func (r *CertReconciler) renewalWindow() time.Duration {
+	return durationOrDefault(r.CertRenewalWindow, constants.CertLookahead)
+}
+
+// keyAlgorithm returns the algorithm of the private keys of the generated
+// certificates.
+func (r *CertReconciler) keyAlgorithm() certs.KeyAlgorithm {
+	if r.CertKeyAlgorithm == "" {
+		return certs.DefaultKeyAlgorithm
+	}
+
+	return r.CertKeyAlgorithm
+}
+
+// verifyKeyAlgorithm checks the key of the certificate uses the configured
+// algorithm.
+func (r *CertReconciler) verifyKeyAlgorithm(certPEM []byte) error {
+	keyAlgorithm, err := certs.CertificateKeyAlgorithm(certPEM)
+	if err != nil {
+		return fmt.Errorf("failed to read the certificate key algorithm: %w", err)
+	}
+	if keyAlgorithm != r.keyAlgorithm() {
+		return fmt.Errorf("the certificate key algorithm %s does not match the configured key algorithm %s", keyAlgorithm, r.keyAlgorithm())
+	}
+
+	return nil
+}
+
+// durationOrDefault returns the duration, or defaultDuration when the duration
+// is not set.
+func durationOrDefault(duration, defaultDuration time.Duration) time.Duration {
+	if duration <= 0 {
+		return defaultDuration
+	}
+
+	return duration
+}
+
+// scheduleRenewal schedules a reconciliation when the certificate enters its
+// renewal window.
+func (r *CertReconciler) scheduleRenewal(certPEM []byte) {
+	notAfter, err := certs.CertificateNotAfter(certPEM)
+	if err != nil {
+		r.Log.Error(err, "Cannot schedule the certificate renewal")
+		return
+	}
+
+	r.scheduleReconcile(notAfter.Add(-r.renewalWindow()))
+}
+
+// scheduleReconcile schedules a reconciliation at the given time, unless one
+// is already scheduled earlier.
+func (r *CertReconciler) scheduleReconcile(at time.Time) {
+	if r.nextReconcile.IsZero() || at.Before(r.nextReconcile) {
+		r.nextReconcile = at
+	}
+}
+
+// nextReconcileDelay returns the Duration to wait before the next
+// reconciliation, bounded by retryDuration and tickerDuration. The
+// reconciliation is retried after retryDuration when the given error of the
+// last reconciliation is not nil, because the certificates it did not reach
+// have not been scheduled.
+func (r *CertReconciler) nextReconcileDelay(reconcileErr error) time.Duration {
+	if reconcileErr != nil {
+		return retryDuration
+	}
+	if r.nextReconcile.IsZero() {
+		return tickerDuration
+	}
+
+	return min(max(time.Until(r.nextReconcile), retryDuration), tickerDuration)
+}
+
+// deleteOrphanedPolicyServerCertSecret deletes the policy server cert secret
+// when the policy server owning it does not exist anymore, e.g. because the
+// policy server finalizer has been removed by hand. A policy server recreated
+// with the same name is a different owner: the secret is orphaned as well.
+// It returns whether the secret was orphaned.
+func (r *CertReconciler) deleteOrphanedPolicyServerCertSecret(ctx context.Context, serverCertSecret *corev1.Secret) (bool, error) {
+	ownerReference := policyServerOwnerReference(serverCertSecret)
+	if ownerReference == nil {
+		return false, nil
+	}
+
+	policyServer := &policiesv1.PolicyServer{}
+	err := r.Get(ctx, types.NamespacedName{Name: ownerReference.Name}, policyServer)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	if err == nil && policyServer.GetUID() == ownerReference.UID {
+		return false, nil
+	}
+
+	r.Log.Info("Deleting orphaned policy server cert secret", "secret", serverCertSecret.GetName(), "policyServer", ownerReference.Name)
+	if err = r.Delete(ctx, serverCertSecret); err != nil && !apierrors.IsNotFound(err) {
+		return true, err
+	}
+
+	return true, nil
+}
+
+// reconcilePolicyServerCertStatus reports the expiration of the certificate
+// stored in the serverCertSecret in the status of the policy server owning
+// the secret, and schedules a reconciliation when the certificate crosses the
+// expiration warning threshold. Secrets not owned by a policy server are
+// ignored.
+func (r *CertReconciler) reconcilePolicyServerCertStatus(ctx context.Context, serverCertSecret *corev1.Secret) error {
+	ownerReference := policyServerOwnerReference(serverCertSecret)
+	if ownerReference == nil {
+		return nil
+	}
+
+	notAfter, err := certs.CertificateNotAfter(serverCertSecret.Data[constants.ServerCert])
+	if err != nil {
+		return fmt.Errorf("failed to read certificate expiration: %w", err)
+	}
+	if warningAt := notAfter.Add(-r.CertExpirationWarningThreshold); time.Now().Before(warningAt) {
+		r.scheduleReconcile(warningAt)
+	}
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		policyServer := &policiesv1.PolicyServer{}
+		if err = r.Get(ctx, types.NamespacedName{Name: ownerReference.Name}, policyServer); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		original := policyServer.DeepCopy()
+
+		policyServer.Status.CertificateExpiresAt = &metav1.Time{Time: notAfter}
+		setCertificateValidCondition(&policyServer.Status.Conditions, notAfter, r.CertExpirationWarningThreshold)
+
+		return r.Status().Patch(ctx, policyServer, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+	})
+}
+
+// policyServerOwnerReference returns the reference to the policy server
+// owning the object, or nil when the object is not owned by a policy server.
+func policyServerOwnerReference(object metav1.Object) *metav1.OwnerReference {
+	for _, ownerReference := range object.GetOwnerReferences() {
+		if ownerReference.APIVersion == policiesv1.GroupVersion.String() && ownerReference.Kind == "PolicyServer" {
+			return &ownerReference
+		}
+	}
 
 	return nil
 }
+
+// setCertificateValidCondition sets the CertificateValid condition to false
+// when the certificate expires within the threshold, to true otherwise.
+func setCertificateValidCondition(conditions *[]metav1.Condition, notAfter time.Time, threshold time.Duration) {
+	condition := metav1.Condition{
+		Type:    string(policiesv1.PolicyServerCertificateValid),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(policiesv1.CertificateNotExpiring),
+		Message: "the certificate expires at " + notAfter.UTC().Format(time.RFC3339),
+	}
+	if time.Until(notAfter) < threshold {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(policiesv1.CertificateExpiring)
+	}
+
+	apimeta.SetStatusCondition(conditions, condition)
+}
@@ -2,8 +2,10 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
 	"github.com/kubewarden/kubewarden-controller/internal/certs"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
 	. "github.com/onsi/ginkgo/v2"
@@ -11,9 +13,19 @@ import (
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/config"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 )
 
 var _ = Describe("Cert controller", func() {
@@ -463,4 +475,431 @@ var _ = Describe("Cert controller", func() {
 			Expect(expectedCABundle).To(Equal(caBundle))
 		})
 	})
+
+	Context("Policy server certificate status", func() {
+		const policyServerName = "cert-status-test"
+
+		newCertController := func(threshold time.Duration) (*CertReconciler, *corev1.Secret) {
+			testScheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(testScheme)).To(Succeed())
+			Expect(policiesv1.AddToScheme(testScheme)).To(Succeed())
+
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.SetUID("cert-status-test-uid")
+			// The policy servers are deleted right away, without waiting for
+			// the policy server controller to remove the finalizer.
+			policyServer.SetFinalizers(nil)
+
+			caCert, caPrivateKey, err := certs.GenerateCA(time.Now(), time.Now().Add(constants.CACertExpiration))
+			Expect(err).ToNot(HaveOccurred())
+			dnsName := certs.DNSName(policyServer.NameWithPrefix(), deploymentsNamespace)
+			cert, privateKey, err := certs.GenerateCert(caCert, caPrivateKey, time.Now(), time.Now().Add(90*24*time.Hour), dnsName)
+			Expect(err).ToNot(HaveOccurred())
+
+			serverCertSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: deploymentsNamespace,
+					Name:      policyServer.NameWithPrefix(),
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: policiesv1.GroupVersion.String(),
+							Kind:       "PolicyServer",
+							Name:       policyServerName,
+							UID:        policyServer.GetUID(),
+						},
+					},
+				},
+				Data: map[string][]byte{
+					constants.ServerCert:       cert,
+					constants.ServerPrivateKey: privateKey,
+				},
+			}
+
+			certController := &CertReconciler{
+				Client: fake.NewClientBuilder().
+					WithScheme(testScheme).
+					WithObjects(policyServer).
+					WithStatusSubresource(policyServer).
+					Build(),
+				DeploymentsNamespace:           deploymentsNamespace,
+				CertExpirationWarningThreshold: threshold,
+			}
+
+			return certController, serverCertSecret
+		}
+
+		getPolicyServer := func(certController *CertReconciler) *policiesv1.PolicyServer {
+			policyServer := &policiesv1.PolicyServer{}
+			Expect(certController.Get(ctx, types.NamespacedName{Name: policyServerName}, policyServer)).To(Succeed())
+			return policyServer
+		}
+
+		It("should report the certificate expiration", func() {
+			certController, serverCertSecret := newCertController(30 * 24 * time.Hour)
+			Expect(certController.reconcilePolicyServerCertStatus(ctx, serverCertSecret)).To(Succeed())
+
+			notAfter, err := certs.CertificateNotAfter(serverCertSecret.Data[constants.ServerCert])
+			Expect(err).ToNot(HaveOccurred())
+
+			policyServer := getPolicyServer(certController)
+			Expect(policyServer.Status.CertificateExpiresAt).ToNot(BeNil())
+			Expect(policyServer.Status.CertificateExpiresAt.Time).To(BeTemporally("~", notAfter, time.Second))
+			Expect(apimeta.IsStatusConditionTrue(policyServer.Status.Conditions, string(policiesv1.PolicyServerCertificateValid))).To(BeTrue())
+		})
+
+		It("should set the CertificateValid condition to false when the certificate expires within the threshold", func() {
+			certController, serverCertSecret := newCertController(120 * 24 * time.Hour)
+			Expect(certController.reconcilePolicyServerCertStatus(ctx, serverCertSecret)).To(Succeed())
+
+			policyServer := getPolicyServer(certController)
+			condition := apimeta.FindStatusCondition(policyServer.Status.Conditions, string(policiesv1.PolicyServerCertificateValid))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(policiesv1.CertificateExpiring)))
+		})
+
+		It("should schedule the next reconciliation when the certificate crosses the threshold", func() {
+			certController, serverCertSecret := newCertController(90*24*time.Hour - time.Hour)
+			Expect(certController.reconcilePolicyServerCertStatus(ctx, serverCertSecret)).To(Succeed())
+
+			Expect(certController.nextReconcileDelay(nil)).To(BeNumerically("~", time.Hour, time.Minute))
+		})
+
+		It("should ignore the secrets not owned by a policy server", func() {
+			certController, serverCertSecret := newCertController(120 * 24 * time.Hour)
+			serverCertSecret.OwnerReferences = nil
+			Expect(certController.reconcilePolicyServerCertStatus(ctx, serverCertSecret)).To(Succeed())
+
+			policyServer := getPolicyServer(certController)
+			Expect(policyServer.Status.CertificateExpiresAt).To(BeNil())
+			Expect(policyServer.Status.Conditions).To(BeEmpty())
+		})
+
+		It("should delete the secrets owned by a policy server that does not exist anymore", func() {
+			certController, serverCertSecret := newCertController(30 * 24 * time.Hour)
+			Expect(certController.Create(ctx, serverCertSecret)).To(Succeed())
+			Expect(certController.Delete(ctx, getPolicyServer(certController))).To(Succeed())
+
+			orphaned, err := certController.deleteOrphanedPolicyServerCertSecret(ctx, serverCertSecret)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(orphaned).To(BeTrue())
+
+			err = certController.Get(ctx, client.ObjectKeyFromObject(serverCertSecret), &corev1.Secret{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should delete the secrets owned by a deleted policy server recreated with the same name", func() {
+			certController, serverCertSecret := newCertController(30 * 24 * time.Hour)
+			Expect(certController.Create(ctx, serverCertSecret)).To(Succeed())
+			Expect(certController.Delete(ctx, getPolicyServer(certController))).To(Succeed())
+			recreatedPolicyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			recreatedPolicyServer.SetUID("cert-status-test-recreated-uid")
+			recreatedPolicyServer.SetFinalizers(nil)
+			Expect(certController.Create(ctx, recreatedPolicyServer)).To(Succeed())
+
+			orphaned, err := certController.deleteOrphanedPolicyServerCertSecret(ctx, serverCertSecret)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(orphaned).To(BeTrue())
+
+			err = certController.Get(ctx, client.ObjectKeyFromObject(serverCertSecret), &corev1.Secret{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should keep the secrets owned by an existing policy server", func() {
+			certController, serverCertSecret := newCertController(30 * 24 * time.Hour)
+			Expect(certController.Create(ctx, serverCertSecret)).To(Succeed())
+
+			orphaned, err := certController.deleteOrphanedPolicyServerCertSecret(ctx, serverCertSecret)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(orphaned).To(BeFalse())
+
+			Expect(certController.Get(ctx, client.ObjectKeyFromObject(serverCertSecret), &corev1.Secret{})).To(Succeed())
+		})
+	})
+
+	Context("Renewal window", func() {
+		const (
+			webhookServerServiceName    = "renewal-window-test-webhook-service"
+			caRootSecretName            = "renewal-window-test-ca-root"
+			webhookServerCertSecretName = "renewal-window-test-webhook-server-cert"
+		)
+
+		newCertController := func(renewalWindow, webhookServerCertValidity time.Duration) *CertReconciler {
+			caCert, caPrivateKey, err := certs.GenerateCA(time.Now(), time.Now().Add(constants.CACertExpiration))
+			Expect(err).ToNot(HaveOccurred())
+			dnsName := certs.DNSName(webhookServerServiceName, deploymentsNamespace)
+			webhookServerCert, webhookServerPrivateKey, err := certs.GenerateCert(caCert, caPrivateKey, time.Now(), time.Now().Add(webhookServerCertValidity), dnsName)
+			Expect(err).ToNot(HaveOccurred())
+
+			return &CertReconciler{
+				Client: fake.NewClientBuilder().WithObjects(
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Namespace: deploymentsNamespace, Name: caRootSecretName},
+						Data: map[string][]byte{
+							constants.CARootCert:       caCert,
+							constants.CARootPrivateKey: caPrivateKey,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Namespace: deploymentsNamespace, Name: webhookServerCertSecretName},
+						Data: map[string][]byte{
+							constants.ServerCert:       webhookServerCert,
+							constants.ServerPrivateKey: webhookServerPrivateKey,
+						},
+					},
+				).Build(),
+				DeploymentsNamespace:        deploymentsNamespace,
+				WebhookServiceName:          webhookServerServiceName,
+				CARootSecretName:            caRootSecretName,
+				WebhookServerCertSecretName: webhookServerCertSecretName,
+				CertRenewalWindow:           renewalWindow,
+			}
+		}
+
+		getWebhookServerCertExpiration := func(certController *CertReconciler) time.Time {
+			webhookServerCertSecret := &corev1.Secret{}
+			Expect(certController.Get(ctx, types.NamespacedName{Name: webhookServerCertSecretName, Namespace: deploymentsNamespace}, webhookServerCertSecret)).To(Succeed())
+			notAfter, err := certs.CertificateNotAfter(webhookServerCertSecret.Data[constants.ServerCert])
+			Expect(err).ToNot(HaveOccurred())
+			return notAfter
+		}
+
+		It("should rotate the certificates entering the configured renewal window", func() {
+			certController := newCertController(100*24*time.Hour, 90*24*time.Hour)
+			Expect(certController.reconcile(ctx)).To(Succeed())
+
+			Expect(getWebhookServerCertExpiration(certController)).To(BeTemporally("~", time.Now().Add(constants.ServerCertExpiration), time.Minute))
+		})
+
+		It("should not rotate the certificates outside of the configured renewal window", func() {
+			certController := newCertController(30*24*time.Hour, 90*24*time.Hour)
+			Expect(certController.reconcile(ctx)).To(Succeed())
+
+			Expect(getWebhookServerCertExpiration(certController)).To(BeTemporally("~", time.Now().Add(90*24*time.Hour), time.Minute))
+		})
+
+		It("should generate the server certificates with the configured duration", func() {
+			certController := newCertController(30*24*time.Hour, 20*24*time.Hour)
+			certController.ServerCertDuration = 90 * 24 * time.Hour
+			Expect(certController.reconcile(ctx)).To(Succeed())
+
+			Expect(getWebhookServerCertExpiration(certController)).To(BeTemporally("~", time.Now().Add(90*24*time.Hour), time.Minute))
+		})
+
+		It("should schedule the next reconciliation when the earliest certificate enters the renewal window", func() {
+			certController := newCertController(90*24*time.Hour, 90*24*time.Hour+time.Hour)
+			Expect(certController.reconcile(ctx)).To(Succeed())
+
+			Expect(certController.nextReconcileDelay(nil)).To(BeNumerically("~", time.Hour, time.Minute))
+		})
+
+		It("should reconcile at least every tickerDuration", func() {
+			certController := newCertController(30*24*time.Hour, 90*24*time.Hour)
+			Expect(certController.reconcile(ctx)).To(Succeed())
+
+			Expect(certController.nextReconcileDelay(nil)).To(Equal(tickerDuration))
+		})
+
+		It("should retry after retryDuration when the reconciliation fails", func() {
+			certController := newCertController(30*24*time.Hour, 90*24*time.Hour)
+			Expect(certController.reconcile(ctx)).To(Succeed())
+
+			Expect(certController.nextReconcileDelay(errors.New("failed to get CA cert secret"))).To(Equal(retryDuration))
+		})
+	})
+
+	Context("Key algorithm", func() {
+		const (
+			webhookServerServiceName    = "key-algorithm-test-webhook-service"
+			caRootSecretName            = "key-algorithm-test-ca-root"
+			webhookServerCertSecretName = "key-algorithm-test-webhook-server-cert"
+		)
+
+		var certController *CertReconciler
+
+		getKeyAlgorithm := func(secretName, entry string) certs.KeyAlgorithm {
+			secret := &corev1.Secret{}
+			Expect(certController.Get(ctx, types.NamespacedName{Name: secretName, Namespace: deploymentsNamespace}, secret)).To(Succeed())
+			keyAlgorithm, err := certs.CertificateKeyAlgorithm(secret.Data[entry])
+			Expect(err).ToNot(HaveOccurred())
+			return keyAlgorithm
+		}
+
+		BeforeEach(func() {
+			caCert, caPrivateKey, err := certs.GenerateCA(time.Now(), time.Now().Add(constants.CACertExpiration))
+			Expect(err).ToNot(HaveOccurred())
+			dnsName := certs.DNSName(webhookServerServiceName, deploymentsNamespace)
+			webhookServerCert, webhookServerPrivateKey, err := certs.GenerateCert(caCert, caPrivateKey, time.Now(), time.Now().Add(constants.ServerCertExpiration), dnsName)
+			Expect(err).ToNot(HaveOccurred())
+
+			certController = &CertReconciler{
+				Client: fake.NewClientBuilder().WithObjects(
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Namespace: deploymentsNamespace, Name: caRootSecretName},
+						Data: map[string][]byte{
+							constants.CARootCert:       caCert,
+							constants.CARootPrivateKey: caPrivateKey,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Namespace: deploymentsNamespace, Name: webhookServerCertSecretName},
+						Data: map[string][]byte{
+							constants.ServerCert:       webhookServerCert,
+							constants.ServerPrivateKey: webhookServerPrivateKey,
+						},
+					},
+				).Build(),
+				DeploymentsNamespace:        deploymentsNamespace,
+				WebhookServiceName:          webhookServerServiceName,
+				CARootSecretName:            caRootSecretName,
+				WebhookServerCertSecretName: webhookServerCertSecretName,
+			}
+		})
+
+		It("should keep the certificates using the configured key algorithm", func() {
+			Expect(certController.reconcile(ctx)).To(Succeed())
+
+			Expect(getKeyAlgorithm(caRootSecretName, constants.CARootCert)).To(Equal(certs.KeyAlgorithmECDSAP256))
+			Expect(getKeyAlgorithm(webhookServerCertSecretName, constants.ServerCert)).To(Equal(certs.KeyAlgorithmECDSAP256))
+		})
+
+		It("should rotate the CA root and the server certificates to the configured key algorithm", func() {
+			certController.CertKeyAlgorithm = certs.KeyAlgorithmRSA
+			Expect(certController.reconcile(ctx)).To(Succeed())
+
+			Expect(getKeyAlgorithm(caRootSecretName, constants.CARootCert)).To(Equal(certs.KeyAlgorithmRSA))
+			Expect(getKeyAlgorithm(caRootSecretName, constants.OldCARootCert)).To(Equal(certs.KeyAlgorithmECDSAP256))
+			Expect(getKeyAlgorithm(webhookServerCertSecretName, constants.ServerCert)).To(Equal(certs.KeyAlgorithmRSA))
+
+			By("verifying the rotated server certificate against the rotated CA root")
+			caRootSecret := &corev1.Secret{}
+			Expect(certController.Get(ctx, types.NamespacedName{Name: caRootSecretName, Namespace: deploymentsNamespace}, caRootSecret)).To(Succeed())
+			pool, err := certs.NewCertPool(caRootSecret.Data[constants.CARootCert])
+			Expect(err).ToNot(HaveOccurred())
+			webhookServerCertSecret := &corev1.Secret{}
+			Expect(certController.Get(ctx, types.NamespacedName{Name: webhookServerCertSecretName, Namespace: deploymentsNamespace}, webhookServerCertSecret)).To(Succeed())
+			dnsName := certs.DNSName(webhookServerServiceName, deploymentsNamespace)
+			Expect(certs.VerifyCert(webhookServerCertSecret.Data[constants.ServerCert], webhookServerCertSecret.Data[constants.ServerPrivateKey], pool, dnsName, time.Now())).To(Succeed())
+		})
+	})
+
+	Context("Externally managed policy server certificates", func() {
+		const (
+			caRootSecretName            = "external-test-ca-root"
+			webhookServerServiceName    = "external-test-webhook-service"
+			webhookServerCertSecretName = "external-test-webhook-server-cert"
+			externalCertSecretName      = "external-test-cert"
+		)
+
+		var caCert []byte
+
+		newWebhookConfiguration := func(policyServerName string) *admissionregistrationv1.ValidatingWebhookConfiguration {
+			return &admissionregistrationv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "external-test-" + policyServerName,
+					Labels: map[string]string{constants.PartOfLabelKey: constants.PartOfLabelValue},
+				},
+				Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+					Name: "external-test-" + policyServerName + ".kubewarden.admission",
+					ClientConfig: admissionregistrationv1.WebhookClientConfig{
+						Service:  &admissionregistrationv1.ServiceReference{Namespace: deploymentsNamespace, Name: "policy-server-" + policyServerName},
+						CABundle: []byte("stale"),
+					},
+				}},
+			}
+		}
+
+		newCertController := func() *CertReconciler {
+			testScheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(testScheme)).To(Succeed())
+			Expect(policiesv1.AddToScheme(testScheme)).To(Succeed())
+
+			var caPrivateKey []byte
+			var err error
+			caCert, caPrivateKey, err = certs.GenerateCA(time.Now(), time.Now().Add(constants.CACertExpiration))
+			Expect(err).ToNot(HaveOccurred())
+			webhookServerCert, webhookServerPrivateKey, err := certs.GenerateCert(caCert, caPrivateKey, time.Now(), time.Now().Add(constants.ServerCertExpiration), certs.DNSName(webhookServerServiceName, deploymentsNamespace))
+			Expect(err).ToNot(HaveOccurred())
+
+			externalCACert, externalCAPrivateKey, err := certs.GenerateCA(time.Now(), time.Now().Add(constants.CACertExpiration))
+			Expect(err).ToNot(HaveOccurred())
+			externalCert, externalPrivateKey, err := certs.GenerateCert(externalCACert, externalCAPrivateKey, time.Now(), time.Now().Add(constants.ServerCertExpiration), certs.DNSName("policy-server-external", deploymentsNamespace))
+			Expect(err).ToNot(HaveOccurred())
+			externalCertSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: deploymentsNamespace, Name: externalCertSecretName},
+				Data: map[string][]byte{
+					constants.ServerCert:       externalCert,
+					constants.ServerPrivateKey: externalPrivateKey,
+					constants.CARootCert:       externalCACert,
+				},
+			}
+
+			externalPolicyServer := policiesv1.NewPolicyServerFactory().WithName("external").Build()
+			externalPolicyServer.Spec.CertificateSecret = externalCertSecretName
+
+			return &CertReconciler{
+				Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Namespace: deploymentsNamespace, Name: caRootSecretName},
+						Data: map[string][]byte{
+							constants.CARootCert:       caCert,
+							constants.CARootPrivateKey: caPrivateKey,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Namespace: deploymentsNamespace, Name: webhookServerCertSecretName},
+						Data: map[string][]byte{
+							constants.ServerCert:       webhookServerCert,
+							constants.ServerPrivateKey: webhookServerPrivateKey,
+						},
+					},
+					externalCertSecret,
+					externalPolicyServer,
+					policiesv1.NewPolicyServerFactory().WithName("internal").Build(),
+					newWebhookConfiguration("external"),
+					newWebhookConfiguration("internal"),
+				).Build(),
+				DeploymentsNamespace:        deploymentsNamespace,
+				WebhookServiceName:          webhookServerServiceName,
+				CARootSecretName:            caRootSecretName,
+				WebhookServerCertSecretName: webhookServerCertSecretName,
+			}
+		}
+
+		getCABundle := func(certController *CertReconciler, policyServerName string) []byte {
+			webhookConfiguration := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+			Expect(certController.Get(ctx, types.NamespacedName{Name: "external-test-" + policyServerName}, webhookConfiguration)).To(Succeed())
+			return webhookConfiguration.Webhooks[0].ClientConfig.CABundle
+		}
+
+		It("should leave the CA bundle of the externally managed certificates to the policy reconcilers", func() {
+			certController := newCertController()
+			Expect(certController.reconcileWebhookConfigurations(ctx, caCert)).To(Succeed())
+
+			Expect(getCABundle(certController, "external")).To(Equal([]byte("stale")))
+			Expect(getCABundle(certController, "internal")).To(Equal(caCert))
+		})
+	})
+
+	Context("Concurrency", func() {
+		It("should reconcile the certificates one at a time when the controllers run concurrent reconciliations", func() {
+			mgr, err := ctrl.NewManager(&rest.Config{Host: "https://127.0.0.1:6443"}, ctrl.Options{
+				Scheme:  clientgoscheme.Scheme,
+				Metrics: metricsserver.Options{BindAddress: "0"},
+				Controller: ctrlconfig.Controller{
+					MaxConcurrentReconciles: 4,
+					SkipNameValidation:      ptr.To(true),
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			certController := &CertReconciler{
+				Client:               mgr.GetClient(),
+				DeploymentsNamespace: deploymentsNamespace,
+				CARootSecretName:     constants.CARootSecretName,
+			}
+			Expect(certController.SetupWithManager(mgr)).To(Succeed())
+			Expect(certController.controllerOptions().MaxConcurrentReconciles).To(Equal(1))
+		})
+	})
 })
@@ -25,6 +25,7 @@ import (
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
 
 	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
@@ -326,4 +327,63 @@ var _ = Describe("ClusterAdmissionPolicy controller", Label("real-cluster"), fun
 			)
 		})
 	})
+
+	When("creating ClusterAdmissionPolicies bound to a PolicyServer with a default backgroundAudit", Ordered, func() {
+		var policyServer *policiesv1.PolicyServer
+		var inheritingPolicyName string
+		var auditedPolicyName string
+
+		BeforeAll(func() {
+			policyServer = policiesv1.NewPolicyServerFactory().WithName(newName("policy-server")).Build()
+			policyServer.Spec.DefaultBackgroundAudit = ptr.To(false)
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			inheritingPolicyName = newName("inheriting-policy")
+			Expect(k8sClient.Create(ctx, policiesv1.NewClusterAdmissionPolicyFactory().
+				WithName(inheritingPolicyName).
+				WithPolicyServer(policyServer.GetName()).
+				Build())).To(Succeed())
+
+			auditedPolicyName = newName("audited-policy")
+			auditedPolicy := policiesv1.NewClusterAdmissionPolicyFactory().
+				WithName(auditedPolicyName).
+				WithPolicyServer(policyServer.GetName()).
+				Build()
+			auditedPolicy.Spec.BackgroundAudit = ptr.To(true)
+			Expect(k8sClient.Create(ctx, auditedPolicy)).To(Succeed())
+		})
+
+		It("should report the policy server default for the policies not setting backgroundAudit", func() {
+			Eventually(func() (*policiesv1.ClusterAdmissionPolicy, error) {
+				return getTestClusterAdmissionPolicy(ctx, inheritingPolicyName)
+			}, timeout, pollInterval).Should(
+				HaveField("Status.BackgroundAudit", HaveValue(BeFalse())),
+			)
+		})
+
+		It("should report the backgroundAudit set by the policy", func() {
+			Eventually(func() (*policiesv1.ClusterAdmissionPolicy, error) {
+				return getTestClusterAdmissionPolicy(ctx, auditedPolicyName)
+			}, timeout, pollInterval).Should(
+				HaveField("Status.BackgroundAudit", HaveValue(BeTrue())),
+			)
+		})
+
+		It("should follow the changes of the policy server default", func() {
+			Eventually(func() error {
+				storedPolicyServer, err := getTestPolicyServer(ctx, policyServer.GetName())
+				if err != nil {
+					return err
+				}
+				storedPolicyServer.Spec.DefaultBackgroundAudit = ptr.To(true)
+				return k8sClient.Update(ctx, storedPolicyServer)
+			}, timeout, pollInterval).Should(Succeed())
+
+			Eventually(func() (*policiesv1.ClusterAdmissionPolicy, error) {
+				return getTestClusterAdmissionPolicy(ctx, inheritingPolicyName)
+			}, timeout, pollInterval).Should(
+				HaveField("Status.BackgroundAudit", HaveValue(BeTrue())),
+			)
+		})
+	})
 })
@@ -20,15 +20,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
@@ -55,7 +61,17 @@ type ClusterAdmissionPolicyGroupReconciler struct {
 	Scheme                                     *runtime.Scheme
 	DeploymentsNamespace                       string
 	FeatureGateAdmissionWebhookMatchConditions bool
-	policySubReconciler                        *policySubReconciler
+	// PolicyServerRestartGracePeriod is the time during which the status of
+	// an active policy is held after its policy server restarts.
+	PolicyServerRestartGracePeriod time.Duration
+	// MaxConcurrentReconciles is the maximum number of policies reconciled
+	// concurrently. The controller-runtime default of one is used when it
+	// is not set.
+	MaxConcurrentReconciles int
+	// Tracer starts the spans of the reconciliations. The spans are not
+	// recorded when it is nil.
+	Tracer              trace.Tracer
+	policySubReconciler *policySubReconciler
 }
 
 // Reconcile reconciles admission policies.
@@ -78,6 +94,9 @@ func (r *ClusterAdmissionPolicyGroupReconciler) SetupWithManager(mgr ctrl.Manage
 		r.Log,
 		r.DeploymentsNamespace,
 		r.FeatureGateAdmissionWebhookMatchConditions,
+		r.PolicyServerRestartGracePeriod,
+		clock.RealClock{},
+		r.Tracer,
 	}
 
 	err := ctrl.NewControllerManagedBy(mgr).
@@ -86,10 +105,24 @@ func (r *ClusterAdmissionPolicyGroupReconciler) SetupWithManager(mgr ctrl.Manage
 			&corev1.Pod{},
 			handler.EnqueueRequestsFromMapFunc(r.findClusterAdmissionPoliciesForPod),
 		).
+		// Watch the policy servers to apply the changes of their policy
+		// defaults to the bound policies
+		Watches(
+			&policiesv1.PolicyServer{},
+			handler.EnqueueRequestsFromMapFunc(r.findClusterAdmissionPoliciesForPolicyServer),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		// Watch the externally managed certificates of the policy servers to
+		// refresh the CA bundle of the webhooks
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.findClusterAdmissionPoliciesForSecret),
+		).
 		Watches(
 			&admissionregistrationv1.ValidatingWebhookConfiguration{},
 			handler.EnqueueRequestsFromMapFunc(r.findClusterAdmissionPolicyForWebhookConfiguration),
 		).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 	if err != nil {
 		return errors.Join(errors.New("failed enrolling controller with manager"), err)
@@ -101,6 +134,14 @@ func (r *ClusterAdmissionPolicyGroupReconciler) findClusterAdmissionPoliciesForP
 	return findClusterPoliciesForPod(ctx, r.Client, object)
 }
 
+func (r *ClusterAdmissionPolicyGroupReconciler) findClusterAdmissionPoliciesForPolicyServer(ctx context.Context, object client.Object) []reconcile.Request {
+	return findPoliciesForPolicyServer(ctx, r.Client, &policiesv1.ClusterAdmissionPolicyGroupList{}, object)
+}
+
+func (r *ClusterAdmissionPolicyGroupReconciler) findClusterAdmissionPoliciesForSecret(ctx context.Context, object client.Object) []reconcile.Request {
+	return findPoliciesForCertificateSecret(ctx, r.Client, r.DeploymentsNamespace, &policiesv1.ClusterAdmissionPolicyGroupList{}, object)
+}
+
 func (r *ClusterAdmissionPolicyGroupReconciler) findClusterAdmissionPolicyForWebhookConfiguration(_ context.Context, webhookConfiguration client.Object) []reconcile.Request {
 	if !hasKubewardenLabel(webhookConfiguration.GetLabels()) {
 		return []reconcile.Request{}
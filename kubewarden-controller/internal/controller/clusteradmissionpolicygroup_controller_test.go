@@ -18,6 +18,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -25,6 +26,15 @@ import (
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
@@ -213,4 +223,273 @@ var _ = Describe("ClusterAdmissionPolicyGroup controller", Label("real-cluster")
 			)
 		})
 	})
+
+	When("creating a ClusterAdmissionPolicyGroup with the validatingAdmissionPolicy backend", Ordered, func() {
+		var policyName string
+		var policy *policiesv1.ClusterAdmissionPolicyGroup
+
+		BeforeAll(func() {
+			policyName = newName("vap-policy")
+			policy = policiesv1.NewClusterAdmissionPolicyGroupFactory().
+				WithName(policyName).
+				WithMembers(celPolicyGroupMembers()).
+				WithExpression("replicas() || !has_owner()").
+				WithBackend(policiesv1.PolicyGroupBackendValidatingAdmissionPolicy).
+				Build()
+			Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+		})
+
+		It("should set the ClusterAdmissionPolicyGroup to active with the validatingAdmissionPolicy backend", func() {
+			Eventually(func() (*policiesv1.ClusterAdmissionPolicyGroup, error) {
+				return getTestClusterAdmissionPolicyGroup(ctx, policyName)
+			}, timeout, pollInterval).Should(And(
+				HaveField("Status.PolicyStatus", Equal(policiesv1.PolicyStatusActive)),
+				HaveField("Status.Backend", Equal(policiesv1.PolicyGroupBackendValidatingAdmissionPolicy)),
+			))
+		})
+
+		It("should create the ValidatingAdmissionPolicy and its binding", func() {
+			Eventually(func() error {
+				validatingAdmissionPolicy := &admissionregistrationv1.ValidatingAdmissionPolicy{}
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: policy.GetUniqueName()}, validatingAdmissionPolicy); err != nil {
+					return err
+				}
+				Expect(validatingAdmissionPolicy.Spec.Variables).To(HaveLen(2))
+				Expect(validatingAdmissionPolicy.Spec.Validations).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+					"Expression": Equal("variables.replicas || !variables.has_owner"),
+				})))
+
+				validatingAdmissionPolicyBinding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{}
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: policy.GetUniqueName()}, validatingAdmissionPolicyBinding); err != nil {
+					return err
+				}
+				Expect(validatingAdmissionPolicyBinding.Spec.PolicyName).To(Equal(policy.GetUniqueName()))
+				Expect(validatingAdmissionPolicyBinding.Spec.ValidationActions).To(ConsistOf(admissionregistrationv1.Deny))
+
+				return nil
+			}, timeout, pollInterval).Should(Succeed())
+		})
+
+		It("should not create the ValidatingWebhookConfiguration", func() {
+			Consistently(func() error {
+				_, err := getTestValidatingWebhookConfiguration(ctx, policy.GetUniqueName())
+				return err
+			}, consistencyTimeout, pollInterval).Should(Satisfy(apierrors.IsNotFound))
+		})
+	})
+
+	When("creating a ClusterAdmissionPolicyGroup with the validatingAdmissionPolicy backend and non CEL members", func() {
+		It("should fall back to the webhook backend", func() {
+			policyName := newName("vap-fallback-policy")
+			Expect(
+				k8sClient.Create(ctx, policiesv1.NewClusterAdmissionPolicyGroupFactory().
+					WithName(policyName).
+					WithBackend(policiesv1.PolicyGroupBackendValidatingAdmissionPolicy).
+					Build()),
+			).To(Succeed())
+
+			Eventually(func() (*policiesv1.ClusterAdmissionPolicyGroup, error) {
+				return getTestClusterAdmissionPolicyGroup(ctx, policyName)
+			}, timeout, pollInterval).Should(And(
+				HaveField("Status.Backend", Equal(policiesv1.PolicyGroupBackendWebhook)),
+				HaveField("Status.Conditions", ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(string(policiesv1.PolicyGroupBackendFallback)),
+					"Status": Equal(metav1.ConditionTrue),
+					"Reason": Equal("PolicyGroupNotCompilable"),
+				}))),
+			))
+		})
+	})
+})
+
+func celPolicyGroupMembers() policiesv1.PolicyGroupMembersWithContext {
+	return policiesv1.PolicyGroupMembersWithContext{
+		"replicas": {
+			PolicyGroupMember: policiesv1.PolicyGroupMember{
+				Module: "registry://ghcr.io/kubewarden/policies/cel-policy:v1.0.0",
+				Settings: runtime.RawExtension{
+					Raw: []byte(`{"validations":[{"expression":"object.spec.replicas >= 2","message":"at least 2 replicas"}]}`),
+				},
+			},
+		},
+		"has_owner": {
+			PolicyGroupMember: policiesv1.PolicyGroupMember{
+				Module: "ghcr.io/kubewarden/policies/cel-policy@sha256:1b6f8f4a2f4b1c6d8e9a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e",
+				Settings: runtime.RawExtension{
+					Raw: []byte(`{"validations":[{"expression":"has(object.metadata.ownerReferences)"}]}`),
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("Policy group compilation into a ValidatingAdmissionPolicy", func() {
+	It("should compile the CEL policy members and the group expression", func() {
+		policyGroup := policiesv1.NewClusterAdmissionPolicyGroupFactory().
+			WithMembers(celPolicyGroupMembers()).
+			WithExpression("!(replicas() && has_owner()) || replicas() != has_owner()").
+			Build()
+
+		compiled, err := compilePolicyGroup(policyGroup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(compiled.variables).To(Equal([]admissionregistrationv1.Variable{
+			{Name: "has_owner", Expression: "(has(object.metadata.ownerReferences))"},
+			{Name: "replicas", Expression: "(object.spec.replicas >= 2)"},
+		}))
+		Expect(compiled.expression).To(Equal("!(variables.replicas && variables.has_owner) || variables.replicas != variables.has_owner"))
+	})
+
+	It("should join the validations of a member", func() {
+		expression, err := compilePolicyGroupMember(policiesv1.PolicyGroupMemberWithContext{
+			PolicyGroupMember: policiesv1.PolicyGroupMember{
+				Module: "ghcr.io/kubewarden/policies/cel-policy:latest",
+				Settings: runtime.RawExtension{
+					Raw: []byte(`{"validations":[{"expression":"true"},{"expression":"object.metadata.name != 'foo'"}]}`),
+				},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expression).To(Equal("(true) && (object.metadata.name != 'foo')"))
+	})
+
+	DescribeTable("should reject the members that cannot be compiled",
+		func(member policiesv1.PolicyGroupMemberWithContext, expectedError string) {
+			_, err := compilePolicyGroupMember(member)
+			Expect(err).To(MatchError(ContainSubstring(expectedError)))
+		},
+		Entry("not a CEL policy", policiesv1.PolicyGroupMemberWithContext{
+			PolicyGroupMember: policiesv1.PolicyGroupMember{
+				Module: "registry://ghcr.io/kubewarden/tests/pod-privileged:v0.2.5",
+			},
+		}, "is not the CEL policy"),
+		Entry("context-aware policy", policiesv1.PolicyGroupMemberWithContext{
+			PolicyGroupMember: policiesv1.PolicyGroupMember{
+				Module: "ghcr.io/kubewarden/policies/cel-policy:v1.0.0",
+			},
+			ContextAwareResources: []policiesv1.ContextAwareResource{{APIVersion: "v1", Kind: "Pod"}},
+		}, "context-aware policies cannot be evaluated natively"),
+		Entry("CEL policy with variables", policiesv1.PolicyGroupMemberWithContext{
+			PolicyGroupMember: policiesv1.PolicyGroupMember{
+				Module: "ghcr.io/kubewarden/policies/cel-policy:v1.0.0",
+				Settings: runtime.RawExtension{
+					Raw: []byte(`{"variables":[{"name":"foo","expression":"true"}],"validations":[{"expression":"variables.foo"}]}`),
+				},
+			},
+		}, `settings field "variables" is not supported`),
+		Entry("CEL policy without validations", policiesv1.PolicyGroupMemberWithContext{
+			PolicyGroupMember: policiesv1.PolicyGroupMember{
+				Module: "ghcr.io/kubewarden/policies/cel-policy:v1.0.0",
+			},
+		}, "no validations defined"),
+	)
+})
+
+var _ = Describe("Policy group validatingAdmissionPolicy backend", func() {
+	ctx := context.Background()
+
+	var (
+		policyGroup *policiesv1.ClusterAdmissionPolicyGroup
+		webhook     *admissionregistrationv1.ValidatingWebhookConfiguration
+	)
+
+	BeforeEach(func() {
+		policyGroup = policiesv1.NewClusterAdmissionPolicyGroupFactory().
+			WithMembers(celPolicyGroupMembers()).
+			WithExpression("replicas() || !has_owner()").
+			WithBackend(policiesv1.PolicyGroupBackendValidatingAdmissionPolicy).
+			Build()
+		webhook = &admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: policyGroup.GetUniqueName()},
+		}
+	})
+
+	newSubReconciler := func(funcs interceptor.Funcs) *policySubReconciler {
+		return &policySubReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(newFakeClientTestScheme()).
+				WithObjects(policyGroup, webhook).
+				WithStatusSubresource(policyGroup).
+				WithInterceptorFuncs(funcs).
+				Build(),
+			deploymentsNamespace: deploymentsNamespace,
+		}
+	}
+
+	isValidatingAdmissionPolicy := func(obj client.Object) bool {
+		switch obj.(type) {
+		case *admissionregistrationv1.ValidatingAdmissionPolicy, *admissionregistrationv1.ValidatingAdmissionPolicyBinding:
+			return true
+		default:
+			return false
+		}
+	}
+
+	It("should delete the webhook once the ValidatingAdmissionPolicy and its binding exist", func() {
+		subReconciler := newSubReconciler(interceptor.Funcs{})
+
+		_, enforced, err := subReconciler.reconcilePolicyGroupBackend(ctx, policyGroup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(enforced).To(BeTrue())
+
+		Expect(subReconciler.Get(ctx, client.ObjectKeyFromObject(policyGroup), &admissionregistrationv1.ValidatingAdmissionPolicy{})).To(Succeed())
+		Expect(subReconciler.Get(ctx, client.ObjectKeyFromObject(policyGroup), &admissionregistrationv1.ValidatingAdmissionPolicyBinding{})).To(Succeed())
+		Expect(apierrors.IsNotFound(subReconciler.Get(ctx, client.ObjectKeyFromObject(webhook), webhook))).To(BeTrue())
+		Expect(policyGroup.Status.Backend).To(Equal(policiesv1.PolicyGroupBackendValidatingAdmissionPolicy))
+		Expect(policyGroup.Status.PolicyStatus).To(Equal(policiesv1.PolicyStatusActive))
+	})
+
+	It("should keep the webhook when the ValidatingAdmissionPolicy cannot be created", func() {
+		subReconciler := newSubReconciler(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if isValidatingAdmissionPolicy(obj) {
+					return errors.New("create failed")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		})
+
+		_, enforced, err := subReconciler.reconcilePolicyGroupBackend(ctx, policyGroup)
+		Expect(err).To(MatchError(ContainSubstring("create failed")))
+		Expect(enforced).To(BeTrue())
+
+		Expect(subReconciler.Get(ctx, client.ObjectKeyFromObject(webhook), webhook)).To(Succeed())
+	})
+
+	It("should fall back to the webhook backend when the ValidatingAdmissionPolicy API is not served", func() {
+		noMatch := func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if isValidatingAdmissionPolicy(obj) {
+				return &apimeta.NoKindMatchError{GroupKind: schema.GroupKind{Group: admissionregistrationv1.GroupName, Kind: "ValidatingAdmissionPolicy"}}
+			}
+			return c.Get(ctx, key, obj, opts...)
+		}
+		subReconciler := newSubReconciler(interceptor.Funcs{Get: noMatch})
+
+		_, enforced, err := subReconciler.reconcilePolicyGroupBackend(ctx, policyGroup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(enforced).To(BeFalse())
+
+		Expect(policyGroup.Status.Backend).To(Equal(policiesv1.PolicyGroupBackendWebhook))
+		Expect(apimeta.FindStatusCondition(policyGroup.Status.Conditions, string(policiesv1.PolicyGroupBackendFallback))).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionTrue),
+			"Reason": Equal("ValidatingAdmissionPolicyNotServed"),
+		})))
+	})
+
+	It("should not create the ValidatingAdmissionPolicy of a paused policy group", func() {
+		policyGroup.SetAnnotations(map[string]string{constants.PausedAnnotation: "true"})
+		subReconciler := newSubReconciler(interceptor.Funcs{})
+		reconciler := &ClusterAdmissionPolicyGroupReconciler{
+			Client:              subReconciler.Client,
+			policySubReconciler: subReconciler,
+		}
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(policyGroup)})
+		Expect(err).ToNot(HaveOccurred())
+
+		storedPolicyGroup := &policiesv1.ClusterAdmissionPolicyGroup{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(policyGroup), storedPolicyGroup)).To(Succeed())
+		Expect(apimeta.IsStatusConditionTrue(storedPolicyGroup.Status.Conditions, string(policiesv1.PolicyReconciliationPaused))).To(BeTrue())
+		err = reconciler.Get(ctx, client.ObjectKeyFromObject(policyGroup), &admissionregistrationv1.ValidatingAdmissionPolicy{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
 })
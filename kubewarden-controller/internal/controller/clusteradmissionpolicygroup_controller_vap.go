@@ -0,0 +1,366 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/google/cel-go/cel"
+	celast "github.com/google/cel-go/common/ast"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingadmissionpolicies,verbs=create;delete;get;list;patch;update;watch
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingadmissionpolicybindings,verbs=create;delete;get;list;patch;update;watch
+
+// celPolicyRepository is the repository of the Kubewarden CEL policy. Its
+// validations use the same CEL environment of the Kubernetes
+// ValidatingAdmissionPolicies.
+const celPolicyRepository = "ghcr.io/kubewarden/policies/cel-policy"
+
+// celPolicyValidation is a validation of the Kubewarden CEL policy settings.
+type celPolicyValidation struct {
+	Expression string `json:"expression"`
+}
+
+// compiledPolicyGroup is a policy group compiled into the variables and the
+// validation expression of a ValidatingAdmissionPolicy.
+type compiledPolicyGroup struct {
+	variables  []admissionregistrationv1.Variable
+	expression string
+}
+
+// compilePolicyGroup compiles the given policy group into the variables and
+// the validation expression of a ValidatingAdmissionPolicy. Each policy member
+// becomes a variable evaluating all its validations, and the calls to the
+// policy members in the group expression are replaced by these variables.
+// An error is returned when the policy group cannot be expressed with CEL only.
+func compilePolicyGroup(policyGroup policiesv1.PolicyGroup) (*compiledPolicyGroup, error) {
+	members := policyGroup.GetPolicyGroupMembersWithContext()
+	memberNames := slices.Sorted(maps.Keys(members))
+
+	variables := make([]admissionregistrationv1.Variable, 0, len(memberNames))
+	for _, name := range memberNames {
+		expression, err := compilePolicyGroupMember(members[name])
+		if err != nil {
+			return nil, fmt.Errorf("policy member %q: %w", name, err)
+		}
+		variables = append(variables, admissionregistrationv1.Variable{
+			Name:       name,
+			Expression: expression,
+		})
+	}
+
+	expression, err := rewritePolicyGroupExpression(policyGroup.GetExpression(), memberNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compiledPolicyGroup{
+		variables:  variables,
+		expression: expression,
+	}, nil
+}
+
+// compilePolicyGroupMember returns the CEL expression evaluating all the
+// validations of the given policy member. Only the Kubewarden CEL policies
+// whose settings only contain validations can be compiled.
+func compilePolicyGroupMember(member policiesv1.PolicyGroupMemberWithContext) (string, error) {
+	if len(member.ContextAwareResources) > 0 {
+		return "", errors.New("context-aware policies cannot be evaluated natively")
+	}
+
+	named, err := reference.ParseNormalizedNamed(strings.TrimPrefix(member.Module, "registry://"))
+	if err != nil || named.Name() != celPolicyRepository {
+		return "", fmt.Errorf("module %q is not the CEL policy", member.Module)
+	}
+
+	settings := map[string]json.RawMessage{}
+	if len(member.Settings.Raw) > 0 {
+		if err = json.Unmarshal(member.Settings.Raw, &settings); err != nil {
+			return "", fmt.Errorf("cannot parse settings: %w", err)
+		}
+	}
+	for key := range settings {
+		if key != "validations" {
+			return "", fmt.Errorf("settings field %q is not supported", key)
+		}
+	}
+
+	var validations []celPolicyValidation
+	if rawValidations, found := settings["validations"]; found {
+		if err = json.Unmarshal(rawValidations, &validations); err != nil {
+			return "", fmt.Errorf("cannot parse validations: %w", err)
+		}
+	}
+	if len(validations) == 0 {
+		return "", errors.New("no validations defined")
+	}
+
+	expressions := make([]string, 0, len(validations))
+	for _, validation := range validations {
+		if validation.Expression == "" {
+			return "", errors.New("validation without expression")
+		}
+		expressions = append(expressions, "("+validation.Expression+")")
+	}
+
+	return strings.Join(expressions, " && "), nil
+}
+
+// rewritePolicyGroupExpression replaces the calls to the policy members in the
+// group expression with the ValidatingAdmissionPolicy variables holding their
+// result.
+func rewritePolicyGroupExpression(expression string, memberNames []string) (string, error) {
+	env, err := cel.NewEnv()
+	if err != nil {
+		return "", fmt.Errorf("cannot create CEL environment: %w", err)
+	}
+	parsed, issues := env.Parse(expression)
+	if issues.Err() != nil {
+		return "", fmt.Errorf("cannot parse expression: %w", issues.Err())
+	}
+
+	root := parsed.NativeRep().Expr()
+	var maxID int64
+	celast.PostOrderVisit(root, celast.NewExprVisitor(func(e celast.Expr) {
+		maxID = max(maxID, e.ID())
+	}))
+
+	factory := celast.NewExprFactory()
+	celast.PostOrderVisit(root, celast.NewExprVisitor(func(e celast.Expr) {
+		if e.Kind() != celast.CallKind {
+			return
+		}
+		call := e.AsCall()
+		if call.IsMemberFunction() || len(call.Args()) > 0 || !slices.Contains(memberNames, call.FunctionName()) {
+			return
+		}
+		maxID += 2
+		e.SetKindCase(factory.NewSelect(maxID-1, factory.NewIdent(maxID, "variables"), call.FunctionName()))
+	}))
+
+	rewritten, err := cel.AstToString(parsed)
+	if err != nil {
+		return "", fmt.Errorf("cannot unparse expression: %w", err)
+	}
+	return rewritten, nil
+}
+
+// reconcilePolicyGroupBackend enforces the policy group with a
+// ValidatingAdmissionPolicy and its binding when it requests the
+// validatingAdmissionPolicy backend. It returns whether the policy group is
+// enforced by the ValidatingAdmissionPolicy, otherwise the reconciliation
+// goes on with the webhook backend. The webhook is deleted only once the
+// ValidatingAdmissionPolicy and its binding exist, so that the policy group is
+// enforced at any time.
+func (r *policySubReconciler) reconcilePolicyGroupBackend(ctx context.Context, policyGroup *policiesv1.ClusterAdmissionPolicyGroup) (ctrl.Result, bool, error) {
+	if policyGroup.Spec.Backend != policiesv1.PolicyGroupBackendValidatingAdmissionPolicy {
+		apimeta.RemoveStatusCondition(&policyGroup.Status.Conditions, string(policiesv1.PolicyGroupBackendFallback))
+		fallBackToWebhookBackend(policyGroup)
+		return ctrl.Result{}, false, nil
+	}
+
+	compiled, err := compilePolicyGroup(policyGroup)
+	if err != nil {
+		setPolicyGroupBackendFallbackCondition(policyGroup, "PolicyGroupNotCompilable",
+			"The policy group cannot be compiled into a ValidatingAdmissionPolicy: "+err.Error())
+		fallBackToWebhookBackend(policyGroup)
+		return ctrl.Result{}, false, nil
+	}
+
+	if err = r.reconcileValidatingAdmissionPolicy(ctx, policyGroup, compiled); err != nil {
+		if apimeta.IsNoMatchError(err) {
+			setPolicyGroupBackendFallbackCondition(policyGroup, "ValidatingAdmissionPolicyNotServed",
+				"The cluster does not serve the ValidatingAdmissionPolicy API")
+			fallBackToWebhookBackend(policyGroup)
+			return ctrl.Result{}, false, nil
+		}
+		return ctrl.Result{}, true, err
+	}
+
+	found, err := r.validatingAdmissionPolicyExists(ctx, policyGroup)
+	if err != nil {
+		return ctrl.Result{}, true, err
+	}
+	if !found {
+		// Keep the webhook until the ValidatingAdmissionPolicy and its
+		// binding can be read back
+		return ctrl.Result{RequeueAfter: constants.TimeToRequeuePolicyReconciliation}, true, nil
+	}
+
+	if err = r.reconcileValidatingWebhookConfigurationDeletion(ctx, policyGroup); err != nil {
+		return ctrl.Result{}, true, err
+	}
+
+	apimeta.RemoveStatusCondition(&policyGroup.Status.Conditions, string(policiesv1.PolicyGroupBackendFallback))
+	policyGroup.Status.Backend = policiesv1.PolicyGroupBackendValidatingAdmissionPolicy
+	policyGroup.SetPolicyModeStatus(policiesv1.PolicyModeStatus(policyGroup.GetPolicyMode()))
+	setPolicyAsActive(policyGroup)
+
+	return ctrl.Result{}, true, nil
+}
+
+// fallBackToWebhookBackend reports the webhook backend in the status of the
+// policy group. A ValidatingAdmissionPolicy created before keeps enforcing
+// the policy group until its webhook is reconciled, see
+// reconcileValidatingAdmissionPolicyDeletion.
+func fallBackToWebhookBackend(policyGroup *policiesv1.ClusterAdmissionPolicyGroup) {
+	if policyGroup.Status.Backend != policiesv1.PolicyGroupBackendValidatingAdmissionPolicy {
+		policyGroup.Status.Backend = policiesv1.PolicyGroupBackendWebhook
+	}
+}
+
+func setPolicyGroupBackendFallbackCondition(policyGroup *policiesv1.ClusterAdmissionPolicyGroup, reason, message string) {
+	apimeta.SetStatusCondition(
+		&policyGroup.Status.Conditions,
+		metav1.Condition{
+			Type:    string(policiesv1.PolicyGroupBackendFallback),
+			Status:  metav1.ConditionTrue,
+			Reason:  reason,
+			Message: message,
+		},
+	)
+}
+
+// validatingAdmissionPolicyExists returns whether both the
+// ValidatingAdmissionPolicy and its binding created for the policy group
+// exist.
+func (r *policySubReconciler) validatingAdmissionPolicyExists(ctx context.Context, policyGroup *policiesv1.ClusterAdmissionPolicyGroup) (bool, error) {
+	objects := []client.Object{
+		&admissionregistrationv1.ValidatingAdmissionPolicy{},
+		&admissionregistrationv1.ValidatingAdmissionPolicyBinding{},
+	}
+	for _, object := range objects {
+		if err := r.Get(ctx, types.NamespacedName{Name: policyGroup.GetUniqueName()}, object); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("cannot retrieve validating admission policy: %w", err)
+		}
+	}
+
+	return true, nil
+}
+
+func (r *policySubReconciler) reconcileValidatingAdmissionPolicy(
+	ctx context.Context,
+	policyGroup *policiesv1.ClusterAdmissionPolicyGroup,
+	compiled *compiledPolicyGroup,
+) error {
+	labels := map[string]string{
+		constants.PartOfLabelKey: constants.PartOfLabelValue,
+	}
+	annotations := map[string]string{
+		constants.WebhookConfigurationPolicyNameAnnotationKey: policyGroup.GetName(),
+	}
+
+	validatingAdmissionPolicy := &admissionregistrationv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: policyGroup.GetUniqueName(),
+		},
+	}
+	_, err := controllerutil.CreateOrPatch(ctx, r.Client, validatingAdmissionPolicy, func() error {
+		validatingAdmissionPolicy.Labels = labels
+		validatingAdmissionPolicy.Annotations = annotations
+
+		resourceRules := make([]admissionregistrationv1.NamedRuleWithOperations, 0, len(policyGroup.GetRules()))
+		for _, rule := range policyGroup.GetRules() {
+			resourceRules = append(resourceRules, admissionregistrationv1.NamedRuleWithOperations{RuleWithOperations: rule})
+		}
+
+		validatingAdmissionPolicy.Spec = admissionregistrationv1.ValidatingAdmissionPolicySpec{
+			MatchConstraints: &admissionregistrationv1.MatchResources{
+				NamespaceSelector: r.namespaceSelector(policyGroup),
+				ObjectSelector:    policyGroup.GetObjectSelector(),
+				ResourceRules:     resourceRules,
+				MatchPolicy:       policyGroup.GetMatchPolicy(),
+			},
+			FailurePolicy:   policyGroup.GetFailurePolicy(),
+			MatchConditions: policyGroup.GetMatchConditions(),
+			Variables:       compiled.variables,
+			Validations: []admissionregistrationv1.Validation{
+				{
+					Expression: compiled.expression,
+					Message:    policyGroup.GetMessage(),
+				},
+			},
+		}
+
+		return controllerutil.SetOwnerReference(policyGroup, validatingAdmissionPolicy, r.Scheme())
+	})
+	if err != nil {
+		return fmt.Errorf("cannot reconcile validating admission policy: %w", err)
+	}
+
+	validatingAdmissionPolicyBinding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: policyGroup.GetUniqueName(),
+		},
+	}
+	_, err = controllerutil.CreateOrPatch(ctx, r.Client, validatingAdmissionPolicyBinding, func() error {
+		validatingAdmissionPolicyBinding.Labels = labels
+		validatingAdmissionPolicyBinding.Annotations = annotations
+
+		validationActions := []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny}
+		if policiesv1.PolicyModeStatus(policyGroup.GetPolicyMode()) == policiesv1.PolicyModeStatusMonitor {
+			validationActions = []admissionregistrationv1.ValidationAction{admissionregistrationv1.Audit}
+		}
+		validatingAdmissionPolicyBinding.Spec = admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName:        validatingAdmissionPolicy.Name,
+			ValidationActions: validationActions,
+		}
+
+		return controllerutil.SetOwnerReference(policyGroup, validatingAdmissionPolicyBinding, r.Scheme())
+	})
+	if err != nil {
+		return fmt.Errorf("cannot reconcile validating admission policy binding: %w", err)
+	}
+
+	return nil
+}
+
+// reconcileValidatingAdmissionPolicyDeletion deletes the
+// ValidatingAdmissionPolicy and its binding created for the policy group, once
+// the policy group falls back to the webhook backend and its webhook is
+// reconciled. They are only looked up when they have been created, to avoid
+// failing on clusters not serving their API.
+func (r *policySubReconciler) reconcileValidatingAdmissionPolicyDeletion(ctx context.Context, policyGroup *policiesv1.ClusterAdmissionPolicyGroup) error {
+	if policyGroup.Status.Backend != policiesv1.PolicyGroupBackendValidatingAdmissionPolicy {
+		return nil
+	}
+
+	objects := []client.Object{
+		&admissionregistrationv1.ValidatingAdmissionPolicyBinding{},
+		&admissionregistrationv1.ValidatingAdmissionPolicy{},
+	}
+	for _, object := range objects {
+		if err := r.Get(ctx, types.NamespacedName{Name: policyGroup.GetUniqueName()}, object); err != nil {
+			if client.IgnoreNotFound(err) != nil && !apimeta.IsNoMatchError(err) {
+				return fmt.Errorf("cannot retrieve validating admission policy: %w", err)
+			}
+			continue
+		}
+		if err := client.IgnoreNotFound(r.Delete(ctx, object)); err != nil {
+			return fmt.Errorf("cannot delete validating admission policy: %w", err)
+		}
+	}
+	policyGroup.Status.Backend = policiesv1.PolicyGroupBackendWebhook
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+// SetupPolicyServerIndexes registers the constants.PolicyServerIndexKey field
+// index of all the policy kinds, indexing the policies by the name of their
+// policy server, and the constants.PolicyServerSecretsIndexKey field index of
+// the PolicyServers. It must be called once, before setting up the reconcilers
+// using the indexes.
+func SetupPolicyServerIndexes(ctx context.Context, mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &policiesv1.PolicyServer{}, constants.PolicyServerSecretsIndexKey, indexPolicyServerSecrets); err != nil {
+		return fmt.Errorf("failed indexing policy servers by secret: %w", err)
+	}
+
+	for _, policy := range []policiesv1.Policy{
+		&policiesv1.AdmissionPolicy{},
+		&policiesv1.AdmissionPolicyGroup{},
+		&policiesv1.ClusterAdmissionPolicy{},
+		&policiesv1.ClusterAdmissionPolicyGroup{},
+	} {
+		if err := mgr.GetFieldIndexer().IndexField(ctx, policy, constants.PolicyServerIndexKey, indexPolicyServer); err != nil {
+			return fmt.Errorf("failed indexing %T by policy server: %w", policy, err)
+		}
+	}
+
+	return nil
+}
+
+// indexPolicyServer returns the name of the policy server of the given
+// policy.
+func indexPolicyServer(object client.Object) []string {
+	policy, ok := object.(policiesv1.Policy)
+	if !ok {
+		return []string{}
+	}
+	return []string{policy.GetPolicyServer()}
+}
+
+// indexPolicyServerSecrets returns the names of the secrets referenced by the
+// given PolicyServer.
+func indexPolicyServerSecrets(object client.Object) []string {
+	policyServer, ok := object.(*policiesv1.PolicyServer)
+	if !ok {
+		return []string{}
+	}
+	secrets := policyServer.ImagePullSecretNames()
+	if policyServer.Spec.CertificateSecret != "" {
+		secrets = append(secrets, policyServer.Spec.CertificateSecret)
+	}
+	return secrets
+}
+
+// findPolicyServersForSecret returns the policy servers referencing the given
+// secret of the deployments namespace, found through the
+// constants.PolicyServerSecretsIndexKey field index.
+func findPolicyServersForSecret(ctx context.Context, k8sClient client.Reader, deploymentsNamespace string, secret client.Object) ([]policiesv1.PolicyServer, error) {
+	if secret.GetNamespace() != deploymentsNamespace {
+		return nil, nil
+	}
+
+	var policyServers policiesv1.PolicyServerList
+	if err := k8sClient.List(ctx, &policyServers, client.MatchingFields{constants.PolicyServerSecretsIndexKey: secret.GetName()}); err != nil {
+		return nil, fmt.Errorf("cannot list the policy servers of the secret: %w", err)
+	}
+	return policyServers.Items, nil
+}
+
+// findPoliciesForCertificateSecret returns the requests of the policies of
+// the given kind bound to the policy servers using the given secret as
+// externally managed certificate, to refresh the CA bundle of their webhooks.
+func findPoliciesForCertificateSecret(ctx context.Context, k8sClient client.Reader, deploymentsNamespace string, policyList client.ObjectList, secret client.Object) []reconcile.Request {
+	policyServers, err := findPolicyServersForSecret(ctx, k8sClient, deploymentsNamespace, secret)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := []reconcile.Request{}
+	for _, policyServer := range policyServers {
+		if policyServer.Spec.CertificateSecret != secret.GetName() {
+			continue
+		}
+		requests = append(requests, findPoliciesForPolicyServer(ctx, k8sClient, policyList, &policyServer)...)
+	}
+	return requests
+}
+
+// findPoliciesForPolicyServer returns the requests of the policies of the
+// given kind bound to the given policy server, found through the
+// constants.PolicyServerIndexKey field index.
+func findPoliciesForPolicyServer(ctx context.Context, k8sClient client.Reader, policyList client.ObjectList, policyServer client.Object) []reconcile.Request {
+	err := k8sClient.List(ctx, policyList, client.MatchingFields{constants.PolicyServerIndexKey: policyServer.GetName()})
+	if err != nil {
+		return []reconcile.Request{}
+	}
+	policies, err := apimeta.ExtractList(policyList)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0, len(policies))
+	for _, object := range policies {
+		policy, ok := object.(client.Object)
+		if !ok {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(policy)})
+	}
+	return requests
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+)
+
+// ListPolicies returns all the admission policies, cluster admission
+// policies, admission policy groups and cluster admission policy groups
+// installed in the cluster.
+func ListPolicies(ctx context.Context, k8sClient client.Reader) ([]policiesv1.Policy, error) {
+	var clusterAdmissionPolicies policiesv1.ClusterAdmissionPolicyList
+	if err := k8sClient.List(ctx, &clusterAdmissionPolicies); err != nil {
+		return nil, fmt.Errorf("failed obtaining ClusterAdmissionPolicies: %w", err)
+	}
+	var admissionPolicies policiesv1.AdmissionPolicyList
+	if err := k8sClient.List(ctx, &admissionPolicies); err != nil {
+		return nil, fmt.Errorf("failed obtaining AdmissionPolicies: %w", err)
+	}
+	var admissionPolicyGroups policiesv1.AdmissionPolicyGroupList
+	if err := k8sClient.List(ctx, &admissionPolicyGroups); err != nil {
+		return nil, fmt.Errorf("failed obtaining AdmissionPolicyGroups: %w", err)
+	}
+	var clusterAdmissionPolicyGroups policiesv1.ClusterAdmissionPolicyGroupList
+	if err := k8sClient.List(ctx, &clusterAdmissionPolicyGroups); err != nil {
+		return nil, fmt.Errorf("failed obtaining ClusterAdmissionPolicyGroups: %w", err)
+	}
+
+	policies := make([]policiesv1.Policy, 0)
+	for _, clusterAdmissionPolicy := range clusterAdmissionPolicies.Items {
+		policies = append(policies, clusterAdmissionPolicy.DeepCopy())
+	}
+	for _, admissionPolicy := range admissionPolicies.Items {
+		policies = append(policies, admissionPolicy.DeepCopy())
+	}
+	for _, admissionPolicyGroup := range admissionPolicyGroups.Items {
+		policies = append(policies, admissionPolicyGroup.DeepCopy())
+	}
+	for _, clusterAdmissionPolicyGroup := range clusterAdmissionPolicyGroups.Items {
+		policies = append(policies, clusterAdmissionPolicyGroup.DeepCopy())
+	}
+	return policies, nil
+}
@@ -17,10 +17,12 @@ limitations under the License.
 package controller
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -28,15 +30,22 @@ import (
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
+
 	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/certs"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
 	"github.com/kubewarden/kubewarden-controller/internal/metrics"
+	"github.com/kubewarden/kubewarden-controller/internal/tracing"
 )
 
 type policySubReconciler struct {
@@ -44,13 +53,33 @@ type policySubReconciler struct {
 	Log                                        logr.Logger
 	deploymentsNamespace                       string
 	featureGateAdmissionWebhookMatchConditions bool
+	policyServerRestartGracePeriod             time.Duration
+	clock                                      clock.PassiveClock
+	tracer                                     trace.Tracer
 }
 
 func (r *policySubReconciler) reconcile(ctx context.Context, policy policiesv1.Policy) (ctrl.Result, error) {
+	ctx, span := tracing.StartReconcileSpan(ctx, r.tracer, policy, "reconcile policy",
+		tracing.PolicyNameKey.String(policy.GetUniqueName()),
+		tracing.PolicyServerNameKey.String(policy.GetPolicyServer()),
+	)
+	defer span.End()
+
 	if policy.GetDeletionTimestamp() != nil {
 		return r.reconcilePolicyDeletion(ctx, policy)
 	}
 
+	if isReconciliationPaused(policy) {
+		// The webhook configurations of the policy are left untouched, only
+		// the status reports the reconciliation is paused.
+		apimeta.SetStatusCondition(&policy.GetStatus().Conditions, reconciliationPausedCondition(string(policiesv1.PolicyReconciliationPaused)))
+		if err := r.Status().Update(ctx, policy); err != nil {
+			return ctrl.Result{}, fmt.Errorf("update admission policy status error: %w", err)
+		}
+		return ctrl.Result{}, nil
+	}
+	apimeta.RemoveStatusCondition(&policy.GetStatus().Conditions, string(policiesv1.PolicyReconciliationPaused))
+
 	reconcileResult, reconcileErr := r.reconcilePolicy(ctx, policy)
 
 	if err := r.setPolicyModeStatus(ctx, policy); err != nil {
@@ -61,11 +90,6 @@ func (r *policySubReconciler) reconcile(ctx context.Context, policy policiesv1.P
 		return ctrl.Result{}, fmt.Errorf("update admission policy status error: %w", err)
 	}
 
-	// record policy count metric
-	if err := metrics.RecordPolicyCount(ctx, policy); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to record policy mestrics: %w", err)
-	}
-
 	return reconcileResult, reconcileErr
 }
 
@@ -79,17 +103,31 @@ func (r *policySubReconciler) reconcilePolicy(ctx context.Context, policy polici
 			Message: "The policy webhook has not been created",
 		},
 	)
+	// The policy groups enforced by a ValidatingAdmissionPolicy do not need
+	// a policy server
+	if policyGroup, ok := policy.(*policiesv1.ClusterAdmissionPolicyGroup); ok {
+		result, enforced, err := r.reconcilePolicyGroupBackend(ctx, policyGroup)
+		if enforced || err != nil {
+			return result, err
+		}
+	}
+
 	if policy.GetPolicyServer() == "" {
 		policy.SetStatus(policiesv1.PolicyStatusUnscheduled)
+		policy.GetStatus().BackgroundAudit = nil
 		return ctrl.Result{}, nil
 	}
 
 	policyServer, err := r.getPolicyServer(ctx, policy)
 	if err != nil {
 		policy.SetStatus(policiesv1.PolicyStatusScheduled)
+		policy.GetStatus().BackgroundAudit = nil
 		//nolint:nilerr // set status to scheduled if policyServer can't be retrieved, and stop reconciling
 		return ctrl.Result{}, nil
 	}
+	// The audit checks read the resolved value from the status, the policy
+	// is re-reconciled when the default of its policy server changes.
+	policy.GetStatus().BackgroundAudit = ptr.To(policyServer.IsBackgroundAuditEnabled(policy))
 	if policy.GetStatus().PolicyStatus != policiesv1.PolicyStatusActive {
 		policy.SetStatus(policiesv1.PolicyStatusPending)
 	}
@@ -103,6 +141,9 @@ func (r *policySubReconciler) reconcilePolicy(ctx context.Context, policy polici
 	}
 
 	if !r.isPolicyUniquelyReachable(ctx, &policyServerDeployment, policy.GetUniqueName()) {
+		if r.isPolicyServerRestarting(ctx, policy, &policyServerDeployment) {
+			return r.holdPolicyStatusDuringRestart(policy), nil
+		}
 		apimeta.SetStatusCondition(
 			&policy.GetStatus().Conditions,
 			metav1.Condition{
@@ -125,34 +166,50 @@ func (r *policySubReconciler) reconcilePolicy(ctx context.Context, policy polici
 		},
 	)
 
+	// The CA bundle is read from the ca.crt key of the secret, either the
+	// controller CA root or the externally managed policy server certificate.
+	// The CA bundle of the externally managed certificates is refreshed only
+	// here, when their secret changes.
+	caSecretName := constants.CARootSecretName
+	if policyServer.Spec.CertificateSecret != "" {
+		caSecretName = policyServer.Spec.CertificateSecret
+	}
 	secret := corev1.Secret{}
-	if err = r.Get(ctx, types.NamespacedName{Namespace: r.deploymentsNamespace, Name: constants.CARootSecretName}, &secret); err != nil {
+	if err = r.Get(ctx, types.NamespacedName{Namespace: r.deploymentsNamespace, Name: caSecretName}, &secret); err != nil {
 		return ctrl.Result{}, errors.Join(errors.New("cannot find policy server secret"), err)
 	}
+	if policyServer.Spec.CertificateSecret != "" {
+		if _, err = certs.ExtractCABundleFromExternalCertSecret(&secret, certs.DNSName(policyServer.NameWithPrefix(), r.deploymentsNamespace)); err != nil {
+			return ctrl.Result{}, errors.Join(errors.New("invalid policy server certificate secret"), err)
+		}
+	}
+
+	r.setPolicyMatchConditionsIgnoredCondition(policy)
 
 	if policy.IsMutating() {
-		if err = r.reconcileMutatingWebhookConfiguration(ctx, policy, &secret, policyServer.NameWithPrefix()); err != nil {
+		if err = r.reconcileMutatingWebhookConfiguration(ctx, policy, &secret, policyServer); err != nil {
+			r.recordWebhookConfigError(ctx, policy, err)
 			return ctrl.Result{}, errors.Join(errors.New("error reconciling mutating webhook"), err)
 		}
 	} else {
-		if err = r.reconcileValidatingWebhookConfiguration(ctx, policy, &secret, policyServer.NameWithPrefix()); err != nil {
+		if err = r.reconcileValidatingWebhookConfiguration(ctx, policy, &secret, policyServer); err != nil {
+			r.recordWebhookConfigError(ctx, policy, err)
 			return ctrl.Result{}, errors.Join(errors.New("error reconciling validating webhook"), err)
 		}
 	}
+	if policyGroup, ok := policy.(*policiesv1.ClusterAdmissionPolicyGroup); ok {
+		if err = r.reconcileValidatingAdmissionPolicyDeletion(ctx, policyGroup); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 	setPolicyAsActive(policy)
 
 	return ctrl.Result{}, nil
 }
 
 func (r *policySubReconciler) reconcilePolicyDeletion(ctx context.Context, policy policiesv1.Policy) (ctrl.Result, error) {
-	if policy.IsMutating() {
-		if err := r.reconcileMutatingWebhookConfigurationDeletion(ctx, policy); err != nil {
-			return ctrl.Result{}, err
-		}
-	} else {
-		if err := r.reconcileValidatingWebhookConfigurationDeletion(ctx, policy); err != nil {
-			return ctrl.Result{}, err
-		}
+	if err := r.deletePolicyWebhookConfiguration(ctx, policy); err != nil {
+		return ctrl.Result{}, err
 	}
 	// Remove the old finalizer used to ensure that the policy server created
 	// before this controller version is delete as well. As the upgrade path
@@ -167,7 +224,45 @@ func (r *policySubReconciler) reconcilePolicyDeletion(ctx context.Context, polic
 	return ctrl.Result{}, nil
 }
 
+// deletePolicyWebhookConfiguration deletes the webhook configuration of the
+// given policy, if any.
+func (r *policySubReconciler) deletePolicyWebhookConfiguration(ctx context.Context, policy policiesv1.Policy) error {
+	var err error
+	if policy.IsMutating() {
+		err = r.reconcileMutatingWebhookConfigurationDeletion(ctx, policy)
+	} else {
+		err = r.reconcileValidatingWebhookConfigurationDeletion(ctx, policy)
+	}
+	if err != nil {
+		r.recordWebhookConfigError(ctx, policy, err)
+		return err
+	}
+
+	return nil
+}
+
+// recordWebhookConfigError records the failure of the reconciliation of the
+// webhook configuration of the given policy. The failure reason is the reason
+// of the Kubernetes API error, Unknown for the other errors.
+func (r *policySubReconciler) recordWebhookConfigError(ctx context.Context, policy policiesv1.Policy, err error) {
+	kind := "Unknown"
+	if gvk, gvkErr := apiutil.GVKForObject(policy, r.Scheme()); gvkErr == nil {
+		kind = gvk.Kind
+	}
+	reason := cmp.Or(string(apierrors.ReasonForError(err)), "Unknown")
+
+	if recordErr := metrics.RecordWebhookConfigError(ctx, kind, reason); recordErr != nil {
+		r.Log.Error(recordErr, "failed to record the webhook configuration error metric", "policy", policy.GetUniqueName())
+	}
+}
+
 func (r *policySubReconciler) setPolicyModeStatus(ctx context.Context, policy policiesv1.Policy) error {
+	if policy.GetStatus().Backend == policiesv1.PolicyGroupBackendValidatingAdmissionPolicy {
+		// The policy is enforced by the Kubernetes API server, not by the
+		// policy server
+		return nil
+	}
+
 	policyServerDeployment := appsv1.Deployment{}
 	policyServerDeploymentName := policyServerDeploymentName(policy.GetPolicyServer())
 
@@ -254,6 +349,77 @@ func (r *policySubReconciler) isPolicyUniquelyReachable(ctx context.Context, pol
 	return true
 }
 
+// isPolicyServerRestarting returns true when an active policy is not reachable
+// while the policy server is already running the latest configuration. This
+// happens when the policy server pods are restarted, not when a new
+// configuration is being rolled out.
+func (r *policySubReconciler) isPolicyServerRestarting(ctx context.Context, policy policiesv1.Policy, policyServerDeployment *appsv1.Deployment) bool {
+	if r.policyServerRestartGracePeriod <= 0 || policy.GetStatus().PolicyStatus != policiesv1.PolicyStatusActive {
+		return false
+	}
+
+	configMap := corev1.ConfigMap{}
+	err := r.Get(ctx, client.ObjectKey{
+		Namespace: policyServerDeployment.Namespace,
+		Name:      policyServerDeployment.Name, // As the deployment name matches the name of the ConfigMap
+	}, &configMap)
+	if err != nil {
+		return false
+	}
+
+	return isPolicyInConfigMap(configMap, policy.GetUniqueName()) &&
+		policyServerDeployment.Annotations[constants.PolicyServerDeploymentConfigVersionAnnotation] == configMap.ResourceVersion
+}
+
+// holdPolicyStatusDuringRestart keeps the policy active, with its PolicyActive
+// condition set as reconciling, until the restart grace period expires. Once
+// the grace period is over, the policy is flipped back to pending.
+func (r *policySubReconciler) holdPolicyStatusDuringRestart(policy policiesv1.Policy) ctrl.Result {
+	now := r.clock.Now()
+	conditions := &policy.GetStatus().Conditions
+
+	// The transition time of the condition is only updated when its status
+	// changes, so it records when the restart has been detected.
+	apimeta.SetStatusCondition(
+		conditions,
+		metav1.Condition{
+			Type:               string(policiesv1.PolicyUniquelyReachable),
+			Status:             metav1.ConditionFalse,
+			Reason:             "PolicyServerRestarting",
+			Message:            "The policy server is restarting",
+			LastTransitionTime: metav1.NewTime(now),
+		},
+	)
+	restartingSince := apimeta.FindStatusCondition(*conditions, string(policiesv1.PolicyUniquelyReachable)).LastTransitionTime.Time
+
+	if remaining := r.policyServerRestartGracePeriod - now.Sub(restartingSince); remaining > 0 {
+		apimeta.SetStatusCondition(
+			conditions,
+			metav1.Condition{
+				Type:               string(policiesv1.PolicyActive),
+				Status:             metav1.ConditionUnknown,
+				Reason:             "Reconciling",
+				Message:            "The policy server is restarting, waiting for it to become ready",
+				LastTransitionTime: metav1.NewTime(now),
+			},
+		)
+		return ctrl.Result{RequeueAfter: remaining}
+	}
+
+	policy.SetStatus(policiesv1.PolicyStatusPending)
+	apimeta.SetStatusCondition(
+		conditions,
+		metav1.Condition{
+			Type:               string(policiesv1.PolicyActive),
+			Status:             metav1.ConditionFalse,
+			Reason:             "PolicyServerNotReady",
+			Message:            "The policy server did not become ready within the restart grace period",
+			LastTransitionTime: metav1.NewTime(now),
+		},
+	)
+	return ctrl.Result{RequeueAfter: constants.TimeToRequeuePolicyReconciliation}
+}
+
 func isLatestReplicaSetFromPolicyServerDeployment(replicaSet *appsv1.ReplicaSet, policyServerDeployment *appsv1.Deployment, configMapVersion string) bool {
 	return replicaSet.Annotations[constants.KubernetesRevisionAnnotation] == policyServerDeployment.Annotations[constants.KubernetesRevisionAnnotation] &&
 		replicaSet.Annotations[constants.PolicyServerDeploymentConfigVersionAnnotation] == configMapVersion
@@ -383,6 +549,27 @@ func setPolicyAsActive(policy policiesv1.Policy) {
 	)
 }
 
+// setPolicyMatchConditionsIgnoredCondition reports the matchConditions of the
+// policy that are not applied to its webhook because the
+// AdmissionWebhookMatchConditions feature gate is disabled. The condition is
+// removed when the matchConditions are applied or the policy has none.
+func (r *policySubReconciler) setPolicyMatchConditionsIgnoredCondition(policy policiesv1.Policy) {
+	if r.featureGateAdmissionWebhookMatchConditions || len(policy.GetMatchConditions()) == 0 {
+		apimeta.RemoveStatusCondition(&policy.GetStatus().Conditions, string(policiesv1.PolicyMatchConditionsIgnored))
+		return
+	}
+
+	apimeta.SetStatusCondition(
+		&policy.GetStatus().Conditions,
+		metav1.Condition{
+			Type:    string(policiesv1.PolicyMatchConditionsIgnored),
+			Status:  metav1.ConditionTrue,
+			Reason:  "AdmissionWebhookMatchConditionsDisabled",
+			Message: fmt.Sprintf("The %d matchConditions of the policy are ignored: the AdmissionWebhookMatchConditions feature gate is disabled in the cluster, the policy is evaluated for all the requests matching its rules", len(policy.GetMatchConditions())),
+		},
+	)
+}
+
 func setPolicyConfigurationCondition(policyServerConfigMap *corev1.ConfigMap, policyServerDeployment *appsv1.Deployment, conditions *[]metav1.Condition) {
 	if configAnnotation, ok := policyServerDeployment.Annotations[constants.PolicyServerDeploymentConfigVersionAnnotation]; ok {
 		if configAnnotation == policyServerConfigMap.ResourceVersion {
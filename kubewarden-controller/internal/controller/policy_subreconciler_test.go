@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/noop"
+	metricSDK "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+var _ = Describe("Policy status during a policy server restart", func() {
+	var (
+		fakeClock     *clocktesting.FakePassiveClock
+		subReconciler *policySubReconciler
+		policy        *policiesv1.ClusterAdmissionPolicy
+	)
+
+	BeforeEach(func() {
+		fakeClock = clocktesting.NewFakePassiveClock(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+		subReconciler = &policySubReconciler{
+			policyServerRestartGracePeriod: time.Minute,
+			clock:                          fakeClock,
+		}
+		policy = &policiesv1.ClusterAdmissionPolicy{
+			Status: policiesv1.PolicyStatus{
+				PolicyStatus: policiesv1.PolicyStatusActive,
+			},
+		}
+		setPolicyAsActive(policy)
+		apimeta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{
+			Type:   string(policiesv1.PolicyUniquelyReachable),
+			Status: metav1.ConditionTrue,
+			Reason: "LatestReplicaSetIsUniquelyReachable",
+		})
+	})
+
+	It("should hold the policy status during the grace period", func() {
+		result := subReconciler.holdPolicyStatusDuringRestart(policy)
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+		fakeClock.SetTime(fakeClock.Now().Add(40 * time.Second))
+		result = subReconciler.holdPolicyStatusDuringRestart(policy)
+		Expect(result.RequeueAfter).To(Equal(20 * time.Second))
+
+		Expect(policy.Status.PolicyStatus).To(Equal(policiesv1.PolicyStatusActive))
+		Expect(apimeta.FindStatusCondition(policy.Status.Conditions, string(policiesv1.PolicyActive))).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionUnknown),
+			"Reason": Equal("Reconciling"),
+		})))
+	})
+
+	It("should resolve the policy status once the grace period expires", func() {
+		subReconciler.holdPolicyStatusDuringRestart(policy)
+
+		fakeClock.SetTime(fakeClock.Now().Add(time.Minute))
+		result := subReconciler.holdPolicyStatusDuringRestart(policy)
+		Expect(result.RequeueAfter).To(Equal(constants.TimeToRequeuePolicyReconciliation))
+
+		Expect(policy.Status.PolicyStatus).To(Equal(policiesv1.PolicyStatusPending))
+		Expect(apimeta.FindStatusCondition(policy.Status.Conditions, string(policiesv1.PolicyActive))).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionFalse),
+			"Reason": Equal("PolicyServerNotReady"),
+		})))
+	})
+})
+
+var _ = Describe("Policy server default policy timeout", func() {
+	ctx := context.Background()
+
+	var policyServer *policiesv1.PolicyServer
+
+	reconcile := func(timeoutSeconds *int32) *admissionregistrationv1.ValidatingWebhookConfiguration {
+		policy := policiesv1.NewClusterAdmissionPolicyFactory().WithName("default-policy-timeout").WithPolicyServer("default").Build()
+		policy.Spec.TimeoutSeconds = timeoutSeconds
+
+		subReconciler := &policySubReconciler{
+			Client:               fake.NewClientBuilder().Build(),
+			deploymentsNamespace: "kubewarden",
+		}
+		Expect(subReconciler.reconcileValidatingWebhookConfiguration(ctx, policy, &corev1.Secret{}, policyServer)).To(Succeed())
+
+		webhook := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		Expect(subReconciler.Get(ctx, client.ObjectKey{Name: policy.GetUniqueName()}, webhook)).To(Succeed())
+		return webhook
+	}
+
+	BeforeEach(func() {
+		policyServer = policiesv1.NewPolicyServerFactory().WithName("default").Build()
+		policyServer.Spec.DefaultPolicyTimeoutSeconds = ptr.To(int32(5))
+	})
+
+	It("should use the policy server default when the policy does not set timeoutSeconds", func() {
+		webhook := reconcile(nil)
+		Expect(webhook.Webhooks).To(HaveLen(1))
+		Expect(webhook.Webhooks[0].TimeoutSeconds).To(HaveValue(Equal(int32(5))))
+	})
+
+	It("should use the timeoutSeconds set by the policy", func() {
+		webhook := reconcile(ptr.To(int32(20)))
+		Expect(webhook.Webhooks).To(HaveLen(1))
+		Expect(webhook.Webhooks[0].TimeoutSeconds).To(HaveValue(Equal(int32(20))))
+	})
+
+	It("should leave timeoutSeconds unset when the policy server has no default", func() {
+		policyServer.Spec.DefaultPolicyTimeoutSeconds = nil
+		webhook := reconcile(nil)
+		Expect(webhook.Webhooks).To(HaveLen(1))
+		Expect(webhook.Webhooks[0].TimeoutSeconds).To(BeNil())
+	})
+})
+
+var _ = Describe("Mutating policy reinvocationPolicy", func() {
+	ctx := context.Background()
+
+	reconcile := func(reinvocationPolicy *admissionregistrationv1.ReinvocationPolicyType) *admissionregistrationv1.MutatingWebhookConfiguration {
+		policy := policiesv1.NewClusterAdmissionPolicyFactory().WithName("reinvocation-policy").WithPolicyServer("default").WithMutating(true).Build()
+		policy.Spec.ReinvocationPolicy = reinvocationPolicy
+
+		subReconciler := &policySubReconciler{
+			Client:               fake.NewClientBuilder().Build(),
+			deploymentsNamespace: "kubewarden",
+		}
+		policyServer := policiesv1.NewPolicyServerFactory().WithName("default").Build()
+		Expect(subReconciler.reconcileMutatingWebhookConfiguration(ctx, policy, &corev1.Secret{}, policyServer)).To(Succeed())
+
+		webhook := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		Expect(subReconciler.Get(ctx, client.ObjectKey{Name: policy.GetUniqueName()}, webhook)).To(Succeed())
+		return webhook
+	}
+
+	It("should never reinvoke the policy by default", func() {
+		webhook := reconcile(nil)
+		Expect(webhook.Webhooks).To(HaveLen(1))
+		Expect(webhook.Webhooks[0].ReinvocationPolicy).To(HaveValue(Equal(admissionregistrationv1.NeverReinvocationPolicy)))
+	})
+
+	It("should use the reinvocationPolicy set by the policy", func() {
+		webhook := reconcile(ptr.To(admissionregistrationv1.IfNeededReinvocationPolicy))
+		Expect(webhook.Webhooks).To(HaveLen(1))
+		Expect(webhook.Webhooks[0].ReinvocationPolicy).To(HaveValue(Equal(admissionregistrationv1.IfNeededReinvocationPolicy)))
+	})
+})
+
+var _ = Describe("Policy matchConditions ignored condition", func() {
+	matchConditions := []admissionregistrationv1.MatchCondition{
+		{Name: "exclude-leases", Expression: `!(request.resource.group == "coordination.k8s.io" && request.resource.resource == "leases")`},
+	}
+
+	It("should report the matchConditions ignored when the feature gate is disabled", func() {
+		subReconciler := &policySubReconciler{featureGateAdmissionWebhookMatchConditions: false}
+		policy := policiesv1.NewClusterAdmissionPolicyFactory().WithMatchConditions(matchConditions).Build()
+
+		subReconciler.setPolicyMatchConditionsIgnoredCondition(policy)
+
+		Expect(apimeta.FindStatusCondition(policy.Status.Conditions, string(policiesv1.PolicyMatchConditionsIgnored))).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status":  Equal(metav1.ConditionTrue),
+			"Reason":  Equal("AdmissionWebhookMatchConditionsDisabled"),
+			"Message": ContainSubstring("The 1 matchConditions of the policy are ignored"),
+		})))
+	})
+
+	It("should remove the condition when the feature gate is enabled", func() {
+		subReconciler := &policySubReconciler{featureGateAdmissionWebhookMatchConditions: false}
+		policy := policiesv1.NewClusterAdmissionPolicyFactory().WithMatchConditions(matchConditions).Build()
+		subReconciler.setPolicyMatchConditionsIgnoredCondition(policy)
+
+		subReconciler.featureGateAdmissionWebhookMatchConditions = true
+		subReconciler.setPolicyMatchConditionsIgnoredCondition(policy)
+
+		Expect(apimeta.FindStatusCondition(policy.Status.Conditions, string(policiesv1.PolicyMatchConditionsIgnored))).To(BeNil())
+	})
+
+	It("should not report the condition when the policy has no matchConditions", func() {
+		subReconciler := &policySubReconciler{featureGateAdmissionWebhookMatchConditions: false}
+		policy := policiesv1.NewClusterAdmissionPolicyFactory().WithMatchConditions(nil).Build()
+
+		subReconciler.setPolicyMatchConditionsIgnoredCondition(policy)
+
+		Expect(apimeta.FindStatusCondition(policy.Status.Conditions, string(policiesv1.PolicyMatchConditionsIgnored))).To(BeNil())
+	})
+})
+
+var _ = Describe("Policy webhook configuration errors", func() {
+	It("should record the kind of the policy and the reason of the error", func() {
+		reader := metricSDK.NewManualReader()
+		meterProvider := metricSDK.NewMeterProvider(metricSDK.WithReader(reader))
+		otel.SetMeterProvider(meterProvider)
+		DeferCleanup(func() {
+			otel.SetMeterProvider(noop.NewMeterProvider())
+		})
+
+		subReconciler := &policySubReconciler{
+			Client: fake.NewClientBuilder().WithScheme(newFakeClientTestScheme()).Build(),
+		}
+		policy := policiesv1.NewAdmissionPolicyFactory().Build()
+		conflict := apierrors.NewConflict(admissionregistrationv1.Resource("validatingwebhookconfigurations"), policy.GetUniqueName(), errors.New("the object has been modified"))
+
+		subReconciler.recordWebhookConfigError(context.Background(), policy, fmt.Errorf("cannot reconcile validating webhook: %w", conflict))
+		subReconciler.recordWebhookConfigError(context.Background(), policy, errors.New("cannot build the webhook configuration"))
+
+		var resourceMetrics metricdata.ResourceMetrics
+		Expect(reader.Collect(context.Background(), &resourceMetrics)).To(Succeed())
+		Expect(resourceMetrics.ScopeMetrics).To(HaveLen(1))
+		Expect(resourceMetrics.ScopeMetrics[0].Metrics).To(HaveLen(1))
+		sum, ok := resourceMetrics.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+		Expect(ok).To(BeTrue())
+		Expect(sum.DataPoints).To(ConsistOf(
+			HaveField("Attributes", Equal(attribute.NewSet(attribute.String("policy_kind", "AdmissionPolicy"), attribute.String("reason", "Conflict")))),
+			HaveField("Attributes", Equal(attribute.NewSet(attribute.String("policy_kind", "AdmissionPolicy"), attribute.String("reason", "Unknown")))),
+		))
+	})
+})
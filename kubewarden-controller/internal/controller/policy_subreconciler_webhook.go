@@ -23,7 +23,7 @@ func (r *policySubReconciler) reconcileValidatingWebhookConfiguration(
 	ctx context.Context,
 	policy policiesv1.Policy,
 	admissionSecret *corev1.Secret,
-	policyServerNameWithPrefix string,
+	policyServer *policiesv1.PolicyServer,
 ) error {
 	webhook := &admissionregistrationv1.ValidatingWebhookConfiguration{
 		ObjectMeta: metav1.ObjectMeta{
@@ -36,7 +36,7 @@ func (r *policySubReconciler) reconcileValidatingWebhookConfiguration(
 
 		service := admissionregistrationv1.ServiceReference{
 			Namespace: r.deploymentsNamespace,
-			Name:      policyServerNameWithPrefix,
+			Name:      policyServer.NameWithPrefix(),
 			Path:      &admissionPath,
 			Port:      &admissionPort,
 		}
@@ -69,7 +69,7 @@ func (r *policySubReconciler) reconcileValidatingWebhookConfiguration(
 				NamespaceSelector:       r.namespaceSelector(policy),
 				ObjectSelector:          policy.GetObjectSelector(),
 				SideEffects:             sideEffects,
-				TimeoutSeconds:          policy.GetTimeoutSeconds(),
+				TimeoutSeconds:          webhookTimeoutSeconds(policy, policyServer),
 				AdmissionReviewVersions: []string{"v1"},
 			},
 		}
@@ -111,7 +111,7 @@ func (r *policySubReconciler) reconcileMutatingWebhookConfiguration(
 	ctx context.Context,
 	policy policiesv1.Policy,
 	admissionSecret *corev1.Secret,
-	policyServerNameWithPrefix string,
+	policyServer *policiesv1.PolicyServer,
 ) error {
 	webhook := &admissionregistrationv1.MutatingWebhookConfiguration{
 		ObjectMeta: metav1.ObjectMeta{
@@ -124,7 +124,7 @@ func (r *policySubReconciler) reconcileMutatingWebhookConfiguration(
 
 		service := admissionregistrationv1.ServiceReference{
 			Namespace: r.deploymentsNamespace,
-			Name:      policyServerNameWithPrefix,
+			Name:      policyServer.NameWithPrefix(),
 			Path:      &admissionPath,
 			Port:      &admissionPort,
 		}
@@ -134,6 +134,13 @@ func (r *policySubReconciler) reconcileMutatingWebhookConfiguration(
 			noneSideEffects := admissionregistrationv1.SideEffectClassNone
 			sideEffects = &noneSideEffects
 		}
+
+		reinvocationPolicy := policy.GetReinvocationPolicy()
+		if reinvocationPolicy == nil {
+			neverReinvocationPolicy := admissionregistrationv1.NeverReinvocationPolicy
+			reinvocationPolicy = &neverReinvocationPolicy
+		}
+
 		webhook.Name = policy.GetUniqueName()
 		webhook.Labels = map[string]string{
 			constants.PartOfLabelKey: constants.PartOfLabelValue,
@@ -155,7 +162,8 @@ func (r *policySubReconciler) reconcileMutatingWebhookConfiguration(
 				NamespaceSelector:       r.namespaceSelector(policy),
 				ObjectSelector:          policy.GetObjectSelector(),
 				SideEffects:             sideEffects,
-				TimeoutSeconds:          policy.GetTimeoutSeconds(),
+				TimeoutSeconds:          webhookTimeoutSeconds(policy, policyServer),
+				ReinvocationPolicy:      reinvocationPolicy,
 				AdmissionReviewVersions: []string{"v1"},
 			},
 		}
@@ -190,6 +198,17 @@ func (r *policySubReconciler) reconcileMutatingWebhookConfigurationDeletion(ctx
 	return nil
 }
 
+// webhookTimeoutSeconds returns the timeout of the policy webhook: the timeout
+// of the policy, or the default of its policy server when the policy does not
+// set it. Kubernetes applies its own default when both are unset.
+func webhookTimeoutSeconds(policy policiesv1.Policy, policyServer *policiesv1.PolicyServer) *int32 {
+	if policy.GetTimeoutSeconds() != nil {
+		return policy.GetTimeoutSeconds()
+	}
+
+	return policyServer.Spec.DefaultPolicyTimeoutSeconds
+}
+
 func (r *policySubReconciler) namespaceSelector(policy policiesv1.Policy) *metav1.LabelSelector {
 	switch policy.(type) {
 	case *policiesv1.ClusterAdmissionPolicyGroup, *policiesv1.ClusterAdmissionPolicy:
@@ -20,21 +20,32 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/certs"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
+	"github.com/kubewarden/kubewarden-controller/internal/tracing"
 )
 
 // Warning: this controller is deployed by a helm chart which has its own
@@ -52,6 +63,13 @@ import (
 //+kubebuilder:rbac:namespace=kubewarden,groups=apps,resources=replicasets,verbs=get;list;watch
 //+kubebuilder:rbac:namespace=kubewarden,groups=core,resources=pods,verbs=get;list;watch
 //+kubebuilder:rbac:namespace=kubewarden,groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:namespace=kubewarden,groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind;escalate,resourceNames=kubewarden-policy-server-default
+//+kubebuilder:rbac:namespace=kubewarden,groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:namespace=kubewarden,groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:namespace=kubewarden,groups=monitoring.coreos.com,resources=podmonitors,verbs=get;list;watch;create;update;patch;delete
 
 // PolicyServerReconciler reconciles a PolicyServer object.
 type PolicyServerReconciler struct {
@@ -62,14 +80,71 @@ type PolicyServerReconciler struct {
 	DeploymentsNamespace                               string
 	AlwaysAcceptAdmissionReviewsInDeploymentsNamespace bool
 	ClientCAConfigMapName                              string
+	// ManageNetworkPolicies creates a NetworkPolicy for each policy server,
+	// allowing the ingress traffic to the webhook port only from the
+	// NetworkPolicyAPIServerCIDRs and the audit scanner pods, and to the
+	// metrics port only from the NetworkPolicyMonitoringNamespace. The
+	// NetworkPolicies owned by the policy servers are deleted when it is not
+	// set.
+	ManageNetworkPolicies            bool
+	NetworkPolicyAPIServerCIDRs      []string
+	NetworkPolicyMonitoringNamespace string
+	// ZoneAntiAffinity spreads the replicas of the policy servers across the
+	// availability zones, unless a pod anti-affinity is set by the user.
+	ZoneAntiAffinity bool
+	// ServerCertDuration is the validity of the policy server certificates.
+	// Defaults to constants.ServerCertExpiration when zero.
+	ServerCertDuration time.Duration
+	// CertKeyAlgorithm is the algorithm of the private keys of the policy
+	// server certificates. Defaults to certs.DefaultKeyAlgorithm when empty.
+	CertKeyAlgorithm certs.KeyAlgorithm
+	// Recorder records the events about the reconciliation of the policy
+	// servers. It is set by SetupWithManager when nil.
+	Recorder record.EventRecorder
+	// MaxConcurrentReconciles is the maximum number of policy servers
+	// reconciled concurrently. The controller-runtime default of one is used
+	// when it is not set. Each policy server owns its resources, and a
+	// policy server is never reconciled by two workers at the same time.
+	MaxConcurrentReconciles int
+	// PolicyChangesDebounce is the time window during which the changes of
+	// the policies are coalesced before reconciling their policy server.
+	// The policy server is reconciled at every change when it is zero.
+	PolicyChangesDebounce time.Duration
+	// Tracer starts the spans of the reconciliations. The spans are not
+	// recorded when it is nil.
+	Tracer trace.Tracer
+	// NamespaceAllowList is the list of the namespaces whose AdmissionPolicies
+	// are loaded by the policy servers. The AdmissionPolicies of all the
+	// namespaces are loaded when it is empty.
+	NamespaceAllowList []string
+	podRestarts        podRestartsTracker
 }
 
+// Samplers of the traces of the policy servers.
+const (
+	// TracingSamplerAlways samples all the traces whose parent, if any, is
+	// sampled.
+	TracingSamplerAlways = "always"
+	// TracingSamplerNever does not sample any trace.
+	TracingSamplerNever = "never"
+	// TracingSamplerRatio samples a ratio of the traces whose parent, if
+	// any, is sampled.
+	TracingSamplerRatio = "ratio"
+)
+
 // TelemetryConfiguration is a struct that contains the configuration for the
 // Telemetry configuration. Now, it only contains the configuration for the
 // OpenTelemetry.
 type TelemetryConfiguration struct {
 	MetricsEnabled bool
 	TracingEnabled bool
+	// TracingSampler is the sampler of the traces of the policy servers,
+	// one of the TracingSampler constants. The policy servers use their
+	// default sampler when it is empty.
+	TracingSampler string
+	// TracingSamplerRatio is the ratio of the sampled traces when the
+	// TracingSamplerRatio sampler is used.
+	TracingSamplerRatio float64
 	// OpenTelemetry configuration.
 	// OtelSidecarEnabled is a flag that enables the OpenTelemetry sidecar.
 	OtelSidecarEnabled bool
@@ -78,17 +153,30 @@ type TelemetryConfiguration struct {
 	// controller and policy server with the remote OpenTelemetry collector.
 	OtelCertificateSecret       string
 	OtelClientCertificateSecret string
+	// ServiceMonitorEnabled is a flag that enables the creation of a
+	// Prometheus Operator ServiceMonitor for each Policy Server. It is only
+	// used when metrics are enabled.
+	ServiceMonitorEnabled bool
+	// PodMonitorEnabled is a flag that enables the creation of a Prometheus
+	// Operator PodMonitor for each Policy Server, scraping the policy server
+	// pods directly. It is only used when metrics are enabled.
+	PodMonitorEnabled bool
 }
 
-func (r *PolicyServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *PolicyServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
 	var policyServer policiesv1.PolicyServer
-	if err := r.Get(ctx, req.NamespacedName, &policyServer); err != nil {
+	if err = r.Get(ctx, req.NamespacedName, &policyServer); err != nil {
 		if client.IgnoreNotFound(err) != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to get policy server: %w", err)
 		}
 		return ctrl.Result{}, nil
 	}
 
+	ctx, span := tracing.StartReconcileSpan(ctx, r.Tracer, &policyServer, "reconcile policy server",
+		tracing.PolicyServerNameKey.String(policyServer.GetName()),
+	)
+	defer span.End()
+
 	policies, err := r.getPolicies(ctx, &policyServer)
 	if err != nil {
 		return ctrl.Result{}, errors.Join(errors.New("could not get policies"), err)
@@ -98,127 +186,184 @@ func (r *PolicyServerReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return r.reconcileDeletion(ctx, &policyServer, policies)
 	}
 
-	err = r.reconcilePolicyServerCertSecret(ctx, &policyServer)
-	if err != nil {
-		return ctrl.Result{}, err
+	if isReconciliationPaused(&policyServer) {
+		// The policy server resources are left untouched, only the status
+		// reports the reconciliation is paused.
+		apimeta.SetStatusCondition(&policyServer.Status.Conditions, reconciliationPausedCondition(string(policiesv1.PolicyServerReconciliationPaused)))
+		if err = r.Client.Status().Update(ctx, &policyServer); err != nil {
+			return ctrl.Result{}, fmt.Errorf("update policy server status error: %w", err)
+		}
+		return ctrl.Result{}, nil
 	}
+	apimeta.RemoveStatusCondition(&policyServer.Status.Conditions, string(policiesv1.PolicyServerReconciliationPaused))
+
+	summary := newPolicyServerReconcileSummary()
+	defer func() {
+		r.Log.Info("PolicyServer reconciliation summary", summary.keysAndValues(policyServer.Name)...)
+	}()
+	defer func() {
+		// The conflicts are transient, the reconciliation is retried with
+		// the latest version of the objects.
+		if err != nil && !apierrors.IsConflict(err) {
+			r.recordEvent(&policyServer, corev1.EventTypeWarning, policyServerReconcileFailedReason, "Reconciliation failed: %v", err)
+		}
+	}()
 
-	if err = r.reconcilePolicyServerConfigMap(ctx, &policyServer, policies); err != nil {
-		setFalseConditionType(
-			&policyServer.Status.Conditions,
-			string(policiesv1.PolicyServerConfigMapReconciled),
-			fmt.Sprintf("error reconciling configmap: %v", err),
-		)
-		return ctrl.Result{}, err
+	// The status is updated even when some resources failed to reconcile,
+	// so that the condition of each of them reports its own error.
+	reconcileErr := r.reconcilePolicyServerResources(ctx, &policyServer, policies, summary)
+	policyServer.Status.Policies = summarizePoliciesStatus(policies)
+	policyServer.Status.RecommendedRequests = policiesv1.AggregateResourceHints(policies)
+
+	if err = r.Client.Status().Update(ctx, &policyServer); err != nil {
+		return ctrl.Result{}, errors.Join(reconcileErr, fmt.Errorf("update policy server status error: %w", err))
 	}
 
-	setTrueConditionType(
-		&policyServer.Status.Conditions,
-		string(policiesv1.PolicyServerConfigMapReconciled),
-	)
+	if reconcileErr != nil {
+		return ctrl.Result{}, reconcileErr
+	}
 
-	if err = r.reconcilePolicyServerPodDisruptionBudget(ctx, &policyServer); err != nil {
-		setFalseConditionType(
-			&policyServer.Status.Conditions,
-			string(policiesv1.PolicyServerPodDisruptionBudgetReconciled),
-			fmt.Sprintf("error reconciling policy server PodDisruptionBudget: %v", err),
-		)
+	if err = r.recordPolicyServerReplicas(ctx, &policyServer); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	setTrueConditionType(
-		&policyServer.Status.Conditions,
-		string(policiesv1.PolicyServerPodDisruptionBudgetReconciled),
-	)
+	if err = r.recordPolicyServerRestarts(ctx, &policyServer); err != nil {
+		return ctrl.Result{}, err
+	}
 
-	if err = r.reconcilePolicyServerDeployment(ctx, &policyServer); err != nil {
+	return ctrl.Result{}, nil
+}
+
+// reconcilePolicyServerResources reconciles all the resources of the policy
+// server, setting the condition of each of them. A failing resource does not
+// prevent the others from being reconciled, the errors are aggregated. The
+// exception is the Deployment, which is skipped when the certificate secret
+// or the ConfigMap its pods mount failed.
+func (r *PolicyServerReconciler) reconcilePolicyServerResources(
+	ctx context.Context,
+	policyServer *policiesv1.PolicyServer,
+	policies []policiesv1.Policy,
+	summary *policyServerReconcileSummary,
+) error {
+	var errs []error
+
+	certSecretErr := r.reconcilePolicyServerCertSecret(ctx, policyServer)
+	summary.certSecret = subReconcileOutcomeFromError(certSecretErr)
+	errs = append(errs, certSecretErr)
+
+	configMapErr := r.reconcileWithCondition(policyServer, policiesv1.PolicyServerConfigMapReconciled, "error reconciling configmap", &summary.configMap, func() error {
+		return r.reconcilePolicyServerConfigMap(ctx, policyServer, policies)
+	})
+	errs = append(errs, configMapErr)
+
+	errs = append(errs, r.reconcileWithCondition(policyServer, policiesv1.PolicyServerPodDisruptionBudgetReconciled, "error reconciling policy server PodDisruptionBudget", &summary.podDisruptionBudget, func() error {
+		return r.reconcilePolicyServerPodDisruptionBudget(ctx, policyServer)
+	}))
+
+	errs = append(errs, r.reconcileWithCondition(policyServer, policiesv1.PolicyServerServiceAccountReconciled, "error reconciling policy server ServiceAccount", &summary.serviceAccount, func() error {
+		return r.reconcilePolicyServerServiceAccount(ctx, policyServer, policies)
+	}))
+
+	var deploymentErr error
+	if certSecretErr != nil || configMapErr != nil {
+		// Rolling out the pods with a missing certificate or stale policies
+		// would break the running policy server. The error is already
+		// reported by the failed step.
+		deploymentErr = errors.New("the certificate secret or the configmap is not reconciled")
 		setFalseConditionType(
 			&policyServer.Status.Conditions,
 			string(policiesv1.PolicyServerDeploymentReconciled),
-			fmt.Sprintf("error reconciling deployment: %v", err),
+			"deployment not reconciled: "+deploymentErr.Error(),
 		)
-		return ctrl.Result{}, err
+	} else {
+		deploymentErr = r.reconcileWithCondition(policyServer, policiesv1.PolicyServerDeploymentReconciled, "error reconciling deployment", &summary.deployment, func() error {
+			return r.reconcilePolicyServerDeployment(ctx, policyServer, policies)
+		})
+		errs = append(errs, deploymentErr)
 	}
 
-	setTrueConditionType(
-		&policyServer.Status.Conditions,
-		string(policiesv1.PolicyServerDeploymentReconciled),
-	)
+	errs = append(errs, r.reconcileWithCondition(policyServer, policiesv1.PolicyServerServiceReconciled, "error reconciling service", &summary.service, func() error {
+		return r.reconcilePolicyServerService(ctx, policyServer)
+	}))
 
-	if err = r.reconcilePolicyServerService(ctx, &policyServer); err != nil {
+	err := r.reconcilePolicyServerNetworkPolicy(ctx, policyServer)
+	summary.networkPolicy = subReconcileOutcomeFromError(err)
+	errs = append(errs, err)
+
+	if r.serviceMonitorEnabled() {
+		err = r.reconcilePolicyServerServiceMonitor(ctx, policyServer)
+		summary.serviceMonitor = subReconcileOutcomeFromError(err)
+		errs = append(errs, err)
+	} else {
+		errs = append(errs, r.deletePolicyServerServiceMonitor(ctx, policyServer))
+	}
+
+	if r.podMonitorEnabled() {
+		err = r.reconcilePolicyServerPodMonitor(ctx, policyServer)
+		summary.podMonitor = subReconcileOutcomeFromError(err)
+		errs = append(errs, err)
+	}
+
+	// The observed state of the deployment is meaningful only when the
+	// deployment has been reconciled.
+	if deploymentErr == nil {
+		errs = append(errs, r.setPolicyServerObservedImages(ctx, policyServer))
+		errs = append(errs, r.setPolicyServerDeploymentProgressing(ctx, policyServer))
+		errs = append(errs, r.setPolicyServerPodsSchedulable(ctx, policyServer))
+	}
+
+	return errors.Join(errs...)
+}
+
+// reconcileWithCondition runs the given sub-reconcile, records its outcome and
+// sets the condition of the PolicyServer according to the result.
+func (r *PolicyServerReconciler) reconcileWithCondition(
+	policyServer *policiesv1.PolicyServer,
+	conditionType policiesv1.PolicyServerConditionType,
+	errorMessage string,
+	outcome *subReconcileOutcome,
+	subReconcile func() error,
+) error {
+	err := subReconcile()
+	*outcome = subReconcileOutcomeFromError(err)
+	if err != nil {
 		setFalseConditionType(
 			&policyServer.Status.Conditions,
-			string(policiesv1.PolicyServerServiceReconciled),
-			fmt.Sprintf("error reconciling service: %v", err),
+			string(conditionType),
+			fmt.Sprintf("%s: %v", errorMessage, err),
 		)
-		return ctrl.Result{}, err
+		return err
 	}
 
 	setTrueConditionType(
 		&policyServer.Status.Conditions,
-		string(policiesv1.PolicyServerServiceReconciled),
+		string(conditionType),
 	)
-
-	if err = r.Client.Status().Update(ctx, &policyServer); err != nil {
-		return ctrl.Result{}, fmt.Errorf("update policy server status error: %w", err)
-	}
-
-	return ctrl.Result{}, nil
+	return nil
 }
 
-// SetupWithManager sets up the controller with the Manager.
+// SetupWithManager sets up the controller with the Manager. The policies and
+// the policy servers are listed through the field indexes registered by
+// SetupPolicyServerIndexes.
 func (r *PolicyServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	err := mgr.GetFieldIndexer().IndexField(context.Background(), &policiesv1.ClusterAdmissionPolicy{}, constants.PolicyServerIndexKey, func(object client.Object) []string {
-		policy, ok := object.(*policiesv1.ClusterAdmissionPolicy)
-		if !ok {
-			r.Log.Error(nil, "object is not type of ClusterAdmissionPolicy: %#v", "policy", policy)
-			return []string{}
-		}
-		return []string{policy.Spec.PolicyServer}
-	})
-	if err != nil {
-		return fmt.Errorf("failed enrolling controller with manager: %w", err)
-	}
-	err = mgr.GetFieldIndexer().IndexField(context.Background(), &policiesv1.AdmissionPolicy{}, constants.PolicyServerIndexKey, func(object client.Object) []string {
-		policy, ok := object.(*policiesv1.AdmissionPolicy)
-		if !ok {
-			r.Log.Error(nil, "object is not type of AdmissionPolicy: %#v", "policy", policy)
-			return []string{}
-		}
-		return []string{policy.Spec.PolicyServer}
-	})
-	if err != nil {
-		return fmt.Errorf("failed enrolling controller with manager: %w", err)
-	}
-	err = mgr.GetFieldIndexer().IndexField(context.Background(), &policiesv1.AdmissionPolicyGroup{}, constants.PolicyServerIndexKey, func(object client.Object) []string {
-		policy, ok := object.(*policiesv1.AdmissionPolicyGroup)
-		if !ok {
-			r.Log.Error(nil, "object is not type of AdmissionPolicyGroup: %#v", "policy", policy)
-			return []string{}
-		}
-		return []string{policy.Spec.PolicyServer}
-	})
-	if err != nil {
-		return fmt.Errorf("failed enrolling controller with manager: %w", err)
-	}
-	err = mgr.GetFieldIndexer().IndexField(context.Background(), &policiesv1.ClusterAdmissionPolicyGroup{}, constants.PolicyServerIndexKey, func(object client.Object) []string {
-		policy, ok := object.(*policiesv1.ClusterAdmissionPolicyGroup)
-		if !ok {
-			r.Log.Error(nil, "object is not type of ClusterAdmissionPolicyGroup: %#v", "policy", policy)
-			return []string{}
-		}
-		return []string{policy.Spec.PolicyServer}
-	})
-	if err != nil {
-		return fmt.Errorf("failed enrolling controller with manager: %w", err)
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(policyServerEventRecorderName)
 	}
 
-	err = ctrl.NewControllerManagedBy(mgr).
+	err := ctrl.NewControllerManagedBy(mgr).
 		For(&policiesv1.PolicyServer{}).
-		Watches(&policiesv1.AdmissionPolicy{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAdmissionPolicy)).
-		Watches(&policiesv1.AdmissionPolicyGroup{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAdmissionPolicyGroup)).
-		Watches(&policiesv1.ClusterAdmissionPolicy{}, handler.EnqueueRequestsFromMapFunc(r.enqueueClusterAdmissionPolicy)).
-		Watches(&policiesv1.ClusterAdmissionPolicyGroup{}, handler.EnqueueRequestsFromMapFunc(r.enqueueClusterAdmissionPolicyGroup)).
+		Watches(&policiesv1.AdmissionPolicy{}, debouncedEnqueueRequestsFromMapFunc(r.enqueueAdmissionPolicy, r.PolicyChangesDebounce)).
+		Watches(&policiesv1.AdmissionPolicyGroup{}, debouncedEnqueueRequestsFromMapFunc(r.enqueueAdmissionPolicyGroup, r.PolicyChangesDebounce)).
+		Watches(&policiesv1.ClusterAdmissionPolicy{}, debouncedEnqueueRequestsFromMapFunc(r.enqueueClusterAdmissionPolicy, r.PolicyChangesDebounce)).
+		Watches(&policiesv1.ClusterAdmissionPolicyGroup{}, debouncedEnqueueRequestsFromMapFunc(r.enqueueClusterAdmissionPolicyGroup, r.PolicyChangesDebounce)).
+		// Watch the policy server Deployments to keep the replicas metrics up to date
+		Watches(&appsv1.Deployment{}, handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &policiesv1.PolicyServer{})).
+		// Watch the policy server Pods to keep the PodsSchedulable condition up to date
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.enqueuePolicyServerPod), builder.WithPredicates(policyServerPodStatusChangedPredicate())).
+		// Watch the referenced secrets to refresh the merged image pull secrets
+		// and to validate the externally managed certificates
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.enqueueSecretPolicyServers)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 	if err != nil {
 		return errors.Join(errors.New("failed enrolling controller with manager"), err)
@@ -306,9 +451,96 @@ func (r *PolicyServerReconciler) enqueueClusterAdmissionPolicyGroup(_ context.Co
 	}
 }
 
+// enqueuePolicyServerPod enqueues the PolicyServer of the given pod, found
+// through its PolicyServerLabelKey label. The pods that do not belong to a
+// policy server are ignored.
+func (r *PolicyServerReconciler) enqueuePolicyServerPod(_ context.Context, object client.Object) []reconcile.Request {
+	policyServerName, ok := object.GetLabels()[constants.PolicyServerLabelKey]
+	if !ok || object.GetNamespace() != r.DeploymentsNamespace {
+		return []ctrl.Request{}
+	}
+
+	return []ctrl.Request{
+		{
+			NamespacedName: client.ObjectKey{
+				Name: policyServerName,
+			},
+		},
+	}
+}
+
+// policyServerPodStatusChangedPredicate filters the pod updates down to the
+// phase and readiness transitions. The PodScheduled condition is compared too,
+// because the PodsSchedulable condition reports the scheduler message of the
+// pending pods.
+func policyServerPodStatusChangedPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, ok := e.ObjectOld.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			newPod, ok := e.ObjectNew.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+
+			if oldPod.Status.Phase != newPod.Status.Phase {
+				return true
+			}
+			for _, conditionType := range []corev1.PodConditionType{corev1.PodReady, corev1.PodScheduled} {
+				if podConditionChanged(oldPod, newPod, conditionType) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// podConditionChanged reports whether the status, reason or message of the
+// given condition differ between the two pods.
+func podConditionChanged(oldPod, newPod *corev1.Pod, conditionType corev1.PodConditionType) bool {
+	oldCondition := findPodCondition(oldPod, conditionType)
+	newCondition := findPodCondition(newPod, conditionType)
+	if oldCondition == nil || newCondition == nil {
+		return oldCondition != newCondition
+	}
+	return oldCondition.Status != newCondition.Status ||
+		oldCondition.Reason != newCondition.Reason ||
+		oldCondition.Message != newCondition.Message
+}
+
+// findPodCondition returns the condition of the given type of the pod, or nil.
+func findPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == conditionType {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// enqueueSecretPolicyServers enqueues the policy servers referencing the given
+// secret.
+func (r *PolicyServerReconciler) enqueueSecretPolicyServers(ctx context.Context, object client.Object) []reconcile.Request {
+	policyServers, err := findPolicyServersForSecret(ctx, r.Client, r.DeploymentsNamespace, object)
+	if err != nil {
+		r.Log.Error(err, "cannot enqueue the policy servers of the secret", "secret", object.GetName())
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0, len(policyServers))
+	for _, policyServer := range policyServers {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKey{Name: policyServer.Name}})
+	}
+	return requests
+}
+
 // getPolicies returns all admission policies, cluster admission policy,
 // admission policies groups and cluster admission policy groups bound to the
-// given policyServer.
+// given policyServer. The admission policies outside of the namespace
+// allow-list are skipped.
 func (r *PolicyServerReconciler) getPolicies(ctx context.Context, policyServer *policiesv1.PolicyServer) ([]policiesv1.Policy, error) {
 	var clusterAdmissionPolicies policiesv1.ClusterAdmissionPolicyList
 	err := r.Client.List(ctx, &clusterAdmissionPolicies, client.MatchingFields{constants.PolicyServerIndexKey: policyServer.Name})
@@ -342,6 +574,9 @@ func (r *PolicyServerReconciler) getPolicies(ctx context.Context, policyServer *
 		policies = append(policies, clusterAdmissionPolicy.DeepCopy())
 	}
 	for _, admissionPolicy := range admissionPolicies.Items {
+		if !isNamespaceAllowed(r.NamespaceAllowList, admissionPolicy.GetNamespace()) {
+			continue
+		}
 		policies = append(policies, admissionPolicy.DeepCopy())
 	}
 	for _, admissionPolicyGroup := range admissionPolicyGroupList.Items {
@@ -353,6 +588,28 @@ func (r *PolicyServerReconciler) getPolicies(ctx context.Context, policyServer *
 	return policies, nil
 }
 
+// summarizePoliciesStatus counts the given policies, bound to a policy
+// server, in each status.
+func summarizePoliciesStatus(policies []policiesv1.Policy) *policiesv1.PolicyServerPoliciesSummary {
+	summary := &policiesv1.PolicyServerPoliciesSummary{Total: len(policies)}
+	for _, policy := range policies {
+		switch policy.GetStatus().PolicyStatus {
+		case policiesv1.PolicyStatusActive:
+			summary.Active++
+		case policiesv1.PolicyStatusPending:
+			summary.Pending++
+		case policiesv1.PolicyStatusScheduled:
+			summary.Scheduled++
+		case policiesv1.PolicyStatusUnscheduled:
+			// The policies bound to a policy server are never unscheduled.
+			// The policies not reconciled yet, without status, are counted
+			// in the total only.
+		}
+	}
+
+	return summary
+}
+
 func (r *PolicyServerReconciler) reconcileDeletion(ctx context.Context, policyServer *policiesv1.PolicyServer, policies []policiesv1.Policy) (ctrl.Result, error) {
 	if len(policies) != 0 {
 		// There are still policies scheduled on the PolicyServer, we have to
@@ -360,6 +617,15 @@ func (r *PolicyServerReconciler) reconcileDeletion(ctx context.Context, policySe
 		return r.deletePoliciesAndRequeue(ctx, policyServer, policies)
 	}
 
+	// The secret is owned by the policy server, but it is deleted here as
+	// well so that it does not outlive the policy server when the garbage
+	// collection of the dependents is delayed or orphaned.
+	if err := r.deletePolicyServerCertSecret(ctx, policyServer); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.podRestarts.forget(policyServer.Name)
+
 	// Remove the old finalizer used to ensure that the policy server created
 	// before this controller version is delete as well. As the upgrade path
 	// supported by the Kubewarden project does not allow jumping versions, we
@@ -397,6 +663,67 @@ func (r *PolicyServerReconciler) deletePoliciesAndRequeue(ctx context.Context, p
 	return ctrl.Result{Requeue: true}, nil
 }
 
+type subReconcileOutcome string
+
+const (
+	subReconcileSucceeded subReconcileOutcome = "succeeded"
+	subReconcileFailed    subReconcileOutcome = "failed"
+	subReconcileSkipped   subReconcileOutcome = "skipped"
+)
+
+func subReconcileOutcomeFromError(err error) subReconcileOutcome {
+	if err != nil {
+		return subReconcileFailed
+	}
+	return subReconcileSucceeded
+}
+
+// policyServerReconcileSummary tracks the outcome of each sub-reconcile of a
+// PolicyServer reconciliation. It is used to log a single summary line at the
+// end of each reconciliation. The sub-reconciles not reached because of a
+// previous failure are reported as skipped.
+type policyServerReconcileSummary struct {
+	certSecret          subReconcileOutcome
+	configMap           subReconcileOutcome
+	podDisruptionBudget subReconcileOutcome
+	serviceAccount      subReconcileOutcome
+	deployment          subReconcileOutcome
+	service             subReconcileOutcome
+	networkPolicy       subReconcileOutcome
+	serviceMonitor      subReconcileOutcome
+	podMonitor          subReconcileOutcome
+}
+
+func newPolicyServerReconcileSummary() *policyServerReconcileSummary {
+	return &policyServerReconcileSummary{
+		certSecret:          subReconcileSkipped,
+		configMap:           subReconcileSkipped,
+		podDisruptionBudget: subReconcileSkipped,
+		serviceAccount:      subReconcileSkipped,
+		deployment:          subReconcileSkipped,
+		service:             subReconcileSkipped,
+		networkPolicy:       subReconcileSkipped,
+		serviceMonitor:      subReconcileSkipped,
+		podMonitor:          subReconcileSkipped,
+	}
+}
+
+// keysAndValues returns the summary as structured logging key/value pairs.
+func (s *policyServerReconcileSummary) keysAndValues(policyServerName string) []any {
+	return []any{
+		"policy-server", policyServerName,
+		"certSecret", s.certSecret,
+		"configMap", s.configMap,
+		"podDisruptionBudget", s.podDisruptionBudget,
+		"serviceAccount", s.serviceAccount,
+		"deployment", s.deployment,
+		"service", s.service,
+		"networkPolicy", s.networkPolicy,
+		"serviceMonitor", s.serviceMonitor,
+		"podMonitor", s.podMonitor,
+	}
+}
+
 func setFalseConditionType(
 	conditions *[]metav1.Condition,
 	conditionType string,
@@ -425,5 +752,5 @@ func setTrueConditionType(conditions *[]metav1.Condition, conditionType string)
 }
 
 func policyServerDeploymentName(policyServerName string) string {
-	return "policy-server-" + policyServerName
+	return constants.PolicyServerNamePrefix + policyServerName
 }
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+// zoneAntiAffinityWeight is the weight of the default pod anti-affinity
+// spreading the policy server replicas across the availability zones.
+const zoneAntiAffinityWeight = 100
+
+// configureZoneAntiAffinity adds a preferred pod anti-affinity spreading the
+// policy server replicas across the availability zones, when enabled and the
+// nodes of the cluster are labeled with their zone. The anti-affinity is only
+// added to policy servers with more replicas and without a pod anti-affinity
+// set by the user.
+func (r *PolicyServerReconciler) configureZoneAntiAffinity(ctx context.Context, policyServerDeployment *appsv1.Deployment, policyServer *policiesv1.PolicyServer) error {
+	if !r.ZoneAntiAffinity || policyServer.Spec.Replicas <= 1 || policyServer.Spec.Affinity.PodAntiAffinity != nil {
+		return nil
+	}
+
+	zoned, err := r.hasZonedNodes(ctx)
+	if err != nil {
+		return err
+	}
+	if !zoned {
+		return nil
+	}
+
+	affinity := policyServer.Spec.Affinity.DeepCopy()
+	affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+			{
+				Weight: zoneAntiAffinityWeight,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							constants.PolicyServerLabelKey: policyServer.Name,
+						},
+					},
+					TopologyKey: corev1.LabelTopologyZone,
+				},
+			},
+		},
+	}
+	policyServerDeployment.Spec.Template.Spec.Affinity = affinity
+
+	return nil
+}
+
+// hasZonedNodes returns true when at least a node of the cluster is labeled
+// with its availability zone.
+func (r *PolicyServerReconciler) hasZonedNodes(ctx context.Context) (bool, error) {
+	nodes := &metav1.PartialObjectMetadataList{}
+	nodes.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("NodeList"))
+	if err := r.Client.List(ctx, nodes, client.HasLabels{corev1.LabelTopologyZone}, client.Limit(1)); err != nil {
+		return false, fmt.Errorf("cannot list the nodes: %w", err)
+	}
+
+	return len(nodes.Items) > 0, nil
+}
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+var _ = Describe("Policy server zone anti-affinity", func() {
+	ctx := context.Background()
+
+	zonedNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "zoned-node",
+			Labels: map[string]string{corev1.LabelTopologyZone: "zone-a"},
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node"},
+	}
+
+	newReconciler := func(nodes ...*corev1.Node) *PolicyServerReconciler {
+		testScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(testScheme)).To(Succeed())
+		Expect(policiesv1.AddToScheme(testScheme)).To(Succeed())
+
+		clientBuilder := fake.NewClientBuilder().WithScheme(testScheme)
+		for _, node := range nodes {
+			clientBuilder = clientBuilder.WithObjects(node.DeepCopy())
+		}
+
+		return &PolicyServerReconciler{
+			Client:           clientBuilder.Build(),
+			ZoneAntiAffinity: true,
+		}
+	}
+
+	newPolicyServer := func(replicas int32) *policiesv1.PolicyServer {
+		policyServer := policiesv1.NewPolicyServerFactory().WithName("default").Build()
+		policyServer.Spec.Replicas = replicas
+		return policyServer
+	}
+
+	configure := func(reconciler *PolicyServerReconciler, policyServer *policiesv1.PolicyServer) *corev1.Affinity {
+		deployment := &appsv1.Deployment{}
+		deployment.Spec.Template.Spec.Affinity = &policyServer.Spec.Affinity
+		Expect(reconciler.configureZoneAntiAffinity(ctx, deployment, policyServer)).To(Succeed())
+		return deployment.Spec.Template.Spec.Affinity
+	}
+
+	It("should spread the replicas across the zones", func() {
+		policyServer := newPolicyServer(3)
+		policyServer.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "kubernetes.io/os", Operator: corev1.NodeSelectorOpIn, Values: []string{"linux"}}}},
+				},
+			},
+		}
+
+		affinity := configure(newReconciler(node, zonedNode), policyServer)
+
+		Expect(affinity.NodeAffinity).To(Equal(policyServer.Spec.Affinity.NodeAffinity))
+		Expect(affinity.PodAntiAffinity).To(Equal(&corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: zoneAntiAffinityWeight,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{constants.PolicyServerLabelKey: "default"},
+						},
+						TopologyKey: corev1.LabelTopologyZone,
+					},
+				},
+			},
+		}))
+		Expect(policyServer.Spec.Affinity.PodAntiAffinity).To(BeNil())
+	})
+
+	It("should not change the affinity when the nodes are not labeled with their zone", func() {
+		Expect(configure(newReconciler(node), newPolicyServer(3)).PodAntiAffinity).To(BeNil())
+	})
+
+	It("should not change the affinity of a policy server with a single replica", func() {
+		Expect(configure(newReconciler(zonedNode), newPolicyServer(1)).PodAntiAffinity).To(BeNil())
+	})
+
+	It("should not change the affinity when disabled", func() {
+		reconciler := newReconciler(zonedNode)
+		reconciler.ZoneAntiAffinity = false
+
+		Expect(configure(reconciler, newPolicyServer(3)).PodAntiAffinity).To(BeNil())
+	})
+
+	It("should keep the pod anti-affinity set by the user", func() {
+		policyServer := newPolicyServer(3)
+		policyServer.Spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: 10,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						TopologyKey: corev1.LabelHostname,
+					},
+				},
+			},
+		}
+
+		Expect(configure(newReconciler(zonedNode), policyServer).PodAntiAffinity).To(Equal(policyServer.Spec.Affinity.PodAntiAffinity))
+	})
+})
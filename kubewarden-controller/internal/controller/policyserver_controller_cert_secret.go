@@ -7,6 +7,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -20,6 +21,10 @@ import (
 // generated certificate is signed by the CA certificate provided in the
 // caSecret. The generated certificate is stored in a secret.
 func (r *PolicyServerReconciler) reconcilePolicyServerCertSecret(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	if policyServer.Spec.CertificateSecret != "" {
+		return r.reconcilePolicyServerExternalCertSecret(ctx, policyServer)
+	}
+
 	caSecret := &corev1.Secret{}
 
 	err := r.Client.Get(ctx, types.NamespacedName{Name: constants.CARootSecretName, Namespace: r.DeploymentsNamespace}, caSecret)
@@ -34,7 +39,7 @@ func (r *PolicyServerReconciler) reconcilePolicyServerCertSecret(ctx context.Con
 		},
 	}
 
-	_, err = controllerutil.CreateOrPatch(ctx, r.Client, policyServerSecret, func() error {
+	result, err := controllerutil.CreateOrPatch(ctx, r.Client, policyServerSecret, func() error {
 		if err = controllerutil.SetOwnerReference(policyServer, policyServerSecret, r.Client.Scheme()); err != nil {
 			return errors.Join(errors.New("failed to set policy server secret owner reference"), err)
 		}
@@ -55,12 +60,17 @@ func (r *PolicyServerReconciler) reconcilePolicyServerCertSecret(ctx context.Con
 			}
 
 			var cert, privateKey []byte
-			cert, privateKey, err = certs.GenerateCert(
+			keyAlgorithm := r.CertKeyAlgorithm
+			if keyAlgorithm == "" {
+				keyAlgorithm = certs.DefaultKeyAlgorithm
+			}
+			cert, privateKey, err = certs.GenerateCertWithKeyAlgorithm(
 				caCert,
 				caPrivateKey,
 				time.Now(),
-				time.Now().Add(constants.ServerCertExpiration),
+				time.Now().Add(durationOrDefault(r.ServerCertDuration, constants.ServerCertExpiration)),
 				fmt.Sprintf("%s.%s.svc", policyServer.NameWithPrefix(), r.DeploymentsNamespace),
+				keyAlgorithm,
 			)
 			if err != nil {
 				return fmt.Errorf("cannot generate policy-server %s certificate: %w", policyServer.NameWithPrefix(), err)
@@ -88,6 +98,75 @@ func (r *PolicyServerReconciler) reconcilePolicyServerCertSecret(ctx context.Con
 		&policyServer.Status.Conditions,
 		string(policiesv1.PolicyServerCertSecretReconciled),
 	)
+	r.recordOperationEvent(policyServer, result, "Secret", policyServerSecret.Name)
+
+	return nil
+}
+
+// reconcilePolicyServerExternalCertSecret validates the externally managed
+// secret holding the certificate of the policy server. The certificate
+// previously generated by the controller, if any, is deleted.
+func (r *PolicyServerReconciler) reconcilePolicyServerExternalCertSecret(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	err := r.validatePolicyServerExternalCertSecret(ctx, policyServer)
+	if err == nil {
+		err = r.deletePolicyServerCertSecret(ctx, policyServer)
+	}
+	if err != nil {
+		setFalseConditionType(
+			&policyServer.Status.Conditions,
+			string(policiesv1.PolicyServerCertSecretReconciled),
+			fmt.Sprintf("error reconciling secret: %v", err),
+		)
+		return err
+	}
+
+	setTrueConditionType(
+		&policyServer.Status.Conditions,
+		string(policiesv1.PolicyServerCertSecretReconciled),
+	)
+
+	return nil
+}
+
+// validatePolicyServerExternalCertSecret checks the externally managed secret
+// of the policy server holds a server certificate valid for the policy server
+// Service, its key and its CA.
+func (r *PolicyServerReconciler) validatePolicyServerExternalCertSecret(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	externalCertSecret := &corev1.Secret{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: policyServer.Spec.CertificateSecret, Namespace: r.DeploymentsNamespace}, externalCertSecret)
+	if err != nil {
+		return fmt.Errorf("failed to fetch policy server certificate secret: %w", err)
+	}
+
+	if _, err = certs.ExtractCABundleFromExternalCertSecret(externalCertSecret, certs.DNSName(policyServer.NameWithPrefix(), r.DeploymentsNamespace)); err != nil {
+		return fmt.Errorf("invalid policy server certificate secret: %w", err)
+	}
+
+	return nil
+}
+
+// policyServerCertSecretName returns the name of the secret holding the
+// certificate used by the policy server to serve TLS.
+func policyServerCertSecretName(policyServer *policiesv1.PolicyServer) string {
+	if policyServer.Spec.CertificateSecret != "" {
+		return policyServer.Spec.CertificateSecret
+	}
+
+	return policyServer.NameWithPrefix()
+}
+
+// deletePolicyServerCertSecret deletes the secret holding the certificate of
+// the policy server.
+func (r *PolicyServerReconciler) deletePolicyServerCertSecret(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	policyServerSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: r.DeploymentsNamespace,
+			Name:      policyServer.NameWithPrefix(),
+		},
+	}
+	if err := r.Client.Delete(ctx, policyServerSecret); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Join(errors.New("cannot delete policy server cert secret"), err)
+	}
 
 	return nil
 }
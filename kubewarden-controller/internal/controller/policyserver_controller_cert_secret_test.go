@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/certs"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+var _ = Describe("Policy server cert secret deletion", func() {
+	ctx := context.Background()
+
+	It("should delete the cert secret when the policy server is deleted", func() {
+		testScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(testScheme)).To(Succeed())
+		Expect(policiesv1.AddToScheme(testScheme)).To(Succeed())
+
+		policyServer := policiesv1.NewPolicyServerFactory().WithName("deleted").Build()
+		policyServer.Finalizers = []string{constants.KubewardenFinalizer}
+		policyServerSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: deploymentsNamespace,
+				Name:      policyServer.NameWithPrefix(),
+			},
+		}
+
+		reconciler := &PolicyServerReconciler{
+			Client:               fake.NewClientBuilder().WithScheme(testScheme).WithObjects(policyServer, policyServerSecret).Build(),
+			DeploymentsNamespace: deploymentsNamespace,
+		}
+
+		Expect(reconciler.Delete(ctx, policyServer)).To(Succeed())
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(policyServer), policyServer)).To(Succeed())
+
+		_, err := reconciler.reconcileDeletion(ctx, policyServer, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = reconciler.Get(ctx, client.ObjectKeyFromObject(policyServerSecret), &corev1.Secret{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		err = reconciler.Get(ctx, client.ObjectKeyFromObject(policyServer), &policiesv1.PolicyServer{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Policy server externally managed cert secret", func() {
+	ctx := context.Background()
+	const externalCertSecretName = "external-cert"
+
+	newReconciler := func(externalCertSecretData map[string][]byte) *PolicyServerReconciler {
+		testScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(testScheme)).To(Succeed())
+		Expect(policiesv1.AddToScheme(testScheme)).To(Succeed())
+
+		externalCertSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: deploymentsNamespace,
+				Name:      externalCertSecretName,
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: externalCertSecretData,
+		}
+
+		return &PolicyServerReconciler{
+			Client:               fake.NewClientBuilder().WithScheme(testScheme).WithObjects(externalCertSecret).Build(),
+			DeploymentsNamespace: deploymentsNamespace,
+		}
+	}
+
+	newExternalCertSecretData := func() map[string][]byte {
+		caCert, caPrivateKey, err := certs.GenerateCA(time.Now(), time.Now().Add(constants.CACertExpiration))
+		Expect(err).ToNot(HaveOccurred())
+		cert, privateKey, err := certs.GenerateCert(caCert, caPrivateKey, time.Now(), time.Now().Add(constants.ServerCertExpiration), certs.DNSName("policy-server-external", deploymentsNamespace))
+		Expect(err).ToNot(HaveOccurred())
+
+		return map[string][]byte{
+			constants.ServerCert:       cert,
+			constants.ServerPrivateKey: privateKey,
+			constants.CARootCert:       caCert,
+		}
+	}
+
+	newPolicyServer := func() *policiesv1.PolicyServer {
+		policyServer := policiesv1.NewPolicyServerFactory().WithName("external").Build()
+		policyServer.Spec.CertificateSecret = externalCertSecretName
+		return policyServer
+	}
+
+	It("should use the externally managed secret without generating a certificate", func() {
+		reconciler := newReconciler(newExternalCertSecretData())
+		policyServer := newPolicyServer()
+
+		Expect(reconciler.reconcilePolicyServerCertSecret(ctx, policyServer)).To(Succeed())
+
+		Expect(apimeta.IsStatusConditionTrue(policyServer.Status.Conditions, string(policiesv1.PolicyServerCertSecretReconciled))).To(BeTrue())
+		err := reconciler.Get(ctx, types.NamespacedName{Namespace: deploymentsNamespace, Name: policyServer.NameWithPrefix()}, &corev1.Secret{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(policyServerCertSecretName(policyServer)).To(Equal(externalCertSecretName))
+	})
+
+	It("should reject the externally managed secret without CA certificate", func() {
+		externalCertSecretData := newExternalCertSecretData()
+		delete(externalCertSecretData, constants.CARootCert)
+		reconciler := newReconciler(externalCertSecretData)
+		policyServer := newPolicyServer()
+
+		Expect(reconciler.reconcilePolicyServerCertSecret(ctx, policyServer)).To(MatchError(ContainSubstring("CA could not be extracted from secret: external-cert")))
+
+		Expect(apimeta.IsStatusConditionFalse(policyServer.Status.Conditions, string(policiesv1.PolicyServerCertSecretReconciled))).To(BeTrue())
+	})
+
+	It("should reject the externally managed secret whose certificate is not valid for the policy server Service", func() {
+		externalCertSecretData := newExternalCertSecretData()
+		policyServer := policiesv1.NewPolicyServerFactory().WithName("other").Build()
+		policyServer.Spec.CertificateSecret = externalCertSecretName
+		reconciler := newReconciler(externalCertSecretData)
+
+		Expect(reconciler.reconcilePolicyServerCertSecret(ctx, policyServer)).To(MatchError(ContainSubstring("invalid server certificate in secret external-cert")))
+	})
+
+	It("should reject the externally managed secret without private key", func() {
+		externalCertSecretData := newExternalCertSecretData()
+		delete(externalCertSecretData, constants.ServerPrivateKey)
+		reconciler := newReconciler(externalCertSecretData)
+
+		Expect(reconciler.reconcilePolicyServerCertSecret(ctx, newPolicyServer())).To(MatchError(ContainSubstring("server private key could not be extracted from secret: external-cert")))
+	})
+})
@@ -2,9 +2,13 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -160,13 +164,15 @@ func (r *PolicyServerReconciler) updateConfigMapData(cfg *corev1.ConfigMap, poli
 	cfg.ObjectMeta.Labels = map[string]string{
 		constants.PolicyServerLabelKey: policyServer.ObjectMeta.Name,
 	}
-	if err = controllerutil.SetOwnerReference(policyServer, cfg, r.Client.Scheme()); err != nil {
+	if err = setPolicyServerOwnerReference(policyServer, cfg, r.Client.Scheme()); err != nil {
 		return errors.Join(errors.New("failed to set policy server configmap owner reference"), err)
 	}
 	return nil
 }
 
-func (r *PolicyServerReconciler) policyServerConfigMapVersion(ctx context.Context, policyServer *policiesv1.PolicyServer) (string, error) {
+// policyServerConfigMapVersion returns the resource version and the checksum
+// of the content of the policy server ConfigMap.
+func (r *PolicyServerReconciler) policyServerConfigMapVersion(ctx context.Context, policyServer *policiesv1.PolicyServer) (string, string, error) {
 	// By using Unstructured data we force the client to fetch fresh, uncached
 	// data from the API server
 	unstructuredObj := &unstructured.Unstructured{}
@@ -179,10 +185,28 @@ func (r *PolicyServerReconciler) policyServerConfigMapVersion(ctx context.Contex
 		Name:      policyServer.NameWithPrefix(),
 	}, unstructuredObj)
 	if err != nil {
-		return "", fmt.Errorf("cannot retrieve existing policies ConfigMap: %w", err)
+		return "", "", fmt.Errorf("cannot retrieve existing policies ConfigMap: %w", err)
 	}
 
-	return unstructuredObj.GetResourceVersion(), nil
+	data, _, err := unstructured.NestedStringMap(unstructuredObj.Object, "data")
+	if err != nil {
+		return "", "", fmt.Errorf("cannot read policies ConfigMap data: %w", err)
+	}
+
+	return unstructuredObj.GetResourceVersion(), configMapChecksum(data), nil
+}
+
+// configMapChecksum returns the SHA-256 checksum of the ConfigMap data. The
+// entries are sorted by key, so the checksum changes only when the content
+// of the ConfigMap changes.
+func configMapChecksum(data map[string]string) string {
+	hash := sha256.New()
+	for _, key := range slices.Sorted(maps.Keys(data)) {
+		// The lengths prevent different entries from producing the same
+		// stream of bytes.
+		fmt.Fprintf(hash, "%d:%s%d:%s", len(key), key, len(data[key]), data[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
 }
 
 func buildPolicyGroupMembersWithContext(policies policiesv1.PolicyGroupMembersWithContext) map[string]policyGroupMemberWithContext {
@@ -191,7 +215,7 @@ func buildPolicyGroupMembersWithContext(policies policiesv1.PolicyGroupMembersWi
 		policyGroupMembers[name] = policyGroupMemberWithContext{
 			Module:                policy.Module,
 			Settings:              policy.Settings,
-			ContextAwareResources: policy.ContextAwareResources,
+			ContextAwareResources: policiesv1.UniqueContextAwareResources(policy.ContextAwareResources),
 		}
 	}
 	return policyGroupMembers
@@ -205,7 +229,6 @@ func buildPoliciesMap(admissionPolicies []policiesv1.Policy) policyConfigEntryMa
 				Namespace: admissionPolicy.GetNamespace(),
 				Name:      admissionPolicy.GetName(),
 			},
-			Module:                admissionPolicy.GetModule(),
 			PolicyMode:            string(admissionPolicy.GetPolicyMode()),
 			AllowedToMutate:       admissionPolicy.IsMutating(),
 			Settings:              admissionPolicy.GetSettings(),
@@ -213,9 +236,13 @@ func buildPoliciesMap(admissionPolicies []policiesv1.Policy) policyConfigEntryMa
 			Message:               admissionPolicy.GetMessage(),
 		}
 
+		// The policy server loads the modules of the group members, the
+		// group itself has no module.
 		if policyGroup, ok := admissionPolicy.(policiesv1.PolicyGroup); ok {
 			configEntry.Policies = buildPolicyGroupMembersWithContext(policyGroup.GetPolicyGroupMembersWithContext())
 			configEntry.Expression = policyGroup.GetExpression()
+		} else {
+			configEntry.Module = admissionPolicy.GetModule()
 		}
 
 		policies[admissionPolicy.GetUniqueName()] = configEntry
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// debouncedEnqueueRequestsFromMapFunc returns an event handler enqueueing
+// the requests returned by mapFunc after the given window. The workqueue
+// keeps the earliest time of a request already waiting to be added, so all
+// the events mapped to the same request within the window are coalesced in a
+// single reconciliation. Applying many policies at once results in a single
+// update of the policy server ConfigMap, and thus a single reload of the
+// policy server, instead of one per policy. The requests are enqueued
+// immediately when the window is not positive.
+func debouncedEnqueueRequestsFromMapFunc(mapFunc handler.MapFunc, window time.Duration) handler.EventHandler {
+	if window <= 0 {
+		return handler.EnqueueRequestsFromMapFunc(mapFunc)
+	}
+
+	enqueue := func(ctx context.Context, object client.Object, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		if object == nil {
+			return
+		}
+		for _, request := range mapFunc(ctx, object) {
+			queue.AddAfter(request, window)
+		}
+	}
+
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, e.Object, queue)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			// The policy can be moved to another policy server, both of
+			// them must be reconciled.
+			enqueue(ctx, e.ObjectOld, queue)
+			enqueue(ctx, e.ObjectNew, queue)
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, e.Object, queue)
+		},
+		GenericFunc: func(ctx context.Context, e event.GenericEvent, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, e.Object, queue)
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+)
+
+var _ = Describe("Policy changes debounce", func() {
+	ctx := context.Background()
+
+	var queue workqueue.TypedRateLimitingInterface[reconcile.Request]
+
+	mapToPolicyServer := func(_ context.Context, object client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: object.(policiesv1.Policy).GetPolicyServer()}}}
+	}
+
+	newPolicy := func(name, policyServer string) *policiesv1.ClusterAdmissionPolicy {
+		return policiesv1.NewClusterAdmissionPolicyFactory().WithName(name).WithPolicyServer(policyServer).Build()
+	}
+
+	BeforeEach(func() {
+		queue = workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		DeferCleanup(queue.ShutDown)
+	})
+
+	It("should coalesce the changes of the policies of the same policy server", func() {
+		eventHandler := debouncedEnqueueRequestsFromMapFunc(mapToPolicyServer, 100*time.Millisecond)
+
+		for _, name := range []string{"policy-1", "policy-2", "policy-3"} {
+			eventHandler.Create(ctx, event.CreateEvent{Object: newPolicy(name, "default")}, queue)
+		}
+		eventHandler.Update(ctx, event.UpdateEvent{ObjectOld: newPolicy("policy-1", "default"), ObjectNew: newPolicy("policy-1", "other")}, queue)
+		Expect(queue.Len()).To(Equal(0))
+
+		Eventually(queue.Len).Should(Equal(2))
+		Consistently(queue.Len, 200*time.Millisecond).Should(Equal(2))
+	})
+
+	It("should enqueue the changes immediately without a window", func() {
+		eventHandler := debouncedEnqueueRequestsFromMapFunc(mapToPolicyServer, 0)
+
+		eventHandler.Delete(ctx, event.DeleteEvent{Object: newPolicy("policy-1", "default")}, queue)
+
+		Expect(queue.Len()).To(Equal(1))
+	})
+})
@@ -1,22 +1,30 @@
 package controller
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
+	"github.com/kubewarden/kubewarden-controller/internal/metrics"
 )
 
 const (
@@ -41,14 +49,27 @@ const (
 	otelClientCertificateVolumeName  = "otel-collector-client-certificate"
 	otelCertificateVolumeName        = "otel-collector-certificate"
 	defaultOtelCertificateMountMode  = 420
+	serviceAccountTokenVolumeName    = "kube-api-access"
+	serviceAccountTokenVolumePath    = "/var/run/secrets/kubernetes.io/serviceaccount"
+	kubeRootCAConfigMapName          = "kube-root-ca.crt"
+	projectedTokenVolumeName         = "projected-token"
+	projectedTokenVolumePath         = "/var/run/secrets/tokens"
+	tracesSamplerEnvVar              = "OTEL_TRACES_SAMPLER"
+	tracesSamplerArgEnvVar           = "OTEL_TRACES_SAMPLER_ARG"
 )
 
 // reconcilePolicyServerDeployment reconciles the Deployment that runs the PolicyServer.
-func (r *PolicyServerReconciler) reconcilePolicyServerDeployment(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
-	configMapVersion, err := r.policyServerConfigMapVersion(ctx, policyServer)
+func (r *PolicyServerReconciler) reconcilePolicyServerDeployment(ctx context.Context, policyServer *policiesv1.PolicyServer, policies []policiesv1.Policy) error {
+	if err := r.reconcilePolicyServerImagePullSecret(ctx, policyServer); err != nil {
+		return err
+	}
+
+	configMapVersion, configMapChecksum, err := r.policyServerConfigMapVersion(ctx, policyServer)
 	if err != nil {
 		return fmt.Errorf("cannot get policy-server ConfigMap version: %w", err)
 	}
+	policyServer.Status.ConfigChecksum = configMapChecksum
+	automountServiceAccountToken := r.automountServiceAccountToken(policyServer, policies)
 
 	policyServerDeployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -56,18 +77,181 @@ func (r *PolicyServerReconciler) reconcilePolicyServerDeployment(ctx context.Con
 			Namespace: r.DeploymentsNamespace,
 		},
 	}
-	_, err = controllerutil.CreateOrPatch(ctx, r.Client, policyServerDeployment, func() error {
-		return r.updatePolicyServerDeployment(ctx, policyServer, policyServerDeployment, configMapVersion)
+	result, err := controllerutil.CreateOrPatch(ctx, r.Client, policyServerDeployment, func() error {
+		return r.updatePolicyServerDeployment(ctx, policyServer, policyServerDeployment, configMapVersion, configMapChecksum, automountServiceAccountToken)
 	})
 	if err != nil {
 		return fmt.Errorf("error reconciling policy-server deployment: %w", err)
 	}
+	r.recordOperationEvent(policyServer, result, "Deployment", policyServerDeployment.Name)
+
+	return nil
+}
+
+// recordPolicyServerReplicas records the ready and desired replicas metrics
+// of the policy server from its Deployment status.
+func (r *PolicyServerReconciler) recordPolicyServerReplicas(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	deployment := &appsv1.Deployment{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: policyServer.NameWithPrefix(), Namespace: r.DeploymentsNamespace}, deployment)
+	if err != nil {
+		return fmt.Errorf("cannot get policy-server deployment: %w", err)
+	}
+
+	desiredReplicas := policyServer.Spec.Replicas
+	if deployment.Spec.Replicas != nil {
+		desiredReplicas = *deployment.Spec.Replicas
+	}
+
+	if err = metrics.RecordPolicyServerReplicas(ctx, policyServer, deployment.Status.ReadyReplicas, desiredReplicas); err != nil {
+		return fmt.Errorf("failed to record policy server replicas metrics: %w", err)
+	}
+
+	return nil
+}
+
+// setPolicyServerObservedImages sets in the policy server status the images
+// run by its pods, as reported by the container statuses.
+func (r *PolicyServerReconciler) setPolicyServerObservedImages(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	pods := &corev1.PodList{}
+	err := r.Client.List(ctx, pods,
+		client.InNamespace(r.DeploymentsNamespace),
+		client.MatchingLabels{constants.PolicyServerLabelKey: policyServer.Name},
+	)
+	if err != nil {
+		return fmt.Errorf("cannot list policy server pods: %w", err)
+	}
+
+	policyServer.Status.ObservedImages = observedImages(pods.Items, policyServer.NameWithPrefix())
 
 	return nil
 }
 
+// setPolicyServerDeploymentProgressing reflects the Progressing condition of
+// the policy server Deployment into the policy server status.
+func (r *PolicyServerReconciler) setPolicyServerDeploymentProgressing(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	deployment := &appsv1.Deployment{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: policyServer.NameWithPrefix(), Namespace: r.DeploymentsNamespace}, deployment)
+	if err != nil {
+		return fmt.Errorf("cannot get policy-server deployment: %w", err)
+	}
+
+	condition := deploymentProgressingCondition(deployment)
+	if condition == nil {
+		apimeta.RemoveStatusCondition(&policyServer.Status.Conditions, string(policiesv1.PolicyServerDeploymentProgressing))
+		return nil
+	}
+	apimeta.SetStatusCondition(&policyServer.Status.Conditions, *condition)
+
+	return nil
+}
+
+// deploymentProgressingCondition returns the DeploymentProgressing condition
+// built from the Progressing condition of the given Deployment, or nil when
+// the Deployment does not report it yet.
+func deploymentProgressingCondition(deployment *appsv1.Deployment) *metav1.Condition {
+	for _, deploymentCondition := range deployment.Status.Conditions {
+		if deploymentCondition.Type != appsv1.DeploymentProgressing {
+			continue
+		}
+
+		return &metav1.Condition{
+			Type:    string(policiesv1.PolicyServerDeploymentProgressing),
+			Status:  metav1.ConditionStatus(deploymentCondition.Status),
+			Reason:  deploymentCondition.Reason,
+			Message: deploymentCondition.Message,
+		}
+	}
+
+	return nil
+}
+
+// setPolicyServerPodsSchedulable reflects into the policy server status
+// whether its pods can be created and scheduled.
+func (r *PolicyServerReconciler) setPolicyServerPodsSchedulable(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	deployment := &appsv1.Deployment{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: policyServer.NameWithPrefix(), Namespace: r.DeploymentsNamespace}, deployment)
+	if err != nil {
+		return fmt.Errorf("cannot get policy-server deployment: %w", err)
+	}
+
+	pods := &corev1.PodList{}
+	err = r.Client.List(ctx, pods,
+		client.InNamespace(r.DeploymentsNamespace),
+		client.MatchingLabels{constants.PolicyServerLabelKey: policyServer.Name},
+	)
+	if err != nil {
+		return fmt.Errorf("cannot list policy server pods: %w", err)
+	}
+
+	apimeta.SetStatusCondition(&policyServer.Status.Conditions, podsSchedulableCondition(deployment, pods.Items))
+
+	return nil
+}
+
+// podsSchedulableCondition returns the PodsSchedulable condition built from
+// the ReplicaFailure condition of the given Deployment, reporting the pods
+// rejected at creation, and from the PodScheduled condition of the given
+// pods. The first pending pod by name that cannot be scheduled is reported.
+func podsSchedulableCondition(deployment *appsv1.Deployment, pods []corev1.Pod) metav1.Condition {
+	for _, deploymentCondition := range deployment.Status.Conditions {
+		if deploymentCondition.Type == appsv1.DeploymentReplicaFailure && deploymentCondition.Status == corev1.ConditionTrue {
+			return metav1.Condition{
+				Type:    string(policiesv1.PolicyServerPodsSchedulable),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(policiesv1.PodsCreationFailed),
+				Message: deploymentCondition.Message,
+			}
+		}
+	}
+
+	pods = slices.Clone(pods)
+	slices.SortFunc(pods, func(a, b corev1.Pod) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		for _, podCondition := range pod.Status.Conditions {
+			if podCondition.Type == corev1.PodScheduled && podCondition.Status == corev1.ConditionFalse && podCondition.Reason == corev1.PodReasonUnschedulable {
+				return metav1.Condition{
+					Type:    string(policiesv1.PolicyServerPodsSchedulable),
+					Status:  metav1.ConditionFalse,
+					Reason:  string(policiesv1.PodsUnschedulable),
+					Message: fmt.Sprintf("pod %s: %s", pod.Name, podCondition.Message),
+				}
+			}
+		}
+	}
+
+	return metav1.Condition{
+		Type:   string(policiesv1.PolicyServerPodsSchedulable),
+		Status: metav1.ConditionTrue,
+		Reason: string(policiesv1.PodsScheduled),
+	}
+}
+
+// observedImages returns the sorted list of the distinct image IDs of the
+// running containers with the given name.
+func observedImages(pods []corev1.Pod, containerName string) []string {
+	var images []string
+	for _, pod := range pods {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.Name != containerName || containerStatus.State.Running == nil || containerStatus.ImageID == "" {
+				continue
+			}
+			if !slices.Contains(images, containerStatus.ImageID) {
+				images = append(images, containerStatus.ImageID)
+			}
+		}
+	}
+	slices.Sort(images)
+
+	return images
+}
+
 func configureVerificationConfig(policyServer *policiesv1.PolicyServer, admissionContainer *corev1.Container) {
-	if policyServer.Spec.VerificationConfig != "" {
+	if policyServer.Spec.VerificationConfig != "" || policyServer.Spec.VerificationConfigSecret != "" {
 		admissionContainer.VolumeMounts = append(admissionContainer.VolumeMounts,
 			corev1.VolumeMount{
 				Name:      verificationConfigVolumeName,
@@ -82,7 +266,46 @@ func configureVerificationConfig(policyServer *policiesv1.PolicyServer, admissio
 	}
 }
 
-func (r *PolicyServerReconciler) updatePolicyServerDeployment(ctx context.Context, policyServer *policiesv1.PolicyServer, policyServerDeployment *appsv1.Deployment, configMapVersion string) error {
+// verificationConfigVolumeSource returns the source of the volume holding the
+// Sigstore verification configuration, from either the ConfigMap or the
+// Secret set in the PolicyServer. It returns nil when none is set.
+func verificationConfigVolumeSource(policyServer *policiesv1.PolicyServer) *corev1.VolumeSource {
+	items := []corev1.KeyToPath{
+		{
+			Key:  constants.PolicyServerVerificationConfigEntry,
+			Path: verificationFilename,
+		},
+	}
+
+	switch {
+	case policyServer.Spec.VerificationConfigSecret != "":
+		return &corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: policyServer.Spec.VerificationConfigSecret,
+				Items:      items,
+			},
+		}
+	case policyServer.Spec.VerificationConfig != "":
+		return &corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: policyServer.Spec.VerificationConfig,
+				},
+				Items: items,
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+func (r *PolicyServerReconciler) updatePolicyServerDeployment(
+	ctx context.Context,
+	policyServer *policiesv1.PolicyServer,
+	policyServerDeployment *appsv1.Deployment,
+	configMapVersion, configMapChecksum string,
+	automountServiceAccountToken *bool,
+) error {
 	admissionContainer := getPolicyServerContainer(policyServer)
 
 	if r.AlwaysAcceptAdmissionReviewsInDeploymentsNamespace {
@@ -95,38 +318,45 @@ func (r *PolicyServerReconciler) updatePolicyServerDeployment(ctx context.Contex
 	configureVerificationConfig(policyServer, &admissionContainer)
 	configureImagePullSecret(policyServer, &admissionContainer)
 	configuresInsecureSources(policyServer, &admissionContainer)
+	configurePreStopHook(policyServer, &admissionContainer)
 
-	podSecurityContext := defaultPodSecurityContext()
-	if policyServer.Spec.SecurityContexts.Pod != nil {
-		podSecurityContext = policyServer.Spec.SecurityContexts.Pod
-	}
+	podSecurityContext := buildPodSecurityContext(policyServer)
 
-	admissionContainer.SecurityContext = defaultContainerSecurityContext()
+	admissionContainer.SecurityContext = policiesv1.DefaultPolicyServerContainerSecurityContext()
 	if policyServer.Spec.SecurityContexts.Container != nil {
 		admissionContainer.SecurityContext = policyServer.Spec.SecurityContexts.Container
 	}
 
-	templateAnnotations := policyServer.Spec.Annotations
+	templateAnnotations := maps.Clone(policyServer.Spec.Annotations)
 	if templateAnnotations == nil {
 		templateAnnotations = make(map[string]string)
 	}
+	// The pods are rolled out only when the content of the ConfigMap
+	// changes, not when it is rewritten with the same content.
+	templateAnnotations[constants.PolicyServerPodConfigChecksumAnnotation] = configMapChecksum
 
 	configureLabelsAndAnnotations(policyServerDeployment, policyServer, configMapVersion)
 
 	policyServerDeployment.Spec = buildPolicyServerDeploymentSpec(
 		policyServer,
 		admissionContainer,
-		configMapVersion,
 		templateAnnotations,
 		podSecurityContext,
 	)
 	r.adaptDeploymentForMetricsAndTracingConfiguration(policyServerDeployment, templateAnnotations)
 	r.adaptDeploymentSettingsForPolicyServer(policyServerDeployment, policyServer)
+	if err := r.configureZoneAntiAffinity(ctx, policyServerDeployment, policyServer); err != nil {
+		return err
+	}
 
 	if err := r.configureMutualTLS(ctx, policyServerDeployment); err != nil {
 		return fmt.Errorf("failed to configure mutual TLS: %w", err)
 	}
-	if err := controllerutil.SetOwnerReference(policyServer, policyServerDeployment, r.Client.Scheme()); err != nil {
+	policyServerDeployment.Spec.Template.Spec.AutomountServiceAccountToken = automountServiceAccountToken
+	configureServiceAccountToken(policyServerDeployment, policyServer)
+	configureProjectedToken(policyServerDeployment, policyServer)
+	configureInitContainers(policyServerDeployment, policyServer)
+	if err := setPolicyServerOwnerReference(policyServer, policyServerDeployment, r.Client.Scheme()); err != nil {
 		return errors.Join(errors.New("failed to set policy server deployment owner reference"), err)
 	}
 
@@ -154,6 +384,13 @@ func (r *PolicyServerReconciler) adaptDeploymentForMetricsAndTracingConfiguratio
 		} else {
 			admissionContainer.Env = append(admissionContainer.Env, logFmtEnvVar)
 		}
+		for _, envvar := range r.tracesSamplerEnvVars() {
+			if index := envVarsContainVariable(admissionContainer.Env, envvar.Name); index >= 0 {
+				admissionContainer.Env[index] = envvar
+			} else {
+				admissionContainer.Env = append(admissionContainer.Env, envvar)
+			}
+		}
 	}
 
 	// If the otel sidecar is disabled, we  need to configure the policy
@@ -195,36 +432,24 @@ func (r *PolicyServerReconciler) adaptDeploymentForMetricsAndTracingConfiguratio
 }
 
 func (r *PolicyServerReconciler) adaptDeploymentSettingsForPolicyServer(policyServerDeployment *appsv1.Deployment, policyServer *policiesv1.PolicyServer) {
-	if policyServer.Spec.VerificationConfig != "" {
+	if volumeSource := verificationConfigVolumeSource(policyServer); volumeSource != nil {
 		policyServerDeployment.Spec.Template.Spec.Volumes = append(
 			policyServerDeployment.Spec.Template.Spec.Volumes,
 			corev1.Volume{
-				Name: verificationConfigVolumeName,
-				VolumeSource: corev1.VolumeSource{
-					ConfigMap: &corev1.ConfigMapVolumeSource{
-						LocalObjectReference: corev1.LocalObjectReference{
-							Name: policyServer.Spec.VerificationConfig,
-						},
-						Items: []corev1.KeyToPath{
-							{
-								Key:  constants.PolicyServerVerificationConfigEntry,
-								Path: verificationFilename,
-							},
-						},
-					},
-				},
+				Name:         verificationConfigVolumeName,
+				VolumeSource: *volumeSource,
 			},
 		)
 	}
 
-	if policyServer.Spec.ImagePullSecret != "" {
+	if imagePullSecret := policyFetchImagePullSecretName(policyServer); imagePullSecret != "" {
 		policyServerDeployment.Spec.Template.Spec.Volumes = append(
 			policyServerDeployment.Spec.Template.Spec.Volumes,
 			corev1.Volume{
 				Name: imagePullSecretVolumeName,
 				VolumeSource: corev1.VolumeSource{
 					Secret: &corev1.SecretVolumeSource{
-						SecretName: policyServer.Spec.ImagePullSecret,
+						SecretName: imagePullSecret,
 						Items: []corev1.KeyToPath{
 							{
 								Key:  ".dockerconfigjson",
@@ -254,8 +479,167 @@ func (r *PolicyServerReconciler) adaptDeploymentSettingsForPolicyServer(policySe
 	}
 }
 
+// automountServiceAccountToken returns whether the service account token is
+// mounted in the policy server pods. Disabling the token is overridden while
+// context-aware policies are bound to the policy server, because they need to
+// access the Kubernetes API server, and a warning event is recorded.
+func (r *PolicyServerReconciler) automountServiceAccountToken(policyServer *policiesv1.PolicyServer, policies []policiesv1.Policy) *bool {
+	automount := policyServer.Spec.AutomountServiceAccountToken
+	// The projected token set by serviceAccountToken replaces the
+	// automatically mounted one.
+	if policyServer.Spec.ServiceAccountToken != nil || automount == nil || *automount {
+		return automount
+	}
+
+	var contextAwarePolicies []string
+	for _, policy := range policies {
+		if len(policy.GetContextAwareResources()) != 0 {
+			contextAwarePolicies = append(contextAwarePolicies, policy.GetUniqueName())
+		}
+	}
+	if len(contextAwarePolicies) == 0 {
+		return automount
+	}
+
+	slices.Sort(contextAwarePolicies)
+	r.recordEvent(policyServer, corev1.EventTypeWarning, serviceAccountTokenRequiredReason,
+		"The service account token is mounted despite spec.automountServiceAccountToken, the context-aware policies %s need to access the Kubernetes API server",
+		strings.Join(contextAwarePolicies, ", "))
+
+	return ptr.To(true)
+}
+
+// configureServiceAccountToken replaces the automatically mounted service
+// account token of the policy server with a projected token bound to the
+// audience and expiration set in the PolicyServer. The projected volume is
+// mounted where the Kubernetes clients look for the in-cluster credentials,
+// together with the cluster CA and the namespace.
+func configureServiceAccountToken(policyServerDeployment *appsv1.Deployment, policyServer *policiesv1.PolicyServer) {
+	tokenProjection := policyServer.Spec.ServiceAccountToken
+	if tokenProjection == nil {
+		return
+	}
+
+	podSpec := &policyServerDeployment.Spec.Template.Spec
+	podSpec.AutomountServiceAccountToken = ptr.To(false)
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: serviceAccountTokenVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							Audience:          tokenProjection.Audience,
+							ExpirationSeconds: tokenProjection.ExpirationSeconds,
+							Path:              "token",
+						},
+					},
+					{
+						ConfigMap: &corev1.ConfigMapProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: kubeRootCAConfigMapName},
+							Items: []corev1.KeyToPath{
+								{Key: "ca.crt", Path: "ca.crt"},
+							},
+						},
+					},
+					{
+						DownwardAPI: &corev1.DownwardAPIProjection{
+							Items: []corev1.DownwardAPIVolumeFile{
+								{
+									Path:     "namespace",
+									FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "metadata.namespace"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	admissionContainer := &podSpec.Containers[0]
+	admissionContainer.VolumeMounts = append(admissionContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      serviceAccountTokenVolumeName,
+		ReadOnly:  true,
+		MountPath: serviceAccountTokenVolumePath,
+	})
+}
+
+// configureProjectedToken mounts in the policy server container the projected
+// service account token bound to the audience set in the PolicyServer, e.g.
+// to authenticate against an external service. It does not replace the token
+// used to reach the Kubernetes API server.
+func configureProjectedToken(policyServerDeployment *appsv1.Deployment, policyServer *policiesv1.PolicyServer) {
+	tokenProjection := policyServer.Spec.ProjectedToken
+	if tokenProjection == nil {
+		return
+	}
+
+	podSpec := &policyServerDeployment.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: projectedTokenVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							Audience:          tokenProjection.Audience,
+							ExpirationSeconds: tokenProjection.ExpirationSeconds,
+							Path:              "token",
+						},
+					},
+				},
+			},
+		},
+	})
+
+	admissionContainer := &podSpec.Containers[0]
+	admissionContainer.VolumeMounts = append(admissionContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      projectedTokenVolumeName,
+		ReadOnly:  true,
+		MountPath: projectedTokenVolumePath,
+	})
+}
+
+// configureInitContainers appends the init containers of the PolicyServer
+// after the ones already set by the controller, mounting in them the volumes
+// of the policy server container. The volumes already mounted by an init
+// container, by name or by path, are left as they are.
+func configureInitContainers(policyServerDeployment *appsv1.Deployment, policyServer *policiesv1.PolicyServer) {
+	admissionContainer := policyServerDeployment.Spec.Template.Spec.Containers[0]
+
+	for _, initContainer := range policyServer.Spec.InitContainers {
+		initContainer = *initContainer.DeepCopy()
+		for _, volumeMount := range admissionContainer.VolumeMounts {
+			alreadyMounted := slices.ContainsFunc(initContainer.VolumeMounts, func(mount corev1.VolumeMount) bool {
+				return mount.Name == volumeMount.Name || mount.MountPath == volumeMount.MountPath
+			})
+			if !alreadyMounted {
+				initContainer.VolumeMounts = append(initContainer.VolumeMounts, volumeMount)
+			}
+		}
+		policyServerDeployment.Spec.Template.Spec.InitContainers = append(policyServerDeployment.Spec.Template.Spec.InitContainers, initContainer)
+	}
+}
+
+// configurePreStopHook delays the termination of the policy server container
+// with a preStop sleep hook, so that the pod endpoint is removed from the
+// Service before the policy server stops serving.
+func configurePreStopHook(policyServer *policiesv1.PolicyServer, admissionContainer *corev1.Container) {
+	preStopSleepSeconds := policyServer.Spec.PreStopSleepSeconds
+	if preStopSleepSeconds == nil || *preStopSleepSeconds == 0 {
+		return
+	}
+
+	admissionContainer.Lifecycle = &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			Sleep: &corev1.SleepAction{Seconds: int64(*preStopSleepSeconds)},
+		},
+	}
+}
+
 func configureImagePullSecret(policyServer *policiesv1.PolicyServer, admissionContainer *corev1.Container) {
-	if policyServer.Spec.ImagePullSecret != "" {
+	if policyFetchImagePullSecretName(policyServer) != "" {
 		admissionContainer.VolumeMounts = append(admissionContainer.VolumeMounts,
 			corev1.VolumeMount{
 				Name:      imagePullSecretVolumeName,
@@ -372,31 +756,51 @@ func (r *PolicyServerReconciler) configureMutualTLS(ctx context.Context, policyS
 func buildPolicyServerDeploymentSpec(
 	policyServer *policiesv1.PolicyServer,
 	admissionContainer corev1.Container,
-	configMapVersion string,
 	templateAnnotations map[string]string,
 	podSecurityContext *corev1.PodSecurityContext,
 ) appsv1.DeploymentSpec {
 	templateLabels := map[string]string{
 		//nolint:staticcheck // this label will remove soon when policy lifecycle is revisited
-		constants.AppLabelKey: policyServer.AppLabel(),
-		constants.PolicyServerDeploymentPodSpecConfigVersionLabel: configMapVersion,
-		constants.PolicyServerLabelKey:                            policyServer.Name,
+		constants.AppLabelKey:          policyServer.AppLabel(),
+		constants.PolicyServerLabelKey: policyServer.Name,
 	}
 	for key, value := range policyServer.CommonLabels() {
 		templateLabels[key] = value
 	}
 
+	revisionHistoryLimit := int32(constants.PolicyServerDefaultRevisionHistoryLimit)
+	if policyServer.Spec.RevisionHistoryLimit != nil {
+		revisionHistoryLimit = *policyServer.Spec.RevisionHistoryLimit
+	}
+
+	progressDeadlineSeconds := int32(constants.PolicyServerDefaultProgressDeadlineSeconds)
+	if policyServer.Spec.ProgressDeadlineSeconds != nil {
+		progressDeadlineSeconds = *policyServer.Spec.ProgressDeadlineSeconds
+	}
+
+	var dnsPolicy corev1.DNSPolicy
+	if policyServer.Spec.DNSPolicy != nil {
+		dnsPolicy = *policyServer.Spec.DNSPolicy
+	}
+
+	strategy := appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+	}
+	if policyServer.Spec.Strategy != nil {
+		strategy = *policyServer.Spec.Strategy
+	}
+
 	return appsv1.DeploymentSpec{
-		Replicas: &policyServer.Spec.Replicas,
+		Replicas:                &policyServer.Spec.Replicas,
+		RevisionHistoryLimit:    &revisionHistoryLimit,
+		ProgressDeadlineSeconds: &progressDeadlineSeconds,
 		Selector: &metav1.LabelSelector{
 			MatchLabels: map[string]string{
 				//nolint:staticcheck // this label will remove soon when policy lifecycle is revisited
 				constants.AppLabelKey: policyServer.AppLabel(),
 			},
 		},
-		Strategy: appsv1.DeploymentStrategy{
-			Type: appsv1.RollingUpdateDeploymentStrategyType,
-		},
+		Strategy: strategy,
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
 				Labels:      templateLabels,
@@ -405,10 +809,18 @@ func buildPolicyServerDeploymentSpec(
 			Spec: corev1.PodSpec{
 				SecurityContext:    podSecurityContext,
 				Containers:         []corev1.Container{admissionContainer},
-				ServiceAccountName: policyServer.Spec.ServiceAccountName,
+				ServiceAccountName: policyServerServiceAccountName(policyServer),
+				ImagePullSecrets:   policyServer.Spec.ImagePullSecrets,
 				Tolerations:        policyServer.Spec.Tolerations,
 				Affinity:           &policyServer.Spec.Affinity,
+				NodeSelector:       policyServer.Spec.NodeSelector,
+				NodeName:           policyServer.Spec.NodeName,
 				PriorityClassName:  policyServer.Spec.PriorityClassName,
+				RuntimeClassName:   policyServer.Spec.RuntimeClassName,
+				HostAliases:        policyServer.Spec.HostAliases,
+				DNSPolicy:          dnsPolicy,
+				DNSConfig:          policyServer.Spec.DNSConfig,
+				ReadinessGates:     policyServer.Spec.ReadinessGates,
 				Volumes: []corev1.Volume{
 					{
 						Name: policyStoreVolume,
@@ -420,7 +832,7 @@ func buildPolicyServerDeploymentSpec(
 						Name: certsVolumeName,
 						VolumeSource: corev1.VolumeSource{
 							Secret: &corev1.SecretVolumeSource{
-								SecretName: policyServer.NameWithPrefix(),
+								SecretName: policyServerCertSecretName(policyServer),
 							},
 						},
 					},
@@ -488,7 +900,23 @@ func setOtelCertificateMounts(policyServerDeployment *appsv1.Deployment, otelCer
 
 func replicateOtelEnvVars(policyServerDeployment *appsv1.Deployment) {
 	admissionContainer := &policyServerDeployment.Spec.Template.Spec.Containers[0]
-	otelEnvVarToReplicate := []string{
+	for _, envVar := range replicatedOtelEnvVars() {
+		if value := os.Getenv(envVar); value != "" {
+			envvar := corev1.EnvVar{Name: envVar, Value: value}
+			if index := envVarsContainVariable(admissionContainer.Env, envVar); index >= 0 {
+				admissionContainer.Env[index] = envvar
+			} else {
+				admissionContainer.Env = append(admissionContainer.Env, envvar)
+			}
+		}
+	}
+}
+
+// replicatedOtelEnvVars returns the OpenTelemetry environment variables of
+// the controller replicated in the policy server container when the telemetry
+// data is sent to a remote collector.
+func replicatedOtelEnvVars() []string {
+	return []string{
 		"OTEL_EXPORTER_OTLP_CERTIFICATE",
 		"OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE",
 		"OTEL_EXPORTER_OTLP_CLIENT_KEY",
@@ -516,16 +944,57 @@ func replicateOtelEnvVars(policyServerDeployment *appsv1.Deployment) {
 		"OTEL_EXPORTER_OTLP_TRACES_INSECURE",
 		"OTEL_EXPORTER_OTLP_TRACES_TIMEOUT",
 	}
-	for _, envVar := range otelEnvVarToReplicate {
-		if value := os.Getenv(envVar); value != "" {
-			envvar := corev1.EnvVar{Name: envVar, Value: value}
-			if index := envVarsContainVariable(admissionContainer.Env, envVar); index >= 0 {
-				admissionContainer.Env[index] = envvar
-			} else {
-				admissionContainer.Env = append(admissionContainer.Env, envvar)
+}
+
+// tracesSamplerEnvVars returns the OpenTelemetry environment variables
+// configuring the sampler of the traces of the policy server. The samplers,
+// but the one never sampling, honor the sampling decision of the parent
+// span.
+func (t TelemetryConfiguration) tracesSamplerEnvVars() []corev1.EnvVar {
+	switch t.TracingSampler {
+	case TracingSamplerAlways:
+		return []corev1.EnvVar{
+			{Name: tracesSamplerEnvVar, Value: "parentbased_always_on"},
+		}
+	case TracingSamplerNever:
+		return []corev1.EnvVar{
+			{Name: tracesSamplerEnvVar, Value: "always_off"},
+		}
+	case TracingSamplerRatio:
+		return []corev1.EnvVar{
+			{Name: tracesSamplerEnvVar, Value: "parentbased_traceidratio"},
+			{Name: tracesSamplerArgEnvVar, Value: strconv.FormatFloat(t.TracingSamplerRatio, 'f', -1, 64)},
+		}
+	default:
+		return nil
+	}
+}
+
+// ManagedOtelEnvVars returns the OpenTelemetry environment variables set by
+// the controller in the policy server container, overriding the ones set in
+// the PolicyServer.
+func (t TelemetryConfiguration) ManagedOtelEnvVars() []string {
+	if !t.MetricsEnabled && !t.TracingEnabled {
+		return nil
+	}
+
+	var envVars []string
+	if t.OtelSidecarEnabled {
+		envVars = append(envVars, "OTEL_EXPORTER_OTLP_ENDPOINT")
+	} else {
+		for _, envVar := range replicatedOtelEnvVars() {
+			if os.Getenv(envVar) != "" {
+				envVars = append(envVars, envVar)
 			}
 		}
 	}
+	if t.TracingEnabled {
+		for _, envVar := range t.tracesSamplerEnvVars() {
+			envVars = append(envVars, envVar.Name)
+		}
+	}
+
+	return envVars
 }
 
 func envVarsContainVariable(envVars []corev1.EnvVar, envVarName string) int {
@@ -537,34 +1006,21 @@ func envVarsContainVariable(envVars []corev1.EnvVar, envVarName string) int {
 	return -1
 }
 
-func defaultContainerSecurityContext() *corev1.SecurityContext {
-	enableReadOnlyFilesystem := true
-	privileged := false
-	runAsNonRoot := true
-	allowPrivilegeEscalation := false
-	capabilities := corev1.Capabilities{
-		Add:  []corev1.Capability{},
-		Drop: []corev1.Capability{"ALL"},
-	}
-	admissionContainerSecurityContext := corev1.SecurityContext{
-		ReadOnlyRootFilesystem:   &enableReadOnlyFilesystem,
-		Privileged:               &privileged,
-		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
-		Capabilities:             &capabilities,
-		RunAsNonRoot:             &runAsNonRoot,
+// buildPodSecurityContext returns the security context of the policy server
+// Pod, applying the seccomp profile of the PolicyServer on top of the pod
+// security context.
+func buildPodSecurityContext(policyServer *policiesv1.PolicyServer) *corev1.PodSecurityContext {
+	podSecurityContext := policiesv1.DefaultPolicyServerPodSecurityContext()
+	if policyServer.Spec.SecurityContexts.Pod != nil {
+		podSecurityContext = policyServer.Spec.SecurityContexts.Pod
 	}
-	return &admissionContainerSecurityContext
-}
 
-func defaultPodSecurityContext() *corev1.PodSecurityContext {
-	seccompProfile := &corev1.SeccompProfile{
-		Type: corev1.SeccompProfileTypeRuntimeDefault,
+	if policyServer.Spec.SecurityContexts.SeccompProfile != nil {
+		podSecurityContext = podSecurityContext.DeepCopy()
+		podSecurityContext.SeccompProfile = policyServer.Spec.SecurityContexts.SeccompProfile.DeepCopy()
 	}
 
-	admissionContainerSecurityContext := corev1.PodSecurityContext{
-		SeccompProfile: seccompProfile,
-	}
-	return &admissionContainerSecurityContext
+	return podSecurityContext
 }
 
 func getPolicyServerContainer(policyServer *policiesv1.PolicyServer) corev1.Container {
@@ -597,12 +1053,12 @@ func getPolicyServerContainer(policyServer *policiesv1.PolicyServer) corev1.Cont
 				Value: filepath.Join(secretsContainerPath, constants.ServerPrivateKey),
 			},
 			{
-				Name:  "KUBEWARDEN_PORT",
-				Value: strconv.Itoa(constants.PolicyServerListenPort),
+				Name:  constants.PolicyServerListenPortEnvVar,
+				Value: strconv.Itoa(policyServer.ListenPort()),
 			},
 			{
-				Name:  "KUBEWARDEN_READINESS_PROBE_PORT",
-				Value: strconv.Itoa(constants.PolicyServerReadinessProbePort),
+				Name:  constants.PolicyServerReadinessProbePortEnvVar,
+				Value: strconv.Itoa(policyServer.ReadinessProbePort()),
 			},
 			{
 				Name:  "KUBEWARDEN_POLICIES_DOWNLOAD_DIR",
@@ -616,19 +1072,93 @@ func getPolicyServerContainer(policyServer *policiesv1.PolicyServer) corev1.Cont
 				Name:  "KUBEWARDEN_SIGSTORE_CACHE_DIR",
 				Value: sigstoreCacheDirPath,
 			},
-		}, policyServer.Spec.Env...),
-		ReadinessProbe: &corev1.Probe{
-			ProbeHandler: corev1.ProbeHandler{
-				HTTPGet: &corev1.HTTPGetAction{
-					Path:   constants.PolicyServerReadinessProbe,
-					Port:   intstr.FromInt(constants.PolicyServerReadinessProbePort),
-					Scheme: corev1.URISchemeHTTP,
-				},
-			},
-		},
+		}, slices.Concat(logLevelEnv(policyServer.Spec.LogLevel), workersEnv(policyServer.Spec), featureFlagsEnv(policyServer.Spec.FeatureFlags), policyServer.Spec.Env)...),
+		EnvFrom:        policyServer.Spec.EnvFrom,
+		ReadinessProbe: buildPolicyServerProbe(policyServer.ReadinessProbePort(), policyServer.Spec.Probes.Readiness),
+		LivenessProbe:  buildPolicyServerLivenessProbe(policyServer.ReadinessProbePort(), policyServer.Spec.Probes.Liveness),
 		Resources: corev1.ResourceRequirements{
 			Requests: policyServer.Spec.Requests,
 			Limits:   policyServer.Spec.Limits,
 		},
 	}
 }
+
+// logLevelEnv returns the environment variable setting the policy server log
+// level. The variables set in the PolicyServer env come after it and take
+// precedence.
+func logLevelEnv(logLevel string) []corev1.EnvVar {
+	if logLevel == "" {
+		return nil
+	}
+
+	return []corev1.EnvVar{{Name: constants.PolicyServerLogLevelEnvVar, Value: logLevel}}
+}
+
+// workersEnv returns the environment variable setting the number of workers
+// of the policy server. When not set in the PolicyServer, it defaults to the
+// CPU limit rounded up, because the policy server does not take the limits of
+// its container into account. The variables set in the PolicyServer env come
+// after it and take precedence.
+func workersEnv(spec policiesv1.PolicyServerSpec) []corev1.EnvVar {
+	var workers int64
+	if spec.Workers != nil {
+		workers = int64(*spec.Workers)
+	} else if cpuLimit, found := spec.Limits[corev1.ResourceCPU]; found {
+		workers = max(cpuLimit.Value(), 1)
+	} else {
+		return nil
+	}
+
+	return []corev1.EnvVar{{Name: constants.PolicyServerWorkersEnvVar, Value: strconv.FormatInt(workers, 10)}}
+}
+
+// featureFlagsEnv returns the environment variables toggling the known
+// features of the policy server, sorted by feature name to not restart the
+// policy server pods when the flags do not change. The unknown features are
+// ignored.
+func featureFlagsEnv(featureFlags map[string]string) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	for _, name := range slices.Sorted(maps.Keys(featureFlags)) {
+		if envName, found := policiesv1.PolicyServerFeatureFlagsEnv()[name]; found {
+			env = append(env, corev1.EnvVar{Name: envName, Value: featureFlags[name]})
+		}
+	}
+
+	return env
+}
+
+// buildPolicyServerProbe returns a probe checking the readiness endpoint of
+// the policy server on the given port, using the given parameters. The
+// parameters not set use the Kubernetes defaults.
+func buildPolicyServerProbe(port int, config *policiesv1.ProbeConfiguration) *corev1.Probe {
+	probe := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:   constants.PolicyServerReadinessProbe,
+				Port:   intstr.FromInt(port),
+				Scheme: corev1.URISchemeHTTP,
+			},
+		},
+	}
+	if config == nil {
+		return probe
+	}
+
+	probe.InitialDelaySeconds = ptr.Deref(config.InitialDelaySeconds, 0)
+	probe.PeriodSeconds = ptr.Deref(config.PeriodSeconds, 0)
+	probe.TimeoutSeconds = ptr.Deref(config.TimeoutSeconds, 0)
+	probe.SuccessThreshold = ptr.Deref(config.SuccessThreshold, 0)
+	probe.FailureThreshold = ptr.Deref(config.FailureThreshold, 0)
+
+	return probe
+}
+
+// buildPolicyServerLivenessProbe returns the liveness probe of the policy
+// server container, which is only defined when configured by the user.
+func buildPolicyServerLivenessProbe(port int, config *policiesv1.ProbeConfiguration) *corev1.Probe {
+	if config == nil {
+		return nil
+	}
+
+	return buildPolicyServerProbe(port, config)
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+)
+
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+const (
+	policyServerEventRecorderName = "policyserver-controller"
+	// policyServerReconcileFailedReason is the reason of the warning events
+	// recorded when the reconciliation of a policy server fails.
+	policyServerReconcileFailedReason = "ReconcileFailed"
+	// serviceAccountTokenRequiredReason is the reason of the warning events
+	// recorded when the service account token is mounted in the policy
+	// server pods despite the PolicyServer disabling it.
+	serviceAccountTokenRequiredReason = "ServiceAccountTokenRequired"
+)
+
+// recordEvent records an event about the policy server. The events are not
+// recorded when the reconciler has no recorder, e.g. when it has not been
+// set up with a manager.
+func (r *PolicyServerReconciler) recordEvent(policyServer *policiesv1.PolicyServer, eventType, reason, messageFmt string, args ...any) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(policyServer, eventType, reason, messageFmt, args...)
+}
+
+// recordOperationEvent records a normal event when the given object of the
+// policy server has been created or updated, e.g. the reason of the event of
+// a created Deployment is DeploymentCreated. Nothing is recorded when the
+// object was left untouched.
+func (r *PolicyServerReconciler) recordOperationEvent(policyServer *policiesv1.PolicyServer, result controllerutil.OperationResult, kind, name string) {
+	switch result {
+	case controllerutil.OperationResultCreated:
+		r.recordEvent(policyServer, corev1.EventTypeNormal, kind+"Created", "Created %s %s/%s", kind, r.DeploymentsNamespace, name)
+	case controllerutil.OperationResultUpdated, controllerutil.OperationResultUpdatedStatus:
+		r.recordEvent(policyServer, corev1.EventTypeNormal, kind+"Updated", "Updated %s %s/%s", kind, r.DeploymentsNamespace, name)
+	case controllerutil.OperationResultNone, controllerutil.OperationResultUpdatedStatusOnly:
+	}
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+)
+
+var _ = Describe("Policy server events", func() {
+	ctx := context.Background()
+
+	var (
+		recorder   *record.FakeRecorder
+		reconciler *PolicyServerReconciler
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(testScheme)).To(Succeed())
+		Expect(policiesv1.AddToScheme(testScheme)).To(Succeed())
+
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &PolicyServerReconciler{
+			Client:               fake.NewClientBuilder().WithScheme(testScheme).Build(),
+			DeploymentsNamespace: deploymentsNamespace,
+			Recorder:             recorder,
+		}
+	})
+
+	It("should record the creation and the deletion of the PodDisruptionBudget", func() {
+		policyServer := policiesv1.NewPolicyServerFactory().WithName("events").Build()
+		policyServer.Spec.MinAvailable = ptr.To(intstr.FromInt(1))
+
+		Expect(reconciler.reconcilePolicyServerPodDisruptionBudget(ctx, policyServer)).To(Succeed())
+		Expect(recorder.Events).To(Receive(Equal("Normal PodDisruptionBudgetCreated Created PodDisruptionBudget " + deploymentsNamespace + "/policy-server-events")))
+
+		Expect(reconciler.reconcilePolicyServerPodDisruptionBudget(ctx, policyServer)).To(Succeed())
+		Expect(recorder.Events).ToNot(Receive())
+
+		policyServer.Spec.MinAvailable = nil
+		Expect(reconciler.reconcilePolicyServerPodDisruptionBudget(ctx, policyServer)).To(Succeed())
+		Expect(recorder.Events).To(Receive(Equal("Normal PodDisruptionBudgetDeleted Deleted PodDisruptionBudget " + deploymentsNamespace + "/policy-server-events")))
+
+		Expect(reconciler.reconcilePolicyServerPodDisruptionBudget(ctx, policyServer)).To(Succeed())
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("should record the update of the policy server resources", func() {
+		policyServer := policiesv1.NewPolicyServerFactory().WithName("events").Build()
+
+		reconciler.recordOperationEvent(policyServer, controllerutil.OperationResultUpdated, "Deployment", "policy-server-events")
+		Expect(recorder.Events).To(Receive(Equal("Normal DeploymentUpdated Updated Deployment " + deploymentsNamespace + "/policy-server-events")))
+
+		reconciler.recordOperationEvent(policyServer, controllerutil.OperationResultNone, "Deployment", "policy-server-events")
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("should mount the service account token needed by the context-aware policies", func() {
+		policyServer := policiesv1.NewPolicyServerFactory().WithName("events").Build()
+		policyServer.Spec.AutomountServiceAccountToken = ptr.To(false)
+		policy := policiesv1.NewClusterAdmissionPolicyFactory().WithName("context-aware").WithPolicyServer("events").Build()
+
+		Expect(reconciler.automountServiceAccountToken(policyServer, []policiesv1.Policy{policy})).To(HaveValue(BeFalse()))
+		Expect(recorder.Events).ToNot(Receive())
+
+		policy.Spec.ContextAwareResources = []policiesv1.ContextAwareResource{{APIVersion: "v1", Kind: "Namespace"}}
+		Expect(reconciler.automountServiceAccountToken(policyServer, []policiesv1.Policy{policy})).To(HaveValue(BeTrue()))
+		Expect(recorder.Events).To(Receive(Equal("Warning ServiceAccountTokenRequired The service account token is mounted despite spec.automountServiceAccountToken, the context-aware policies clusterwide-context-aware need to access the Kubernetes API server")))
+
+		policyServer.Spec.ServiceAccountToken = &policiesv1.ServiceAccountTokenProjection{Audience: "kubewarden-policy-server"}
+		Expect(reconciler.automountServiceAccountToken(policyServer, []policiesv1.Policy{policy})).To(HaveValue(BeFalse()))
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("should not record events without a recorder", func() {
+		reconciler.Recorder = nil
+		policyServer := policiesv1.NewPolicyServerFactory().WithName("events").Build()
+
+		Expect(func() {
+			reconciler.recordEvent(policyServer, "Warning", policyServerReconcileFailedReason, "Reconciliation failed: %v", errors.New("boom"))
+		}).ToNot(Panic())
+	})
+})
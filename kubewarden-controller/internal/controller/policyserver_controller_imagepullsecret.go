@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+// dockerConfigJSON is the content of a kubernetes.io/dockerconfigjson secret.
+type dockerConfigJSON struct {
+	Auths map[string]json.RawMessage `json:"auths"`
+}
+
+// mergedImagePullSecretName returns the name of the secret holding the merged
+// credentials of all the image pull secrets of the policy server.
+func mergedImagePullSecretName(policyServer *policiesv1.PolicyServer) string {
+	return policyServer.NameWithPrefix() + constants.PolicyServerImagePullSecretsSuffix
+}
+
+// policyFetchImagePullSecretName returns the name of the secret mounted in the
+// policy server to pull the policies. The policy server reads a single docker
+// config file, so when more image pull secrets are referenced the secret
+// holding their merged credentials is used.
+func policyFetchImagePullSecretName(policyServer *policiesv1.PolicyServer) string {
+	names := policyServer.ImagePullSecretNames()
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	default:
+		return mergedImagePullSecretName(policyServer)
+	}
+}
+
+// reconcilePolicyServerImagePullSecret reconciles the secret holding the
+// merged credentials of the image pull secrets of the policy server. The
+// secret is only needed when more image pull secrets are referenced, otherwise
+// it is deleted.
+func (r *PolicyServerReconciler) reconcilePolicyServerImagePullSecret(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	mergedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mergedImagePullSecretName(policyServer),
+			Namespace: r.DeploymentsNamespace,
+		},
+	}
+
+	names := policyServer.ImagePullSecretNames()
+	if len(names) <= 1 {
+		return r.deleteMergedImagePullSecret(ctx, policyServer, mergedSecret)
+	}
+
+	dockerConfig, err := r.mergeImagePullSecrets(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	_, err = controllerutil.CreateOrPatch(ctx, r.Client, mergedSecret, func() error {
+		if err = setPolicyServerOwnerReference(policyServer, mergedSecret, r.Client.Scheme()); err != nil {
+			return errors.Join(errors.New("failed to set merged image pull secret owner reference"), err)
+		}
+
+		mergedSecret.Labels = map[string]string{
+			constants.PartOfLabelKey:    constants.PartOfLabelValue,
+			constants.ComponentLabelKey: constants.ComponentPolicyServerLabelValue,
+		}
+		mergedSecret.Type = corev1.SecretTypeDockerConfigJson
+		mergedSecret.Data = map[string][]byte{
+			corev1.DockerConfigJsonKey: dockerConfig,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot reconcile merged image pull secret: %w", err)
+	}
+
+	return nil
+}
+
+// deleteMergedImagePullSecret deletes the secret holding the merged
+// credentials of the image pull secrets of the policy server. A secret with
+// the same name not owned by the policy server, created by the user, is left
+// untouched.
+func (r *PolicyServerReconciler) deleteMergedImagePullSecret(ctx context.Context, policyServer *policiesv1.PolicyServer, mergedSecret *corev1.Secret) error {
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(mergedSecret), mergedSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("cannot get merged image pull secret: %w", err)
+	}
+	if !isOwnedByPolicyServer(mergedSecret, policyServer) {
+		return nil
+	}
+	if err := client.IgnoreNotFound(r.Client.Delete(ctx, mergedSecret)); err != nil {
+		return fmt.Errorf("cannot delete merged image pull secret: %w", err)
+	}
+
+	return nil
+}
+
+// mergeImagePullSecrets returns the docker config holding the credentials of
+// all the given image pull secrets. When more secrets provide credentials for
+// the same registry, the first one wins.
+func (r *PolicyServerReconciler) mergeImagePullSecrets(ctx context.Context, names []string) ([]byte, error) {
+	merged := dockerConfigJSON{Auths: map[string]json.RawMessage{}}
+
+	for _, name := range names {
+		secret := &corev1.Secret{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: r.DeploymentsNamespace}, secret); err != nil {
+			return nil, fmt.Errorf("cannot get image pull secret %q: %w", name, err)
+		}
+
+		var dockerConfig dockerConfigJSON
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &dockerConfig); err != nil {
+			return nil, fmt.Errorf("cannot parse image pull secret %q: %w", name, err)
+		}
+		for registry, auth := range dockerConfig.Auths {
+			if _, found := merged.Auths[registry]; !found {
+				merged.Auths[registry] = auth
+			}
+		}
+	}
+
+	dockerConfig, err := json.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal merged image pull secret: %w", err)
+	}
+
+	return dockerConfig, nil
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+// reconcilePolicyServerNetworkPolicy reconciles the NetworkPolicy restricting
+// the ingress traffic of the policy server pods. The NetworkPolicy is deleted
+// when the controller does not manage the NetworkPolicies.
+func (r *PolicyServerReconciler) reconcilePolicyServerNetworkPolicy(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	networkPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      policyServer.NameWithPrefix(),
+			Namespace: r.DeploymentsNamespace,
+		},
+	}
+
+	if !r.ManageNetworkPolicies {
+		return r.deletePolicyServerNetworkPolicy(ctx, policyServer, networkPolicy)
+	}
+
+	_, err := controllerutil.CreateOrPatch(ctx, r.Client, networkPolicy, func() error {
+		return r.updateNetworkPolicy(networkPolicy, policyServer)
+	})
+	if err != nil {
+		return errors.Join(errors.New("failed to create or update NetworkPolicy"), err)
+	}
+
+	return nil
+}
+
+// deletePolicyServerNetworkPolicy deletes the NetworkPolicy of the policy
+// server. A NetworkPolicy with the same name not owned by the policy server,
+// created by the user, is left untouched.
+func (r *PolicyServerReconciler) deletePolicyServerNetworkPolicy(ctx context.Context, policyServer *policiesv1.PolicyServer, networkPolicy *networkingv1.NetworkPolicy) error {
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(networkPolicy), networkPolicy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Join(errors.New("failed to get NetworkPolicy"), err)
+	}
+	if !isOwnedByPolicyServer(networkPolicy, policyServer) {
+		return nil
+	}
+	if err := client.IgnoreNotFound(r.Client.Delete(ctx, networkPolicy)); err != nil {
+		return errors.Join(errors.New("failed to delete NetworkPolicy"), err)
+	}
+
+	return nil
+}
+
+// updateNetworkPolicy allows the ingress traffic to the webhook port only
+// from the Kubernetes API server and the audit scanner pods and, when the
+// metrics are enabled, to the metrics port only from the monitoring
+// namespace.
+func (r *PolicyServerReconciler) updateNetworkPolicy(networkPolicy *networkingv1.NetworkPolicy, policyServer *policiesv1.PolicyServer) error {
+	networkPolicy.Labels = policyServer.CommonLabels()
+
+	webhookPeers := []networkingv1.NetworkPolicyPeer{}
+	for _, cidr := range r.NetworkPolicyAPIServerCIDRs {
+		webhookPeers = append(webhookPeers, networkingv1.NetworkPolicyPeer{
+			IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+		})
+	}
+
+	// The audit scanner runs in the deployments namespace, the namespace of
+	// the NetworkPolicy, and sends its requests straight to the policy
+	// servers.
+	webhookPeers = append(webhookPeers, networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				constants.ComponentLabelKey: constants.ComponentAuditScannerLabelValue,
+				constants.PartOfLabelKey:    constants.PartOfLabelValue,
+			},
+		},
+	})
+
+	ingress := []networkingv1.NetworkPolicyIngressRule{
+		{
+			From: webhookPeers,
+			Ports: []networkingv1.NetworkPolicyPort{
+				{
+					Protocol: ptr.To(corev1.ProtocolTCP),
+					Port:     ptr.To(intstr.FromInt(policyServer.ListenPort())),
+				},
+			},
+		},
+	}
+	if r.MetricsEnabled {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							corev1.LabelMetadataName: r.NetworkPolicyMonitoringNamespace,
+						},
+					},
+				},
+			},
+			Ports: []networkingv1.NetworkPolicyPort{
+				{
+					Protocol: ptr.To(corev1.ProtocolTCP),
+					Port:     ptr.To(intstr.FromInt32(PolicyServerMetricsPort())),
+				},
+			},
+		})
+	}
+
+	networkPolicy.Spec = networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				constants.PolicyServerLabelKey: policyServer.GetName(),
+			},
+		},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		Ingress:     ingress,
+	}
+
+	if err := setPolicyServerOwnerReference(policyServer, networkPolicy, r.Client.Scheme()); err != nil {
+		return errors.Join(errors.New("failed to set policy server NetworkPolicy owner reference"), err)
+	}
+
+	return nil
+}
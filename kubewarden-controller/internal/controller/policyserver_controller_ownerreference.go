@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+// setPolicyServerOwnerReference sets the owner reference of the policy server
+// on a generated resource, according to the mode selected by the owner
+// references annotation. In orphan mode the owner reference of the policy
+// server is removed instead, so that the resource is not garbage collected
+// with it. Unknown modes fall back to the default behavior.
+func setPolicyServerOwnerReference(policyServer *policiesv1.PolicyServer, object metav1.Object, scheme *runtime.Scheme) error {
+	switch policyServer.GetAnnotations()[constants.OwnerReferencesAnnotation] {
+	case constants.OwnerReferencesOrphanMode:
+		object.SetOwnerReferences(slices.DeleteFunc(object.GetOwnerReferences(), func(ownerReference metav1.OwnerReference) bool {
+			return ownerReference.UID == policyServer.GetUID()
+		}))
+		return nil
+	case constants.OwnerReferencesBlockOwnerDeletionMode:
+		return controllerutil.SetOwnerReference(policyServer, object, scheme, controllerutil.WithBlockOwnerDeletion(true))
+	default:
+		return controllerutil.SetOwnerReference(policyServer, object, scheme)
+	}
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+var _ = Describe("Policy server owner references", func() {
+	newPolicyServer := func(mode string) *policiesv1.PolicyServer {
+		policyServer := policiesv1.NewPolicyServerFactory().WithName("owner").Build()
+		policyServer.SetUID(types.UID("policy-server-uid"))
+		if mode != "" {
+			policyServer.SetAnnotations(map[string]string{constants.OwnerReferencesAnnotation: mode})
+		}
+		return policyServer
+	}
+
+	DescribeTable("should set the owner reference according to the annotation",
+		func(mode string, blockOwnerDeletion *bool) {
+			policyServer := newPolicyServer(mode)
+			service := &corev1.Service{}
+
+			Expect(setPolicyServerOwnerReference(policyServer, service, newFakeClientTestScheme())).To(Succeed())
+
+			Expect(service.OwnerReferences).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"APIVersion":         Equal(policiesv1.GroupVersion.String()),
+				"Kind":               Equal("PolicyServer"),
+				"Name":               Equal(policyServer.GetName()),
+				"UID":                Equal(policyServer.GetUID()),
+				"Controller":         BeNil(),
+				"BlockOwnerDeletion": Equal(blockOwnerDeletion),
+			})))
+		},
+		Entry("by default", "", nil),
+		Entry("with an unknown mode", "unknown", nil),
+		Entry("with the block owner deletion mode", constants.OwnerReferencesBlockOwnerDeletionMode, ptr.To(true)),
+	)
+
+	It("should reset the block owner deletion when the default mode is restored", func() {
+		service := &corev1.Service{}
+		Expect(setPolicyServerOwnerReference(newPolicyServer(constants.OwnerReferencesBlockOwnerDeletionMode), service, newFakeClientTestScheme())).To(Succeed())
+
+		Expect(setPolicyServerOwnerReference(newPolicyServer(""), service, newFakeClientTestScheme())).To(Succeed())
+
+		Expect(service.OwnerReferences).To(ConsistOf(HaveField("BlockOwnerDeletion", BeNil())))
+	})
+
+	It("should remove only the policy server owner reference in orphan mode", func() {
+		otherOwnerReference := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: types.UID("other-uid")}
+		service := &corev1.Service{}
+		Expect(setPolicyServerOwnerReference(newPolicyServer(""), service, newFakeClientTestScheme())).To(Succeed())
+		service.OwnerReferences = append(service.OwnerReferences, otherOwnerReference)
+
+		Expect(setPolicyServerOwnerReference(newPolicyServer(constants.OwnerReferencesOrphanMode), service, newFakeClientTestScheme())).To(Succeed())
+
+		Expect(service.OwnerReferences).To(ConsistOf(otherOwnerReference))
+	})
+})
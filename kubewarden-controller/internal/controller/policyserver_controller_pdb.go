@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 
+	corev1 "k8s.io/api/core/v1"
 	k8spoliciesv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -15,12 +17,27 @@ import (
 
 func (r *PolicyServerReconciler) reconcilePolicyServerPodDisruptionBudget(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
 	if policyServer.Spec.MinAvailable != nil || policyServer.Spec.MaxUnavailable != nil {
-		return reconcilePodDisruptionBudget(ctx, policyServer, r.Client, r.DeploymentsNamespace)
+		result, err := reconcilePodDisruptionBudget(ctx, policyServer, r.Client, r.DeploymentsNamespace)
+		if err != nil {
+			return err
+		}
+		r.recordOperationEvent(policyServer, result, "PodDisruptionBudget", policyServer.NameWithPrefix())
+		return nil
 	}
-	return deletePodDisruptionBudget(ctx, policyServer, r.Client, r.DeploymentsNamespace)
+
+	deleted, err := deletePodDisruptionBudget(ctx, policyServer, r.Client, r.DeploymentsNamespace)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		r.recordEvent(policyServer, corev1.EventTypeNormal, "PodDisruptionBudgetDeleted", "Deleted PodDisruptionBudget %s/%s", r.DeploymentsNamespace, policyServer.NameWithPrefix())
+	}
+	return nil
 }
 
-func deletePodDisruptionBudget(ctx context.Context, policyServer *policiesv1.PolicyServer, k8s client.Client, namespace string) error {
+// deletePodDisruptionBudget deletes the PodDisruptionBudget of the policy
+// server, returning whether it existed.
+func deletePodDisruptionBudget(ctx context.Context, policyServer *policiesv1.PolicyServer, k8s client.Client, namespace string) (bool, error) {
 	pdb := &k8spoliciesv1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      policyServer.NameWithPrefix(),
@@ -28,15 +45,18 @@ func deletePodDisruptionBudget(ctx context.Context, policyServer *policiesv1.Pol
 		},
 	}
 
-	err := client.IgnoreNotFound(k8s.Delete(ctx, pdb))
+	err := k8s.Delete(ctx, pdb)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
-		err = errors.Join(errors.New("failed to delete PodDisruptionBudget"), err)
+		return false, errors.Join(errors.New("failed to delete PodDisruptionBudget"), err)
 	}
 
-	return err
+	return true, nil
 }
 
-func reconcilePodDisruptionBudget(ctx context.Context, policyServer *policiesv1.PolicyServer, k8s client.Client, namespace string) error {
+func reconcilePodDisruptionBudget(ctx context.Context, policyServer *policiesv1.PolicyServer, k8s client.Client, namespace string) (controllerutil.OperationResult, error) {
 	commonLabels := policyServer.CommonLabels()
 	pdb := &k8spoliciesv1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
@@ -45,10 +65,10 @@ func reconcilePodDisruptionBudget(ctx context.Context, policyServer *policiesv1.
 			Labels:    commonLabels,
 		},
 	}
-	_, err := controllerutil.CreateOrPatch(ctx, k8s, pdb, func() error {
+	result, err := controllerutil.CreateOrPatch(ctx, k8s, pdb, func() error {
 		pdb.Name = policyServer.NameWithPrefix()
 		pdb.Namespace = namespace
-		if err := controllerutil.SetOwnerReference(policyServer, pdb, k8s.Scheme()); err != nil {
+		if err := setPolicyServerOwnerReference(policyServer, pdb, k8s.Scheme()); err != nil {
 			return errors.Join(errors.New("failed to set policy server PDB owner reference"), err)
 		}
 
@@ -72,5 +92,5 @@ func reconcilePodDisruptionBudget(ctx context.Context, policyServer *policiesv1.
 		err = errors.Join(errors.New("failed to create or update PodDisruptionBudget"), err)
 	}
 
-	return err
+	return result, err
 }
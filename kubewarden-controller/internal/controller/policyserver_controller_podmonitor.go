@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+)
+
+// podMonitorGVK is the GroupVersionKind of the Prometheus Operator
+// PodMonitor. The resource is handled as unstructured to avoid depending on
+// the Prometheus Operator API module.
+func podMonitorGVK() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   "monitoring.coreos.com",
+		Version: "v1",
+		Kind:    "PodMonitor",
+	}
+}
+
+func (r *PolicyServerReconciler) podMonitorEnabled() bool {
+	return r.MetricsEnabled && r.PodMonitorEnabled
+}
+
+func (r *PolicyServerReconciler) reconcilePolicyServerPodMonitor(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	podMonitor := &unstructured.Unstructured{}
+	podMonitor.SetGroupVersionKind(podMonitorGVK())
+	podMonitor.SetName(policyServer.NameWithPrefix())
+	podMonitor.SetNamespace(r.DeploymentsNamespace)
+
+	_, err := controllerutil.CreateOrPatch(ctx, r.Client, podMonitor, func() error {
+		return r.updatePodMonitor(podMonitor, policyServer)
+	})
+	if err != nil {
+		// The Prometheus Operator CRDs are not installed in the cluster.
+		// Do not block the reconciliation of the policy server.
+		if apimeta.IsNoMatchError(err) {
+			r.Log.Info("PodMonitor CRD not found, skipping PodMonitor reconciliation", "policy-server", policyServer.Name)
+			return nil
+		}
+		return errors.Join(errors.New("failed to create or update PodMonitor"), err)
+	}
+
+	return nil
+}
+
+func (r *PolicyServerReconciler) updatePodMonitor(podMonitor *unstructured.Unstructured, policyServer *policiesv1.PolicyServer) error {
+	podMonitor.SetLabels(policyServer.CommonLabels())
+
+	spec := map[string]any{
+		"selector": policyServerMonitorSelector(policyServer),
+		"namespaceSelector": map[string]any{
+			"matchNames": []any{r.DeploymentsNamespace},
+		},
+		"podMetricsEndpoints": []any{
+			map[string]any{
+				// The policy server container does not declare a named
+				// metrics port, the pods are scraped on the port number.
+				"targetPort": int64(PolicyServerMetricsPort()),
+			},
+		},
+	}
+	if err := unstructured.SetNestedMap(podMonitor.Object, spec, "spec"); err != nil {
+		return errors.Join(errors.New("failed to set PodMonitor spec"), err)
+	}
+
+	if err := setPolicyServerOwnerReference(policyServer, podMonitor, r.Client.Scheme()); err != nil {
+		return errors.Join(errors.New("failed to set policy server PodMonitor owner reference"), err)
+	}
+
+	return nil
+}
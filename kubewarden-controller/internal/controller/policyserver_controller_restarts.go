@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+	"github.com/kubewarden/kubewarden-controller/internal/metrics"
+)
+
+// podRestartsTracker keeps track of the container restart counts of the
+// policy server pods, to detect the restarts happened between two
+// reconciliations. The zero value is ready to use.
+type podRestartsTracker struct {
+	mutex sync.Mutex
+	// restartCounts maps the policy server name to the sum of the container
+	// restart counts of each of its pods.
+	restartCounts map[string]map[types.UID]int32
+}
+
+// observe records the restart counts of the given policy server pods and
+// returns how many restarts happened since the previous observation. The pods
+// observed for the first time are used as baseline, their restarts are not
+// counted.
+func (t *podRestartsTracker) observe(policyServerName string, pods []corev1.Pod) int64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.restartCounts == nil {
+		t.restartCounts = make(map[string]map[types.UID]int32)
+	}
+	previousCounts := t.restartCounts[policyServerName]
+
+	var restarts int64
+	currentCounts := make(map[types.UID]int32, len(pods))
+	for _, pod := range pods {
+		var restartCount int32
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			restartCount += containerStatus.RestartCount
+		}
+		currentCounts[pod.UID] = restartCount
+
+		if previousCount, found := previousCounts[pod.UID]; found && restartCount > previousCount {
+			restarts += int64(restartCount - previousCount)
+		}
+	}
+	t.restartCounts[policyServerName] = currentCounts
+
+	return restarts
+}
+
+// forget removes the restart counts recorded for the given policy server.
+func (t *podRestartsTracker) forget(policyServerName string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.restartCounts, policyServerName)
+}
+
+// recordPolicyServerRestarts increments the restarts metric of the policy
+// server with the restarts of its pods observed since the last reconciliation.
+func (r *PolicyServerReconciler) recordPolicyServerRestarts(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	pods := &corev1.PodList{}
+	err := r.Client.List(ctx, pods,
+		client.InNamespace(r.DeploymentsNamespace),
+		client.MatchingLabels{constants.PolicyServerLabelKey: policyServer.Name},
+	)
+	if err != nil {
+		return fmt.Errorf("cannot list policy server pods: %w", err)
+	}
+
+	restarts := r.podRestarts.observe(policyServer.Name, pods.Items)
+	if restarts == 0 {
+		return nil
+	}
+
+	if err = metrics.RecordPolicyServerRestarts(ctx, policyServer, restarts); err != nil {
+		return fmt.Errorf("failed to record policy server restarts metric: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func podWithRestarts(uid types.UID, restartCounts ...int32) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID: uid,
+		},
+	}
+	for _, restartCount := range restartCounts {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			RestartCount: restartCount,
+		})
+	}
+	return pod
+}
+
+var _ = Describe("Policy server pod restarts tracker", func() {
+	It("should use the first observation as baseline", func() {
+		tracker := podRestartsTracker{}
+
+		Expect(tracker.observe("default", []corev1.Pod{podWithRestarts("pod-1", 3)})).To(BeZero())
+	})
+
+	It("should count the increase of the restart counts", func() {
+		tracker := podRestartsTracker{}
+
+		tracker.observe("default", []corev1.Pod{podWithRestarts("pod-1", 0, 1), podWithRestarts("pod-2", 0)})
+		Expect(tracker.observe("default", []corev1.Pod{podWithRestarts("pod-1", 1, 2), podWithRestarts("pod-2", 0)})).To(BeEquivalentTo(2))
+		Expect(tracker.observe("default", []corev1.Pod{podWithRestarts("pod-1", 1, 2), podWithRestarts("pod-2", 3)})).To(BeEquivalentTo(3))
+		Expect(tracker.observe("default", []corev1.Pod{podWithRestarts("pod-1", 1, 2), podWithRestarts("pod-2", 3)})).To(BeZero())
+	})
+
+	It("should not count the restarts of new pods", func() {
+		tracker := podRestartsTracker{}
+
+		tracker.observe("default", []corev1.Pod{podWithRestarts("pod-1", 2)})
+		Expect(tracker.observe("default", []corev1.Pod{podWithRestarts("pod-2", 1)})).To(BeZero())
+		Expect(tracker.observe("default", []corev1.Pod{podWithRestarts("pod-2", 2)})).To(BeEquivalentTo(1))
+	})
+
+	It("should track each policy server separately", func() {
+		tracker := podRestartsTracker{}
+
+		tracker.observe("default", []corev1.Pod{podWithRestarts("pod-1", 0)})
+		tracker.observe("other", []corev1.Pod{podWithRestarts("pod-2", 0)})
+		Expect(tracker.observe("default", []corev1.Pod{podWithRestarts("pod-1", 1)})).To(BeEquivalentTo(1))
+		Expect(tracker.observe("other", []corev1.Pod{podWithRestarts("pod-2", 0)})).To(BeZero())
+	})
+
+	It("should reset the baseline when the policy server is forgotten", func() {
+		tracker := podRestartsTracker{}
+
+		tracker.observe("default", []corev1.Pod{podWithRestarts("pod-1", 0)})
+		tracker.forget("default")
+		Expect(tracker.observe("default", []corev1.Pod{podWithRestarts("pod-1", 4)})).To(BeZero())
+	})
+})
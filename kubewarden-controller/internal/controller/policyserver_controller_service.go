@@ -4,21 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
 )
 
-// This is the port where the Policy Server service will be exposing metrics. Can be overridden
-// by an environment variable KUBEWARDEN_POLICY_SERVER_SERVICES_METRICS_PORT.
-func getMetricsPort() int32 {
+// PolicyServerMetricsPort returns the port where the Policy Server service will be exposing
+// metrics. Can be overridden by an environment variable KUBEWARDEN_POLICY_SERVER_SERVICES_METRICS_PORT.
+func PolicyServerMetricsPort() int32 {
 	metricsPort := int32(constants.PolicyServerMetricsPort)
 	envMetricsPort := os.Getenv(constants.PolicyServerMetricsPortEnvVar)
 	if envMetricsPort != "" {
@@ -40,6 +45,11 @@ func (r *PolicyServerReconciler) reconcilePolicyServerService(ctx context.Contex
 			Namespace: r.DeploymentsNamespace,
 		},
 	}
+
+	if err := r.deleteServiceOnHeadlessModeChange(ctx, &svc, policyServer); err != nil {
+		return err
+	}
+
 	_, err := controllerutil.CreateOrPatch(ctx, r.Client, &svc, func() error {
 		return r.updateService(&svc, policyServer)
 	})
@@ -49,6 +59,31 @@ func (r *PolicyServerReconciler) reconcilePolicyServerService(ctx context.Contex
 	return nil
 }
 
+// deleteServiceOnHeadlessModeChange deletes the policy server Service when
+// the user switches it from or to headless mode. The clusterIP field of a
+// Service is immutable, therefore the Service must be recreated.
+func (r *PolicyServerReconciler) deleteServiceOnHeadlessModeChange(ctx context.Context, svc *corev1.Service, policyServer *policiesv1.PolicyServer) error {
+	existingSvc := corev1.Service{}
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(svc), &existingSvc)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("cannot get policy-server service: %w", err)
+	}
+
+	isHeadless := existingSvc.Spec.ClusterIP == corev1.ClusterIPNone
+	if isHeadless == policyServer.Spec.HeadlessService {
+		return nil
+	}
+
+	if err = r.Client.Delete(ctx, &existingSvc); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("cannot delete policy-server service: %w", err)
+	}
+
+	return nil
+}
+
 func (r *PolicyServerReconciler) updateService(svc *corev1.Service, policyServer *policiesv1.PolicyServer) error {
 	commonLabels := policyServer.CommonLabels()
 
@@ -61,13 +96,15 @@ func (r *PolicyServerReconciler) updateService(svc *corev1.Service, policyServer
 		templateLabels[key] = value
 	}
 	svc.Labels = templateLabels
+	configureServiceAnnotations(svc, policyServer)
 
 	svc.Spec = corev1.ServiceSpec{
+		Type: corev1.ServiceTypeClusterIP,
 		Ports: []corev1.ServicePort{
 			{
 				Name:       "policy-server",
 				Port:       constants.PolicyServerServicePort,
-				TargetPort: intstr.FromInt(constants.PolicyServerListenPort),
+				TargetPort: intstr.FromInt(policyServer.ListenPort()),
 				Protocol:   corev1.ProtocolTCP,
 			},
 		},
@@ -76,20 +113,49 @@ func (r *PolicyServerReconciler) updateService(svc *corev1.Service, policyServer
 			constants.PartOfLabelKey:   commonLabels[constants.PartOfLabelKey],
 		},
 	}
+	if policyServer.Spec.ServiceType != "" {
+		svc.Spec.Type = policyServer.Spec.ServiceType
+	}
+	if policyServer.Spec.HeadlessService {
+		svc.Spec.ClusterIP = corev1.ClusterIPNone
+	}
 	if r.MetricsEnabled {
 		svc.Spec.Ports = append(
 			svc.Spec.Ports,
 			corev1.ServicePort{
 				Name:     "metrics",
-				Port:     getMetricsPort(),
+				Port:     PolicyServerMetricsPort(),
 				Protocol: corev1.ProtocolTCP,
 			},
 		)
 	}
 
-	if err := controllerutil.SetOwnerReference(policyServer, svc, r.Client.Scheme()); err != nil {
+	if err := setPolicyServerOwnerReference(policyServer, svc, r.Client.Scheme()); err != nil {
 		return errors.Join(errors.New("failed to set policy server service owner reference"), err)
 	}
 
 	return nil
 }
+
+// configureServiceAnnotations sets the annotations of the PolicyServer on the
+// Service. The keys of the annotations set are stored in an annotation, to
+// remove them from the Service once removed from the PolicyServer without
+// touching the annotations set by other tools.
+func configureServiceAnnotations(svc *corev1.Service, policyServer *policiesv1.PolicyServer) {
+	if previousKeys, found := svc.Annotations[constants.PolicyServerServiceAnnotationsAnnotation]; found {
+		for _, key := range strings.Split(previousKeys, ",") {
+			delete(svc.Annotations, key)
+		}
+		delete(svc.Annotations, constants.PolicyServerServiceAnnotationsAnnotation)
+	}
+
+	if len(policyServer.Spec.ServiceAnnotations) == 0 {
+		return
+	}
+
+	if svc.Annotations == nil {
+		svc.Annotations = make(map[string]string)
+	}
+	maps.Copy(svc.Annotations, policyServer.Spec.ServiceAnnotations)
+	svc.Annotations[constants.PolicyServerServiceAnnotationsAnnotation] = strings.Join(slices.Sorted(maps.Keys(policyServer.Spec.ServiceAnnotations)), ",")
+}
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+)
+
+// policyServerServiceAccountName returns the name of the ServiceAccount used by
+// the policy server pods.
+func policyServerServiceAccountName(policyServer *policiesv1.PolicyServer) string {
+	if policyServer.Spec.DedicatedServiceAccount {
+		return policyServer.NameWithPrefix()
+	}
+	return policyServer.Spec.ServiceAccountName
+}
+
+// policyServerClusterRoleName returns the name of the ClusterRole and
+// ClusterRoleBinding granting access to the context-aware resources to the
+// policy server dedicated ServiceAccount.
+func policyServerClusterRoleName(policyServer *policiesv1.PolicyServer) string {
+	return "kubewarden-" + policyServer.NameWithPrefix()
+}
+
+func (r *PolicyServerReconciler) reconcilePolicyServerServiceAccount(ctx context.Context, policyServer *policiesv1.PolicyServer, policies []policiesv1.Policy) error {
+	if !policyServer.Spec.DedicatedServiceAccount {
+		return r.deletePolicyServerServiceAccount(ctx, policyServer)
+	}
+
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      policyServerServiceAccountName(policyServer),
+			Namespace: r.DeploymentsNamespace,
+		},
+	}
+	_, err := controllerutil.CreateOrPatch(ctx, r.Client, serviceAccount, func() error {
+		serviceAccount.Labels = policyServer.CommonLabels()
+		if err := controllerutil.SetOwnerReference(policyServer, serviceAccount, r.Client.Scheme()); err != nil {
+			return errors.Join(errors.New("failed to set policy server ServiceAccount owner reference"), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Join(errors.New("failed to create or update ServiceAccount"), err)
+	}
+
+	// The ClusterRole grants permissions the controller does not hold: the
+	// API server accepts it only when the controller is allowed to bind and
+	// escalate the ClusterRole by name.
+	clusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: policyServerClusterRoleName(policyServer),
+		},
+	}
+	_, err = controllerutil.CreateOrPatch(ctx, r.Client, clusterRole, func() error {
+		clusterRole.Labels = policyServer.CommonLabels()
+		clusterRole.Rules = r.buildPolicyServerClusterRoleRules(policies)
+		if err := controllerutil.SetOwnerReference(policyServer, clusterRole, r.Client.Scheme()); err != nil {
+			return errors.Join(errors.New("failed to set policy server ClusterRole owner reference"), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Join(errors.New("failed to create or update ClusterRole"), err)
+	}
+
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: policyServerClusterRoleName(policyServer),
+		},
+	}
+	_, err = controllerutil.CreateOrPatch(ctx, r.Client, clusterRoleBinding, func() error {
+		clusterRoleBinding.Labels = policyServer.CommonLabels()
+		clusterRoleBinding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRole.Name,
+		}
+		clusterRoleBinding.Subjects = []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      serviceAccount.Name,
+				Namespace: serviceAccount.Namespace,
+			},
+		}
+		if err := controllerutil.SetOwnerReference(policyServer, clusterRoleBinding, r.Client.Scheme()); err != nil {
+			return errors.Join(errors.New("failed to set policy server ClusterRoleBinding owner reference"), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Join(errors.New("failed to create or update ClusterRoleBinding"), err)
+	}
+
+	return nil
+}
+
+// buildPolicyServerClusterRoleRules returns the rules granting read access to
+// the context-aware resources of the given policies. Resources whose kind
+// cannot be mapped to an API resource are skipped.
+func (r *PolicyServerReconciler) buildPolicyServerClusterRoleRules(policies []policiesv1.Policy) []rbacv1.PolicyRule {
+	resourcesByGroup := make(map[string][]string)
+	for _, policy := range policies {
+		for _, contextAwareResource := range policy.GetContextAwareResources() {
+			groupVersion, err := schema.ParseGroupVersion(contextAwareResource.APIVersion)
+			if err != nil {
+				r.Log.Error(err, "cannot parse context-aware resource apiVersion", "policy", policy.GetUniqueName(), "apiVersion", contextAwareResource.APIVersion)
+				continue
+			}
+			mapping, err := r.Client.RESTMapper().RESTMapping(groupVersion.WithKind(contextAwareResource.Kind).GroupKind(), groupVersion.Version)
+			if err != nil {
+				r.Log.Error(err, "cannot find the API resource of the context-aware resource", "policy", policy.GetUniqueName(), "apiVersion", contextAwareResource.APIVersion, "kind", contextAwareResource.Kind)
+				continue
+			}
+			resources := resourcesByGroup[groupVersion.Group]
+			if !slices.Contains(resources, mapping.Resource.Resource) {
+				resourcesByGroup[groupVersion.Group] = append(resources, mapping.Resource.Resource)
+			}
+		}
+	}
+
+	groups := make([]string, 0, len(resourcesByGroup))
+	for group := range resourcesByGroup {
+		groups = append(groups, group)
+	}
+	slices.Sort(groups)
+
+	rules := make([]rbacv1.PolicyRule, 0, len(groups))
+	for _, group := range groups {
+		resources := resourcesByGroup[group]
+		slices.Sort(resources)
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{group},
+			Resources: resources,
+			Verbs:     []string{"get", "list", "watch"},
+		})
+	}
+	return rules
+}
+
+func (r *PolicyServerReconciler) deletePolicyServerServiceAccount(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	objects := []client.Object{
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: policyServerClusterRoleName(policyServer),
+			},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: policyServerClusterRoleName(policyServer),
+			},
+		},
+		&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      policyServer.NameWithPrefix(),
+				Namespace: r.DeploymentsNamespace,
+			},
+		},
+	}
+	for _, object := range objects {
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(object), object); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return errors.Join(errors.New("failed to get policy server dedicated ServiceAccount resources"), err)
+			}
+			continue
+		}
+		// Do not delete resources with the same name created by the user
+		if !isOwnedByPolicyServer(object, policyServer) {
+			continue
+		}
+		if err := client.IgnoreNotFound(r.Client.Delete(ctx, object)); err != nil {
+			return errors.Join(errors.New("failed to delete policy server dedicated ServiceAccount resources"), err)
+		}
+	}
+
+	return nil
+}
+
+func isOwnedByPolicyServer(object client.Object, policyServer *policiesv1.PolicyServer) bool {
+	return slices.ContainsFunc(object.GetOwnerReferences(), func(ownerReference metav1.OwnerReference) bool {
+		return ownerReference.UID == policyServer.GetUID()
+	})
+}
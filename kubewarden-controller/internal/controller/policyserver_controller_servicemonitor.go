@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+// serviceMonitorGVK is the GroupVersionKind of the Prometheus Operator
+// ServiceMonitor. The resource is handled as unstructured to avoid depending
+// on the Prometheus Operator API module.
+func serviceMonitorGVK() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   "monitoring.coreos.com",
+		Version: "v1",
+		Kind:    "ServiceMonitor",
+	}
+}
+
+// policyServerMonitorSelector returns the label selector of the Prometheus
+// Operator monitors of the policy server. It matches both the policy server
+// Service and pods, which share the common labels of the policy server.
+func policyServerMonitorSelector(policyServer *policiesv1.PolicyServer) map[string]any {
+	return map[string]any{
+		"matchLabels": map[string]any{
+			constants.InstanceLabelKey:     policyServer.CommonLabels()[constants.InstanceLabelKey],
+			constants.PolicyServerLabelKey: policyServer.GetName(),
+		},
+	}
+}
+
+func (r *PolicyServerReconciler) serviceMonitorEnabled() bool {
+	return r.MetricsEnabled && r.ServiceMonitorEnabled
+}
+
+func (r *PolicyServerReconciler) newServiceMonitor(policyServer *policiesv1.PolicyServer) *unstructured.Unstructured {
+	serviceMonitor := &unstructured.Unstructured{}
+	serviceMonitor.SetGroupVersionKind(serviceMonitorGVK())
+	serviceMonitor.SetName(policyServer.NameWithPrefix())
+	serviceMonitor.SetNamespace(r.DeploymentsNamespace)
+	return serviceMonitor
+}
+
+func (r *PolicyServerReconciler) reconcilePolicyServerServiceMonitor(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	serviceMonitor := r.newServiceMonitor(policyServer)
+
+	_, err := controllerutil.CreateOrPatch(ctx, r.Client, serviceMonitor, func() error {
+		return r.updateServiceMonitor(serviceMonitor, policyServer)
+	})
+	if err != nil {
+		// The Prometheus Operator CRDs are not installed in the cluster.
+		// Do not block the reconciliation of the policy server.
+		if apimeta.IsNoMatchError(err) {
+			r.Log.Info("ServiceMonitor CRD not found, skipping ServiceMonitor reconciliation", "policy-server", policyServer.Name)
+			return nil
+		}
+		return errors.Join(errors.New("failed to create or update ServiceMonitor"), err)
+	}
+
+	return nil
+}
+
+// deletePolicyServerServiceMonitor deletes the ServiceMonitor of the policy
+// server, left behind when the ServiceMonitor creation has been disabled. A
+// ServiceMonitor with the same name not owned by the policy server is left
+// untouched, as well as the clusters without the Prometheus Operator CRDs.
+func (r *PolicyServerReconciler) deletePolicyServerServiceMonitor(ctx context.Context, policyServer *policiesv1.PolicyServer) error {
+	serviceMonitor := r.newServiceMonitor(policyServer)
+
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(serviceMonitor), serviceMonitor); err != nil {
+		if apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+			return nil
+		}
+		return errors.Join(errors.New("failed to get ServiceMonitor"), err)
+	}
+	if !isOwnedByPolicyServer(serviceMonitor, policyServer) {
+		return nil
+	}
+	if err := client.IgnoreNotFound(r.Client.Delete(ctx, serviceMonitor)); err != nil {
+		return errors.Join(errors.New("failed to delete ServiceMonitor"), err)
+	}
+
+	return nil
+}
+
+func (r *PolicyServerReconciler) updateServiceMonitor(serviceMonitor *unstructured.Unstructured, policyServer *policiesv1.PolicyServer) error {
+	serviceMonitor.SetLabels(policyServer.CommonLabels())
+
+	spec := map[string]any{
+		"selector": policyServerMonitorSelector(policyServer),
+		"namespaceSelector": map[string]any{
+			"matchNames": []any{r.DeploymentsNamespace},
+		},
+		"endpoints": []any{
+			map[string]any{
+				"port": "metrics",
+			},
+		},
+	}
+	if err := unstructured.SetNestedMap(serviceMonitor.Object, spec, "spec"); err != nil {
+		return errors.Join(errors.New("failed to set ServiceMonitor spec"), err)
+	}
+
+	if err := setPolicyServerOwnerReference(policyServer, serviceMonitor, r.Client.Scheme()); err != nil {
+		return errors.Join(errors.New("failed to set policy server ServiceMonitor owner reference"), err)
+	}
+
+	return nil
+}
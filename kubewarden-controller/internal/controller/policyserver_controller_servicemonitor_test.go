@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+)
+
+var _ = Describe("Policy server ServiceMonitor", func() {
+	ctx := context.Background()
+
+	var (
+		reconciler   *PolicyServerReconciler
+		policyServer *policiesv1.PolicyServer
+	)
+
+	BeforeEach(func() {
+		policyServer = policiesv1.NewPolicyServerFactory().WithName("service-monitor").Build()
+		policyServer.SetUID(types.UID("service-monitor-uid"))
+
+		restMapper := apimeta.NewDefaultRESTMapper(nil)
+		restMapper.Add(serviceMonitorGVK(), apimeta.RESTScopeNamespace)
+		reconciler = &PolicyServerReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(newFakeClientTestScheme()).
+				WithRESTMapper(restMapper).
+				Build(),
+			DeploymentsNamespace: deploymentsNamespace,
+			TelemetryConfiguration: TelemetryConfiguration{
+				MetricsEnabled:        true,
+				ServiceMonitorEnabled: true,
+			},
+		}
+	})
+
+	getServiceMonitor := func() error {
+		serviceMonitor := reconciler.newServiceMonitor(policyServer)
+		return reconciler.Get(ctx, client.ObjectKeyFromObject(serviceMonitor), serviceMonitor)
+	}
+
+	It("should delete the ServiceMonitor when its creation is disabled", func() {
+		Expect(reconciler.reconcilePolicyServerServiceMonitor(ctx, policyServer)).To(Succeed())
+		Expect(getServiceMonitor()).To(Succeed())
+
+		reconciler.ServiceMonitorEnabled = false
+		Expect(reconciler.serviceMonitorEnabled()).To(BeFalse())
+		Expect(reconciler.deletePolicyServerServiceMonitor(ctx, policyServer)).To(Succeed())
+
+		Expect(apierrors.IsNotFound(getServiceMonitor())).To(BeTrue())
+		Expect(reconciler.deletePolicyServerServiceMonitor(ctx, policyServer)).To(Succeed())
+	})
+
+	It("should leave the ServiceMonitor not owned by the policy server untouched", func() {
+		serviceMonitor := reconciler.newServiceMonitor(policyServer)
+		Expect(reconciler.Create(ctx, serviceMonitor)).To(Succeed())
+
+		Expect(reconciler.deletePolicyServerServiceMonitor(ctx, policyServer)).To(Succeed())
+
+		Expect(getServiceMonitor()).To(Succeed())
+	})
+
+	It("should not fail when the ServiceMonitor CRD is not installed", func() {
+		reconciler.Client = fake.NewClientBuilder().WithScheme(newFakeClientTestScheme()).Build()
+
+		Expect(reconciler.deletePolicyServerServiceMonitor(ctx, policyServer)).To(Succeed())
+	})
+})
@@ -21,18 +21,31 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"path/filepath"
+	"slices"
 
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	k8spoliciesv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
 	"github.com/kubewarden/kubewarden-controller/internal/constants"
@@ -132,6 +145,84 @@ var _ = Describe("PolicyServer controller", func() {
 			})))
 		})
 
+		It("should use the policy server nodeSelector configuration in the policy server deployment", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "linux"}
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/os": "linux"}))
+		})
+
+		It("should use the policy server nodeName in the policy server deployment", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.NodeName = "debug-node"
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.Template.Spec.NodeName).To(Equal("debug-node"))
+		})
+
+		It("should disable the service account token in the policy server deployment", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.AutomountServiceAccountToken = ptr.To(false)
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.Template.Spec.AutomountServiceAccountToken).To(HaveValue(BeFalse()))
+		})
+
+		It("should set the preStop sleep hook of the policy server container", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.PreStopSleepSeconds = ptr.To(int32(5))
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.Template.Spec.Containers[0].Lifecycle).To(Equal(&corev1.Lifecycle{
+				PreStop: &corev1.LifecycleHandler{
+					Sleep: &corev1.SleepAction{Seconds: 5},
+				},
+			}))
+		})
+
+		It("should use the policy server DNS configuration in the policy server deployment", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.DNSPolicy = ptr.To(corev1.DNSNone)
+			policyServer.Spec.DNSConfig = &corev1.PodDNSConfig{
+				Nameservers: []string{"10.0.0.53"},
+				Searches:    []string{"registry.internal"},
+			}
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.Template.Spec.DNSPolicy).To(Equal(corev1.DNSNone))
+			Expect(deployment.Spec.Template.Spec.DNSConfig).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Nameservers": Equal([]string{"10.0.0.53"}),
+				"Searches":    Equal([]string{"registry.internal"}),
+			})))
+		})
+
+		It("should use the default DNS policy in the policy server deployment when not set", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.Template.Spec.DNSPolicy).To(Equal(corev1.DNSClusterFirst))
+			Expect(deployment.Spec.Template.Spec.DNSConfig).To(BeNil())
+		})
+
 		It("should use the policy server priorityClassName configuration in the policy server deployment", func() {
 			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
 			defaultSystemClusterCriticalPriorityClass := "system-cluster-critical" // one of the default highest PriorityClass
@@ -144,6 +235,94 @@ var _ = Describe("PolicyServer controller", func() {
 			Expect(deployment.Spec.Template.Spec.PriorityClassName).To(Equal(defaultSystemClusterCriticalPriorityClass))
 		})
 
+		It("should use the default revisionHistoryLimit in the policy server deployment", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.RevisionHistoryLimit).To(PointTo(Equal(int32(constants.PolicyServerDefaultRevisionHistoryLimit))))
+		})
+
+		It("should use the policy server revisionHistoryLimit configuration in the policy server deployment", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.RevisionHistoryLimit = ptr.To(int32(1))
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.RevisionHistoryLimit).To(PointTo(Equal(int32(1))))
+		})
+
+		It("should use the default progressDeadlineSeconds in the policy server deployment", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.ProgressDeadlineSeconds).To(PointTo(Equal(int32(constants.PolicyServerDefaultProgressDeadlineSeconds))))
+		})
+
+		It("should use the policy server progressDeadlineSeconds configuration in the policy server deployment", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.ProgressDeadlineSeconds = ptr.To(int32(120))
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.ProgressDeadlineSeconds).To(PointTo(Equal(int32(120))))
+		})
+
+		It("should use the policy server runtimeClassName configuration in the policy server deployment", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.RuntimeClassName = ptr.To("gvisor")
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.Template.Spec.RuntimeClassName).To(PointTo(Equal("gvisor")))
+		})
+
+		It("should use the RollingUpdate strategy by default in the policy server deployment", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.Strategy.Type).To(Equal(appsv1.RollingUpdateDeploymentStrategyType))
+		})
+
+		It("should use the policy server strategy configuration in the policy server deployment", func() {
+			maxSurge := intstr.FromInt(2)
+			maxUnavailable := intstr.FromInt(0)
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.Strategy = &appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{
+					MaxSurge:       &maxSurge,
+					MaxUnavailable: &maxUnavailable,
+				},
+			}
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.Strategy).To(MatchFields(IgnoreExtras, Fields{
+				"Type": Equal(appsv1.RollingUpdateDeploymentStrategyType),
+				"RollingUpdate": PointTo(MatchFields(IgnoreExtras, Fields{
+					"MaxSurge":       PointTo(Equal(maxSurge)),
+					"MaxUnavailable": PointTo(Equal(maxUnavailable)),
+				})),
+			}))
+		})
+
 		It("should create policy server deployment with some default configuration", func() {
 			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
 			createPolicyServerAndWaitForItsService(ctx, policyServer)
@@ -248,6 +427,80 @@ var _ = Describe("PolicyServer controller", func() {
 			})))
 		})
 
+		It("should create the policy server deployment with the localhost seccomp profile", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			runAsUser := int64(1000)
+			policyServer.Spec.SecurityContexts = policiesv1.PolicyServerSecurity{
+				Pod: &corev1.PodSecurityContext{
+					RunAsUser: &runAsUser,
+				},
+				SeccompProfile: &corev1.SeccompProfile{
+					Type:             corev1.SeccompProfileTypeLocalhost,
+					LocalhostProfile: ptr.To("profiles/policy-server.json"),
+				},
+			}
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.Template.Spec.SecurityContext).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"RunAsUser": PointTo(BeNumerically("==", 1000)),
+				"SeccompProfile": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":             Equal(corev1.SeccompProfileTypeLocalhost),
+					"LocalhostProfile": PointTo(Equal("profiles/policy-server.json")),
+				})),
+			})))
+		})
+
+		It("should use the policy server probes configuration in the policy server deployment", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.Probes = policiesv1.ProbesConfiguration{
+				Readiness: &policiesv1.ProbeConfiguration{
+					PeriodSeconds:    ptr.To[int32](5),
+					SuccessThreshold: ptr.To[int32](2),
+					FailureThreshold: ptr.To[int32](10),
+				},
+				Liveness: &policiesv1.ProbeConfiguration{
+					InitialDelaySeconds: ptr.To[int32](30),
+					SuccessThreshold:    ptr.To[int32](1),
+					FailureThreshold:    ptr.To[int32](3),
+				},
+			}
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.ReadinessProbe).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"PeriodSeconds":    BeNumerically("==", 5),
+				"SuccessThreshold": BeNumerically("==", 2),
+				"FailureThreshold": BeNumerically("==", 10),
+			})))
+			Expect(container.LivenessProbe).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"ProbeHandler": MatchFields(IgnoreExtras, Fields{
+					"HTTPGet": PointTo(MatchFields(IgnoreExtras, Fields{
+						"Path": Equal(constants.PolicyServerReadinessProbe),
+					})),
+				}),
+				"InitialDelaySeconds": BeNumerically("==", 30),
+				"SuccessThreshold":    BeNumerically("==", 1),
+				"FailureThreshold":    BeNumerically("==", 3),
+			})))
+		})
+
+		It("should not define a liveness probe in the policy server deployment by default", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.Spec.Template.Spec.Containers[0].ReadinessProbe).ToNot(BeNil())
+			Expect(deployment.Spec.Template.Spec.Containers[0].LivenessProbe).To(BeNil())
+		})
+
 		It("should create the policy server configmap empty if no policies are assigned ", func() {
 			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
 			createPolicyServerAndWaitForItsService(ctx, policyServer)
@@ -659,136 +912,700 @@ var _ = Describe("PolicyServer controller", func() {
 			}).Should(Succeed())
 		})
 
-		It("should create the policy server secrets", func() {
+		It("should create deployment and service without owner reference in orphan mode", func() {
 			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.SetAnnotations(map[string]string{constants.OwnerReferencesAnnotation: constants.OwnerReferencesOrphanMode})
 			createPolicyServerAndWaitForItsService(ctx, policyServer)
 
 			Eventually(func() error {
-				secret, err := getTestPolicyServerSecret(ctx, policyServerName)
+				deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
 				if err != nil {
 					return err
 				}
-				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				Expect(deployment.OwnerReferences).To(BeEmpty())
+				service, err := getTestPolicyServerService(ctx, policyServerName)
 				if err != nil {
 					return err
 				}
-
-				By("creating a secret containing the TLS certificate and key")
-				Expect(secret.Data).To(HaveKey(constants.ServerCert))
-				Expect(secret.Data).To(HaveKey(constants.ServerPrivateKey))
-
-				By("setting the secret owner reference")
-				Expect(secret.OwnerReferences).To(ContainElement(
-					MatchFields(IgnoreExtras, Fields{
-						"UID":  Equal(policyServer.GetUID()),
-						"Name": Equal(policyServer.GetName()),
-						// FIXME: for some reason GroupVersionKind is not set
-						// "Kind":       Equal(policyServer.GetObjectKind().GroupVersionKind().Kind),
-						// "APIVersion": Equal(policyServer.GetObjectKind().GroupVersionKind().GroupVersion().String()),
-					}),
-				))
+				Expect(service.OwnerReferences).To(BeEmpty())
 				return nil
-			}).Should(Succeed())
+			}, timeout, pollInterval).Should(Succeed())
 		})
 
-		It("should enable mTLS in the policy server deployment", func() {
+		It("should create a ClusterIP service by default", func() {
 			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
 			createPolicyServerAndWaitForItsService(ctx, policyServer)
 
-			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			service, err := getTestPolicyServerService(ctx, policyServerName)
 			Expect(err).ToNot(HaveOccurred())
+			Expect(service.Spec.ClusterIP).ToNot(BeEmpty())
+			Expect(service.Spec.ClusterIP).ToNot(Equal(corev1.ClusterIPNone))
+		})
 
-			container := deployment.Spec.Template.Spec.Containers[0]
+		It("should create a headless service when the policy server has headlessService set", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.HeadlessService = true
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
 
-			kubewardenCAPath := filepath.Join(kubewardenCAVolumePath, constants.CARootCert)
-			clientCAPath := filepath.Join(clientCAVolumePath, constants.ClientCACert)
-			By("specifing the client ca certificate")
-			Expect(container.Env).To(ContainElement(MatchFields(IgnoreExtras, Fields{
-				"Name":  Equal("KUBEWARDEN_CLIENT_CA_FILE"),
-				"Value": Equal(fmt.Sprintf("%s,%s", kubewardenCAPath, clientCAPath)),
+			service, err := getTestPolicyServerService(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(service.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+			Expect(service.Spec.Ports).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Port":       Equal(int32(constants.PolicyServerServicePort)),
+				"TargetPort": Equal(intstr.IntOrString{IntVal: int32(constants.PolicyServerListenPort)}),
 			})))
+		})
 
-			By("mounting the kubewarden CA Secret")
-			Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(MatchFields(IgnoreExtras, Fields{
-				"Name": Equal(kubewardenCAVolumeName),
-				"VolumeSource": MatchFields(IgnoreExtras, Fields{
-					"Secret": PointTo(MatchFields(IgnoreExtras, Fields{
-						"SecretName": Equal(constants.CARootSecretName),
-						"Items": ConsistOf(MatchFields(IgnoreExtras, Fields{
-							"Key":  Equal(constants.CARootCert),
-							"Path": Equal(constants.CARootCert),
-						})),
-					})),
-				}),
-			})))
-			Expect(container.VolumeMounts).To(ContainElement(MatchFields(IgnoreExtras, Fields{
-				"Name":      Equal(kubewardenCAVolumeName),
-				"MountPath": Equal(kubewardenCAVolumePath),
-			})))
+		It("should use the ports set in the policy server env", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.Env = []corev1.EnvVar{
+				{Name: constants.PolicyServerListenPortEnvVar, Value: "9443"},
+				{Name: constants.PolicyServerReadinessProbePortEnvVar, Value: "9081"},
+			}
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
 
-			By("mounting the client CA ConfigMap")
-			Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(MatchFields(IgnoreExtras, Fields{
-				"Name": Equal(clientCAVolumeName),
-				"VolumeSource": MatchFields(IgnoreExtras, Fields{
-					"ConfigMap": PointTo(MatchFields(IgnoreExtras, Fields{
-						"LocalObjectReference": MatchFields(IgnoreExtras, Fields{
-							"Name": Equal(clientCAConfigMapName),
-						}),
-						"Items": ConsistOf(MatchFields(IgnoreExtras, Fields{
-							"Key":  Equal(constants.ClientCACert),
-							"Path": Equal(constants.ClientCACert),
-						})),
-					})),
-				}),
-			})))
-			Expect(container.VolumeMounts).To(ContainElement(MatchFields(IgnoreExtras, Fields{
-				"Name":      Equal(clientCAVolumeName),
-				"MountPath": Equal(clientCAVolumePath),
+			service, err := getTestPolicyServerService(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(service.Spec.Ports).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Port":       Equal(int32(constants.PolicyServerServicePort)),
+				"TargetPort": Equal(intstr.FromInt(9443)),
 			})))
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.HTTPGet.Port).To(Equal(intstr.FromInt(9081)))
 		})
 
-		It("should set the configMap version as a deployment annotation", func() {
+		It("should create a service of the policy server service type", func() {
 			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.ServiceType = corev1.ServiceTypeNodePort
 			createPolicyServerAndWaitForItsService(ctx, policyServer)
 
-			configmap, err := getTestPolicyServerConfigMap(ctx, policyServerName)
+			service, err := getTestPolicyServerService(ctx, policyServerName)
 			Expect(err).ToNot(HaveOccurred())
-
-			Eventually(func() error {
-				deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
-				if err != nil {
-					return err
-				}
-				if deployment.GetAnnotations()[constants.PolicyServerDeploymentConfigVersionAnnotation] != configmap.GetResourceVersion() {
-					return errors.New("deployment configmap version did not change")
-				}
-				if deployment.Spec.Template.GetLabels()[constants.PolicyServerDeploymentConfigVersionAnnotation] != configmap.GetResourceVersion() {
-					return errors.New("pod configmap version did not change")
-				}
-				return nil
-			}, timeout, pollInterval).Should(Succeed())
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+			Expect(service.Spec.Ports).To(ContainElement(HaveField("NodePort", Not(BeZero()))))
 		})
 
-		It("should update the configMap version after adding a policy", func() {
+		It("should reconcile the service annotations without touching the annotations set by other tools", func() {
 			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.ServiceAnnotations = map[string]string{
+				"service.beta.kubernetes.io/azure-load-balancer-internal": "true",
+				"mesh.example.com/inject":                                 "enabled",
+			}
 			createPolicyServerAndWaitForItsService(ctx, policyServer)
 
-			initalConfigMap, err := getTestPolicyServerConfigMap(ctx, policyServerName)
+			service, err := getTestPolicyServerService(ctx, policyServerName)
 			Expect(err).ToNot(HaveOccurred())
+			Expect(service.Annotations).To(And(
+				HaveKeyWithValue("service.beta.kubernetes.io/azure-load-balancer-internal", "true"),
+				HaveKeyWithValue("mesh.example.com/inject", "enabled"),
+			))
+			serviceUID := service.UID
 
+			By("setting an annotation with another tool")
+			service.Annotations["example.com/external"] = "value"
+			Expect(k8sClient.Update(ctx, service)).To(Succeed())
+
+			By("removing an annotation from the policy server")
 			Eventually(func() error {
-				deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
 				if err != nil {
 					return err
 				}
-				if deployment.GetAnnotations()[constants.PolicyServerDeploymentConfigVersionAnnotation] != initalConfigMap.GetResourceVersion() {
-					return errors.New("deployment configmap version did not change")
-				}
-				if deployment.Spec.Template.GetLabels()[constants.PolicyServerDeploymentConfigVersionAnnotation] != initalConfigMap.GetResourceVersion() {
-					return errors.New("pod configmap version did not change")
+				policyServer.Spec.ServiceAnnotations = map[string]string{
+					"service.beta.kubernetes.io/azure-load-balancer-internal": "false",
 				}
-				return nil
-			}, timeout, pollInterval).Should(Succeed())
+				return k8sClient.Update(ctx, policyServer)
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				service, err := getTestPolicyServerService(ctx, policyServerName)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(service.UID).To(Equal(serviceUID))
+				g.Expect(service.Annotations).To(And(
+					HaveKeyWithValue("service.beta.kubernetes.io/azure-load-balancer-internal", "false"),
+					HaveKeyWithValue("example.com/external", "value"),
+					Not(HaveKey("mesh.example.com/inject")),
+				))
+			}, timeout, pollInterval).Should(Succeed())
+		})
+
+		It("should log a summary line with the outcome of each sub-reconcile", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			var logLines []string
+			reconciler := &PolicyServerReconciler{
+				Client: k8sClient,
+				Log: funcr.New(func(_, args string) {
+					logLines = append(logLines, args)
+				}, funcr.Options{}),
+				DeploymentsNamespace:  deploymentsNamespace,
+				ClientCAConfigMapName: clientCAConfigMapName,
+			}
+			_, _ = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: policyServerName}})
+
+			Expect(logLines).To(ContainElement(And(
+				ContainSubstring(`"msg"="PolicyServer reconciliation summary"`),
+				ContainSubstring(fmt.Sprintf(`"policy-server"=%q`, policyServerName)),
+				ContainSubstring(`"certSecret"="succeeded"`),
+				ContainSubstring(`"configMap"="succeeded"`),
+				ContainSubstring(`"podDisruptionBudget"="succeeded"`),
+				ContainSubstring(`"deployment"="succeeded"`),
+				ContainSubstring(`"service"="succeeded"`),
+			)))
+		})
+
+		It("should log the failed sub-reconciles in the summary line", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			var logLines []string
+			reconciler := &PolicyServerReconciler{
+				Client: k8sClient,
+				Log: funcr.New(func(_, args string) {
+					logLines = append(logLines, args)
+				}, funcr.Options{}),
+				// The CA root secret does not exist in this namespace, so the
+				// certificate secret reconciliation fails
+				DeploymentsNamespace: "non-existing-namespace",
+			}
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: policyServerName}})
+			Expect(err).To(HaveOccurred())
+
+			Expect(logLines).To(ContainElement(And(
+				ContainSubstring(`"msg"="PolicyServer reconciliation summary"`),
+				ContainSubstring(`"certSecret"="failed"`),
+				ContainSubstring(`"configMap"="failed"`),
+				ContainSubstring(`"podDisruptionBudget"="succeeded"`),
+				ContainSubstring(`"deployment"="skipped"`),
+				ContainSubstring(`"service"="failed"`),
+				ContainSubstring(`"serviceMonitor"="skipped"`),
+			)))
+		})
+
+		It("should report the error of each failed sub-reconcile in its own condition", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			reconciler := &PolicyServerReconciler{
+				Client: k8sClient,
+				Log:    logr.Discard(),
+				// The resources cannot be created in a namespace that does
+				// not exist, so most of the sub-reconciles fail
+				DeploymentsNamespace: "non-existing-namespace",
+			}
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: policyServerName}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to fetch CA secret"))
+
+			Eventually(func() ([]metav1.Condition, error) {
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return nil, err
+				}
+				return policyServer.Status.Conditions, nil
+			}, timeout, pollInterval).Should(And(
+				ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal(string(policiesv1.PolicyServerCertSecretReconciled)),
+					"Status":  Equal(metav1.ConditionFalse),
+					"Message": ContainSubstring("failed to fetch CA secret"),
+				})),
+				ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal(string(policiesv1.PolicyServerDeploymentReconciled)),
+					"Status":  Equal(metav1.ConditionFalse),
+					"Message": ContainSubstring("deployment not reconciled"),
+				})),
+				ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal(string(policiesv1.PolicyServerServiceReconciled)),
+					"Status":  Equal(metav1.ConditionFalse),
+					"Message": ContainSubstring("error reconciling service"),
+				})),
+				ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(string(policiesv1.PolicyServerPodDisruptionBudgetReconciled)),
+					"Status": Equal(metav1.ConditionTrue),
+				})),
+			))
+		})
+
+		It("should not fail the reconciliation when the ServiceMonitor CRD is not installed", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			var logLines []string
+			reconciler := &PolicyServerReconciler{
+				Client: k8sClient,
+				Log: funcr.New(func(_, args string) {
+					logLines = append(logLines, args)
+				}, funcr.Options{}),
+				DeploymentsNamespace:  deploymentsNamespace,
+				ClientCAConfigMapName: clientCAConfigMapName,
+				TelemetryConfiguration: TelemetryConfiguration{
+					MetricsEnabled:        true,
+					ServiceMonitorEnabled: true,
+				},
+			}
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: policyServerName}})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(logLines).To(ContainElement(ContainSubstring(`"msg"="ServiceMonitor CRD not found, skipping ServiceMonitor reconciliation"`)))
+			Expect(logLines).To(ContainElement(And(
+				ContainSubstring(`"msg"="PolicyServer reconciliation summary"`),
+				ContainSubstring(`"serviceMonitor"="succeeded"`),
+			)))
+		})
+
+		It("should not fail the reconciliation when the PodMonitor CRD is not installed", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			var logLines []string
+			reconciler := &PolicyServerReconciler{
+				Client: k8sClient,
+				Log: funcr.New(func(_, args string) {
+					logLines = append(logLines, args)
+				}, funcr.Options{}),
+				DeploymentsNamespace:  deploymentsNamespace,
+				ClientCAConfigMapName: clientCAConfigMapName,
+				TelemetryConfiguration: TelemetryConfiguration{
+					MetricsEnabled:    true,
+					PodMonitorEnabled: true,
+				},
+			}
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: policyServerName}})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(logLines).To(ContainElement(ContainSubstring(`"msg"="PodMonitor CRD not found, skipping PodMonitor reconciliation"`)))
+			Expect(logLines).To(ContainElement(And(
+				ContainSubstring(`"msg"="PolicyServer reconciliation summary"`),
+				ContainSubstring(`"serviceMonitor"="skipped"`),
+				ContainSubstring(`"podMonitor"="succeeded"`),
+			)))
+		})
+
+		It("should create the NetworkPolicy when managing the NetworkPolicies and delete it otherwise", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			reconciler := &PolicyServerReconciler{
+				Client:                           k8sClient,
+				DeploymentsNamespace:             deploymentsNamespace,
+				ClientCAConfigMapName:            clientCAConfigMapName,
+				ManageNetworkPolicies:            true,
+				NetworkPolicyAPIServerCIDRs:      []string{"10.0.0.0/24", "10.1.0.1/32"},
+				NetworkPolicyMonitoringNamespace: "monitoring",
+				TelemetryConfiguration: TelemetryConfiguration{
+					MetricsEnabled: true,
+				},
+			}
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: policyServerName}})
+			Expect(err).ToNot(HaveOccurred())
+
+			networkPolicy := &networkingv1.NetworkPolicy{}
+			networkPolicyKey := types.NamespacedName{Name: policyServer.NameWithPrefix(), Namespace: deploymentsNamespace}
+			Expect(k8sClient.Get(ctx, networkPolicyKey, networkPolicy)).To(Succeed())
+			Expect(networkPolicy.OwnerReferences).To(ContainElement(HaveField("Name", policyServerName)))
+			Expect(networkPolicy.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{constants.PolicyServerLabelKey: policyServerName}))
+			Expect(networkPolicy.Spec.PolicyTypes).To(Equal([]networkingv1.PolicyType{networkingv1.PolicyTypeIngress}))
+			Expect(networkPolicy.Spec.Ingress).To(Equal([]networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/24"}},
+						{IPBlock: &networkingv1.IPBlock{CIDR: "10.1.0.1/32"}},
+						{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+							constants.ComponentLabelKey: constants.ComponentAuditScannerLabelValue,
+							constants.PartOfLabelKey:    constants.PartOfLabelValue,
+						}}},
+					},
+					Ports: []networkingv1.NetworkPolicyPort{
+						{Protocol: ptr.To(corev1.ProtocolTCP), Port: ptr.To(intstr.FromInt(constants.PolicyServerListenPort))},
+					},
+				},
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: "monitoring"}}},
+					},
+					Ports: []networkingv1.NetworkPolicyPort{
+						{Protocol: ptr.To(corev1.ProtocolTCP), Port: ptr.To(intstr.FromInt(constants.PolicyServerMetricsPort))},
+					},
+				},
+			}))
+
+			By("disabling the NetworkPolicies management")
+			reconciler.ManageNetworkPolicies = false
+			_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: policyServerName}})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = k8sClient.Get(ctx, networkPolicyKey, networkPolicy)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should not delete a NetworkPolicy not owned by the policy server", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			userNetworkPolicy := &networkingv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      policyServer.NameWithPrefix(),
+					Namespace: deploymentsNamespace,
+				},
+				Spec: networkingv1.NetworkPolicySpec{
+					PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+				},
+			}
+			Expect(k8sClient.Create(ctx, userNetworkPolicy)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, userNetworkPolicy))).To(Succeed())
+			})
+
+			reconciler := &PolicyServerReconciler{
+				Client:                k8sClient,
+				DeploymentsNamespace:  deploymentsNamespace,
+				ClientCAConfigMapName: clientCAConfigMapName,
+			}
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: policyServerName}})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(userNetworkPolicy), &networkingv1.NetworkPolicy{})).To(Succeed())
+		})
+
+		It("should create a dedicated ServiceAccount with access to the context-aware resources of the bound policies", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.DedicatedServiceAccount = true
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			policy := policiesv1.NewClusterAdmissionPolicyFactory().
+				WithName(newName("context-aware-policy")).
+				WithPolicyServer(policyServerName).
+				WithContextAwareResources([]policiesv1.ContextAwareResource{
+					{APIVersion: "v1", Kind: "Namespace"},
+					{APIVersion: "v1", Kind: "ConfigMap"},
+					{APIVersion: "apps/v1", Kind: "Deployment"},
+					{APIVersion: "v1", Kind: "Namespace"},
+				}).
+				Build()
+			Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+
+			serviceAccountName := getPolicyServerNameWithPrefix(policyServerName)
+			Eventually(func() error {
+				serviceAccount := &corev1.ServiceAccount{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: serviceAccountName, Namespace: deploymentsNamespace}, serviceAccount)
+				if err != nil {
+					return err
+				}
+				Expect(serviceAccount.OwnerReferences).To(ContainElement(HaveField("UID", policyServer.GetUID())))
+				return nil
+			}, timeout, pollInterval).Should(Succeed())
+
+			clusterRole := &rbacv1.ClusterRole{}
+			Eventually(func() ([]rbacv1.PolicyRule, error) {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "kubewarden-" + serviceAccountName}, clusterRole)
+				return clusterRole.Rules, err
+			}, timeout, pollInterval).Should(Equal([]rbacv1.PolicyRule{
+				{
+					APIGroups: []string{""},
+					Resources: []string{"configmaps", "namespaces"},
+					Verbs:     []string{"get", "list", "watch"},
+				},
+				{
+					APIGroups: []string{"apps"},
+					Resources: []string{"deployments"},
+					Verbs:     []string{"get", "list", "watch"},
+				},
+			}))
+
+			Expect(clusterRole.OwnerReferences).To(ContainElement(HaveField("UID", policyServer.GetUID())))
+
+			clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "kubewarden-" + serviceAccountName}, clusterRoleBinding)).To(Succeed())
+			Expect(clusterRoleBinding.RoleRef).To(Equal(rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "kubewarden-" + serviceAccountName}))
+			Expect(clusterRoleBinding.Subjects).To(ConsistOf(rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      serviceAccountName,
+				Namespace: deploymentsNamespace,
+			}))
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.ServiceAccountName).To(Equal(serviceAccountName))
+		})
+
+		It("should grant access to the context-aware resources of the members of the bound policy groups", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			policyServer.Spec.DedicatedServiceAccount = true
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			policyGroup := policiesv1.NewClusterAdmissionPolicyGroupFactory().
+				WithName(newName("context-aware-group")).
+				WithPolicyServer(policyServerName).
+				WithExpression("pod_privileged() && user_group_psp()").
+				WithMembers(policiesv1.PolicyGroupMembersWithContext{
+					"pod_privileged": {
+						PolicyGroupMember: policiesv1.PolicyGroupMember{
+							Module: "registry://ghcr.io/kubewarden/tests/pod-privileged:v0.2.5",
+						},
+						ContextAwareResources: []policiesv1.ContextAwareResource{
+							{APIVersion: "v1", Kind: "Namespace"},
+						},
+					},
+					"user_group_psp": {
+						PolicyGroupMember: policiesv1.PolicyGroupMember{
+							Module: "registry://ghcr.io/kubewarden/tests/user-group-psp:v0.4.9",
+						},
+						ContextAwareResources: []policiesv1.ContextAwareResource{
+							{APIVersion: "v1", Kind: "Namespace"},
+							{APIVersion: "v1", Kind: "ConfigMap"},
+						},
+					},
+				}).
+				Build()
+			Expect(k8sClient.Create(ctx, policyGroup)).To(Succeed())
+
+			Eventually(func() ([]rbacv1.PolicyRule, error) {
+				clusterRole := &rbacv1.ClusterRole{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "kubewarden-" + getPolicyServerNameWithPrefix(policyServerName)}, clusterRole)
+				return clusterRole.Rules, err
+			}, timeout, pollInterval).Should(Equal([]rbacv1.PolicyRule{
+				{
+					APIGroups: []string{""},
+					Resources: []string{"configmaps", "namespaces"},
+					Verbs:     []string{"get", "list", "watch"},
+				},
+			}))
+		})
+
+		It("should not create a dedicated ServiceAccount by default", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			serviceAccount := &corev1.ServiceAccount{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: getPolicyServerNameWithPrefix(policyServerName), Namespace: deploymentsNamespace}, serviceAccount)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			clusterRole := &rbacv1.ClusterRole{}
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: "kubewarden-" + getPolicyServerNameWithPrefix(policyServerName)}, clusterRole)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should create the policy server secrets", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			Eventually(func() error {
+				secret, err := getTestPolicyServerSecret(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+
+				By("creating a secret containing the TLS certificate and key")
+				Expect(secret.Data).To(HaveKey(constants.ServerCert))
+				Expect(secret.Data).To(HaveKey(constants.ServerPrivateKey))
+
+				By("setting the secret owner reference")
+				Expect(secret.OwnerReferences).To(ContainElement(
+					MatchFields(IgnoreExtras, Fields{
+						"UID":  Equal(policyServer.GetUID()),
+						"Name": Equal(policyServer.GetName()),
+						// FIXME: for some reason GroupVersionKind is not set
+						// "Kind":       Equal(policyServer.GetObjectKind().GroupVersionKind().Kind),
+						// "APIVersion": Equal(policyServer.GetObjectKind().GroupVersionKind().GroupVersion().String()),
+					}),
+				))
+				return nil
+			}).Should(Succeed())
+		})
+
+		It("should use all the image pull secrets in the policy server deployment", func() {
+			for name, registry := range map[string]string{
+				policyServerName + "-images":   "registry.example.com",
+				policyServerName + "-policies": "ghcr.io",
+			} {
+				Expect(k8sClient.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: deploymentsNamespace,
+					},
+					Type: corev1.SecretTypeDockerConfigJson,
+					Data: map[string][]byte{
+						corev1.DockerConfigJsonKey: []byte(`{"auths":{"` + registry + `":{"auth":"dXNlcjpwYXNzd29yZA=="}}}`),
+					},
+				})).To(Succeed())
+			}
+			policyServer := policiesv1.NewPolicyServerFactory().
+				WithName(policyServerName).
+				WithImagePullSecrets(policyServerName+"-images", policyServerName+"-policies").
+				Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			mergedSecretName := getPolicyServerNameWithPrefix(policyServerName) + "-image-pull-secrets"
+			Eventually(func() error {
+				secret := &corev1.Secret{}
+				if err := k8sClient.Get(ctx, client.ObjectKey{Name: mergedSecretName, Namespace: deploymentsNamespace}, secret); err != nil {
+					return err
+				}
+
+				By("merging the credentials of all the image pull secrets")
+				Expect(secret.Type).To(Equal(corev1.SecretTypeDockerConfigJson))
+				Expect(secret.Data[corev1.DockerConfigJsonKey]).To(MatchJSON(`{"auths":{"ghcr.io":{"auth":"dXNlcjpwYXNzd29yZA=="},"registry.example.com":{"auth":"dXNlcjpwYXNzd29yZA=="}}}`))
+				return nil
+			}, timeout, pollInterval).Should(Succeed())
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("setting the image pull secrets in the pod template")
+			Expect(deployment.Spec.Template.Spec.ImagePullSecrets).To(Equal(policyServer.Spec.ImagePullSecrets))
+
+			By("mounting the merged image pull secret to pull the policies")
+			Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("imagepullsecret"),
+				"VolumeSource": MatchFields(IgnoreExtras, Fields{
+					"Secret": PointTo(MatchFields(IgnoreExtras, Fields{
+						"SecretName": Equal(mergedSecretName),
+					})),
+				}),
+			})))
+
+			By("refreshing the merged image pull secret when a source secret changes")
+			rotatedSecret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: policyServerName + "-policies", Namespace: deploymentsNamespace}, rotatedSecret)).To(Succeed())
+			rotatedSecret.Data[corev1.DockerConfigJsonKey] = []byte(`{"auths":{"ghcr.io":{"auth":"dXNlcjpyb3RhdGVk"}}}`)
+			Expect(k8sClient.Update(ctx, rotatedSecret)).To(Succeed())
+			Eventually(func() ([]byte, error) {
+				secret := &corev1.Secret{}
+				err := k8sClient.Get(ctx, client.ObjectKey{Name: mergedSecretName, Namespace: deploymentsNamespace}, secret)
+				return secret.Data[corev1.DockerConfigJsonKey], err
+			}, timeout, pollInterval).Should(MatchJSON(`{"auths":{"ghcr.io":{"auth":"dXNlcjpyb3RhdGVk"},"registry.example.com":{"auth":"dXNlcjpwYXNzd29yZA=="}}}`))
+		})
+
+		It("should not delete a merged image pull secret not owned by the policy server", func() {
+			userSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      getPolicyServerNameWithPrefix(policyServerName) + "-image-pull-secrets",
+					Namespace: deploymentsNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, userSecret)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, userSecret))).To(Succeed())
+			})
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			Consistently(func() error {
+				return k8sClient.Get(ctx, client.ObjectKeyFromObject(userSecret), &corev1.Secret{})
+			}, consistencyTimeout, pollInterval).Should(Succeed())
+		})
+
+		It("should enable mTLS in the policy server deployment", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			container := deployment.Spec.Template.Spec.Containers[0]
+
+			kubewardenCAPath := filepath.Join(kubewardenCAVolumePath, constants.CARootCert)
+			clientCAPath := filepath.Join(clientCAVolumePath, constants.ClientCACert)
+			By("specifing the client ca certificate")
+			Expect(container.Env).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name":  Equal("KUBEWARDEN_CLIENT_CA_FILE"),
+				"Value": Equal(fmt.Sprintf("%s,%s", kubewardenCAPath, clientCAPath)),
+			})))
+
+			By("mounting the kubewarden CA Secret")
+			Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal(kubewardenCAVolumeName),
+				"VolumeSource": MatchFields(IgnoreExtras, Fields{
+					"Secret": PointTo(MatchFields(IgnoreExtras, Fields{
+						"SecretName": Equal(constants.CARootSecretName),
+						"Items": ConsistOf(MatchFields(IgnoreExtras, Fields{
+							"Key":  Equal(constants.CARootCert),
+							"Path": Equal(constants.CARootCert),
+						})),
+					})),
+				}),
+			})))
+			Expect(container.VolumeMounts).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name":      Equal(kubewardenCAVolumeName),
+				"MountPath": Equal(kubewardenCAVolumePath),
+			})))
+
+			By("mounting the client CA ConfigMap")
+			Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal(clientCAVolumeName),
+				"VolumeSource": MatchFields(IgnoreExtras, Fields{
+					"ConfigMap": PointTo(MatchFields(IgnoreExtras, Fields{
+						"LocalObjectReference": MatchFields(IgnoreExtras, Fields{
+							"Name": Equal(clientCAConfigMapName),
+						}),
+						"Items": ConsistOf(MatchFields(IgnoreExtras, Fields{
+							"Key":  Equal(constants.ClientCACert),
+							"Path": Equal(constants.ClientCACert),
+						})),
+					})),
+				}),
+			})))
+			Expect(container.VolumeMounts).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name":      Equal(clientCAVolumeName),
+				"MountPath": Equal(clientCAVolumePath),
+			})))
+		})
+
+		It("should set the configMap version as a deployment annotation and its checksum as a pod annotation", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			configmap, err := getTestPolicyServerConfigMap(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(func() error {
+				deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				if deployment.GetAnnotations()[constants.PolicyServerDeploymentConfigVersionAnnotation] != configmap.GetResourceVersion() {
+					return errors.New("deployment configmap version did not change")
+				}
+				if deployment.Spec.Template.GetAnnotations()[constants.PolicyServerPodConfigChecksumAnnotation] != configMapChecksum(configmap.Data) {
+					return errors.New("pod configmap checksum did not change")
+				}
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				if policyServer.Status.ConfigChecksum != configMapChecksum(configmap.Data) {
+					return errors.New("policy server configmap checksum did not change")
+				}
+				return nil
+			}, timeout, pollInterval).Should(Succeed())
+		})
+
+		It("should update the configMap version after adding a policy", func() {
+			policyServer := policiesv1.NewPolicyServerFactory().WithName(policyServerName).Build()
+			createPolicyServerAndWaitForItsService(ctx, policyServer)
+
+			initalConfigMap, err := getTestPolicyServerConfigMap(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(func() error {
+				deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				if deployment.GetAnnotations()[constants.PolicyServerDeploymentConfigVersionAnnotation] != initalConfigMap.GetResourceVersion() {
+					return errors.New("deployment configmap version did not change")
+				}
+				if deployment.Spec.Template.GetAnnotations()[constants.PolicyServerPodConfigChecksumAnnotation] != configMapChecksum(initalConfigMap.Data) {
+					return errors.New("pod configmap checksum did not change")
+				}
+				return nil
+			}, timeout, pollInterval).Should(Succeed())
 
 			policyName := newName("validating-policy")
 			policy := policiesv1.NewClusterAdmissionPolicyFactory().
@@ -812,8 +1629,8 @@ var _ = Describe("PolicyServer controller", func() {
 				if deployment.GetAnnotations()[constants.PolicyServerDeploymentConfigVersionAnnotation] != configmap.GetResourceVersion() {
 					return errors.New("deployment configmap version did not change")
 				}
-				if deployment.Spec.Template.GetLabels()[constants.PolicyServerDeploymentConfigVersionAnnotation] != configmap.GetResourceVersion() {
-					return errors.New("pod configmap version did not change")
+				if deployment.Spec.Template.GetAnnotations()[constants.PolicyServerPodConfigChecksumAnnotation] != configMapChecksum(configmap.Data) {
+					return errors.New("pod configmap checksum did not change")
 				}
 				return nil
 			}, timeout, pollInterval).Should(Succeed())
@@ -849,6 +1666,25 @@ var _ = Describe("PolicyServer controller", func() {
 			}, timeout, pollInterval).Should(policyServerPodDisruptionBudgetMatcher(policyServer, nil, &maxUnavailable))
 		})
 
+		It("should recreate the service as headless when policy server headlessService is enabled", func() {
+			Eventually(func() error {
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				policyServer.Spec.HeadlessService = true
+				return k8sClient.Update(ctx, policyServer)
+			}).Should(Succeed())
+
+			Eventually(func() string {
+				service, err := getTestPolicyServerService(ctx, policyServerName)
+				if err != nil {
+					return ""
+				}
+				return service.Spec.ClusterIP
+			}, timeout, pollInterval).Should(Equal(corev1.ClusterIPNone))
+		})
+
 		It("should update deployment when policy server image change", func() {
 			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
 			Expect(err).ToNot(HaveOccurred())
@@ -915,6 +1751,246 @@ var _ = Describe("PolicyServer controller", func() {
 			}).Should(And(Not(Equal(oldServiceAccount)), Equal("new-service-account")))
 		})
 
+		It("should update deployment when policy server runtimeClassName change", func() {
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.RuntimeClassName).To(BeNil())
+			Eventually(func() error {
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				policyServer.Spec.RuntimeClassName = ptr.To("gvisor")
+				return k8sClient.Update(ctx, policyServer)
+			}).Should(Succeed())
+
+			Eventually(func() *string {
+				deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+				if err != nil {
+					return nil
+				}
+				return deployment.Spec.Template.Spec.RuntimeClassName
+			}).Should(PointTo(Equal("gvisor")))
+		})
+
+		It("should update deployment when policy server readinessGates change", func() {
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.ReadinessGates).To(BeEmpty())
+			readinessGates := []corev1.PodReadinessGate{
+				{ConditionType: "mesh.example.com/endpoint-registered"},
+			}
+			Eventually(func() error {
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				policyServer.Spec.ReadinessGates = readinessGates
+				return k8sClient.Update(ctx, policyServer)
+			}).Should(Succeed())
+
+			Eventually(func() []corev1.PodReadinessGate {
+				deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+				if err != nil {
+					return nil
+				}
+				return deployment.Spec.Template.Spec.ReadinessGates
+			}).Should(Equal(readinessGates))
+		})
+
+		It("should set the number of workers of the policy server", func() {
+			getWorkersEnv := func() ([]corev1.EnvVar, error) {
+				deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+				if err != nil {
+					return nil, err
+				}
+				return deployment.Spec.Template.Spec.Containers[0].Env, nil
+			}
+
+			By("defaulting the workers to the CPU limit rounded up")
+			Eventually(func() error {
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				policyServer.Spec.Limits = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m")}
+				return k8sClient.Update(ctx, policyServer)
+			}).Should(Succeed())
+			Eventually(getWorkersEnv).Should(ContainElement(corev1.EnvVar{Name: constants.PolicyServerWorkersEnvVar, Value: "2"}))
+
+			By("using the workers set in the policy server")
+			Eventually(func() error {
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				policyServer.Spec.Workers = ptr.To(int32(4))
+				return k8sClient.Update(ctx, policyServer)
+			}).Should(Succeed())
+			Eventually(getWorkersEnv).Should(ContainElement(corev1.EnvVar{Name: constants.PolicyServerWorkersEnvVar, Value: "4"}))
+		})
+
+		It("should toggle the known policy server features sorted by name", func() {
+			Eventually(func() error {
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				policyServer.Spec.FeatureFlags = map[string]string{
+					"enable-pprof":       "true",
+					"policy-warmup":      "true",
+					"continue-on-errors": "false",
+				}
+				return k8sClient.Update(ctx, policyServer)
+			}).Should(Succeed())
+
+			Eventually(func() ([]corev1.EnvVar, error) {
+				deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+				if err != nil {
+					return nil, err
+				}
+				var featureFlagsEnv []corev1.EnvVar
+				for _, envVar := range deployment.Spec.Template.Spec.Containers[0].Env {
+					if slices.Contains(slices.Collect(maps.Values(policiesv1.PolicyServerFeatureFlagsEnv())), envVar.Name) {
+						featureFlagsEnv = append(featureFlagsEnv, envVar)
+					}
+				}
+				return featureFlagsEnv, nil
+			}).Should(Equal([]corev1.EnvVar{
+				{Name: constants.PolicyServerContinueOnErrorsEnvVar, Value: "false"},
+				{Name: constants.PolicyServerEnablePprofEnvVar, Value: "true"},
+			}))
+		})
+
+		It("should add the policy server init containers sharing the policy server volumes", func() {
+			Eventually(func() error {
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				policyServer.Spec.InitContainers = []corev1.Container{
+					{
+						Name:  "warm-cache",
+						Image: "ghcr.io/kubewarden/kwctl:latest",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "policy-store", MountPath: "/cache"},
+						},
+					},
+				}
+				return k8sClient.Update(ctx, policyServer)
+			}).Should(Succeed())
+
+			Eventually(func() ([]corev1.Container, error) {
+				deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+				if err != nil {
+					return nil, err
+				}
+				return deployment.Spec.Template.Spec.InitContainers, nil
+			}).Should(ContainElement(And(
+				HaveField("Name", "warm-cache"),
+				HaveField("VolumeMounts", ContainElements(
+					corev1.VolumeMount{Name: "policy-store", MountPath: "/cache"},
+					corev1.VolumeMount{Name: "certs", ReadOnly: true, MountPath: "/pki"},
+					corev1.VolumeMount{Name: "policies", ReadOnly: true, MountPath: "/config"},
+				)),
+				HaveField("VolumeMounts", Not(ContainElement(HaveField("MountPath", "/tmp")))),
+			)))
+		})
+
+		It("should mount the projected service account token with the configured audience", func() {
+			Eventually(func() error {
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				policyServer.Spec.ServiceAccountToken = &policiesv1.ServiceAccountTokenProjection{
+					Audience:          "kubewarden-policy-server",
+					ExpirationSeconds: ptr.To[int64](1200),
+				}
+				return k8sClient.Update(ctx, policyServer)
+			}).Should(Succeed())
+
+			Eventually(func() (*appsv1.Deployment, error) {
+				return getTestPolicyServerDeployment(ctx, policyServerName)
+			}).Should(And(
+				HaveField("Spec.Template.Spec.AutomountServiceAccountToken", HaveValue(BeFalse())),
+				HaveField("Spec.Template.Spec.Volumes", ContainElement(And(
+					HaveField("Name", "kube-api-access"),
+					HaveField("Projected.Sources", ContainElement(HaveField("ServiceAccountToken", HaveValue(Equal(corev1.ServiceAccountTokenProjection{
+						Audience:          "kubewarden-policy-server",
+						ExpirationSeconds: ptr.To[int64](1200),
+						Path:              "token",
+					}))))),
+				))),
+				HaveField("Spec.Template.Spec.Containers", ContainElement(HaveField("VolumeMounts", ContainElement(corev1.VolumeMount{
+					Name:      "kube-api-access",
+					ReadOnly:  true,
+					MountPath: "/var/run/secrets/kubernetes.io/serviceaccount",
+				})))),
+			))
+		})
+
+		It("should mount the projected token with the configured audience", func() {
+			Eventually(func() error {
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				policyServer.Spec.ProjectedToken = &policiesv1.ServiceAccountTokenProjection{
+					Audience:          "https://vault.example.com",
+					ExpirationSeconds: ptr.To[int64](1200),
+				}
+				return k8sClient.Update(ctx, policyServer)
+			}).Should(Succeed())
+
+			Eventually(func() (*appsv1.Deployment, error) {
+				return getTestPolicyServerDeployment(ctx, policyServerName)
+			}).Should(And(
+				HaveField("Spec.Template.Spec.AutomountServiceAccountToken", BeNil()),
+				HaveField("Spec.Template.Spec.Volumes", ContainElement(And(
+					HaveField("Name", "projected-token"),
+					HaveField("Projected.Sources", ConsistOf(HaveField("ServiceAccountToken", HaveValue(Equal(corev1.ServiceAccountTokenProjection{
+						Audience:          "https://vault.example.com",
+						ExpirationSeconds: ptr.To[int64](1200),
+						Path:              "token",
+					}))))),
+				))),
+				HaveField("Spec.Template.Spec.Containers", ContainElement(HaveField("VolumeMounts", ContainElement(corev1.VolumeMount{
+					Name:      "projected-token",
+					ReadOnly:  true,
+					MountPath: "/var/run/secrets/tokens",
+				})))),
+			))
+		})
+
+		It("should update deployment when policy server hostAliases change", func() {
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.HostAliases).To(BeEmpty())
+			hostAliases := []corev1.HostAlias{
+				{
+					IP:        "10.0.0.10",
+					Hostnames: []string{"registry.internal"},
+				},
+			}
+			Eventually(func() error {
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				policyServer.Spec.HostAliases = hostAliases
+				return k8sClient.Update(ctx, policyServer)
+			}).Should(Succeed())
+
+			Eventually(func() []corev1.HostAlias {
+				deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+				if err != nil {
+					return nil
+				}
+				return deployment.Spec.Template.Spec.HostAliases
+			}).Should(Equal(hostAliases))
+		})
+
 		It("should update deployment when policy server security context change", func() {
 			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
 			Expect(err).ToNot(HaveOccurred())
@@ -1070,6 +2146,72 @@ var _ = Describe("PolicyServer controller", func() {
 			})), Not(Equal(oldContainers))))
 		})
 
+		It("should update deployment when policy server log level change", func() {
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			oldContainers := deployment.Spec.Template.Spec.Containers
+
+			Eventually(func() error {
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				policyServer.Spec.LogLevel = "debug"
+				return k8sClient.Update(ctx, policyServer)
+			}).Should(Succeed())
+
+			Eventually(func() []corev1.Container {
+				deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+				if err != nil {
+					return nil
+				}
+				return deployment.Spec.Template.Spec.Containers
+			}).Should(And(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Env": ContainElement(Equal(corev1.EnvVar{Name: constants.PolicyServerLogLevelEnvVar, Value: "debug"})),
+			})), Not(Equal(oldContainers))))
+		})
+
+		It("should update deployment when policy server environment variables sources change", func() {
+			deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+			Expect(err).ToNot(HaveOccurred())
+
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      policyServerName + "-tuning",
+					Namespace: deploymentsNamespace,
+				},
+				Data: map[string]string{"KUBEWARDEN_LOG_LEVEL": "debug"},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+
+			oldContainers := deployment.Spec.Template.Spec.Containers
+			newEnvFromSource := corev1.EnvFromSource{
+				ConfigMapRef: &corev1.ConfigMapEnvSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+				},
+			}
+
+			Eventually(func() error {
+				policyServer, err := getTestPolicyServer(ctx, policyServerName)
+				if err != nil {
+					return err
+				}
+				policyServer.Spec.EnvFrom = []corev1.EnvFromSource{newEnvFromSource}
+				return k8sClient.Update(ctx, policyServer)
+			}).Should(Succeed())
+
+			Eventually(func() []corev1.Container {
+				deployment, err := getTestPolicyServerDeployment(ctx, policyServerName)
+				if err != nil {
+					return nil
+				}
+				return deployment.Spec.Template.Spec.Containers
+			}).Should(And(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"EnvFrom": Equal([]corev1.EnvFromSource{newEnvFromSource}),
+			})), Not(Equal(oldContainers))))
+		})
+
 		It("should update the PolicyServer pod with the new requests when the requests are updated", func() {
 			By("updating the PolicyServer requests")
 			updatedRequestsResources := corev1.ResourceList{
@@ -1269,3 +2411,331 @@ var _ = Describe("PolicyServer controller", func() {
 		})
 	})
 })
+
+var _ = Describe("Policy server observed images", func() {
+	podWithContainerStatuses := func(containerStatuses ...corev1.ContainerStatus) corev1.Pod {
+		return corev1.Pod{
+			Status: corev1.PodStatus{
+				ContainerStatuses: containerStatuses,
+			},
+		}
+	}
+	runningContainer := func(name, imageID string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name:    name,
+			ImageID: imageID,
+			State: corev1.ContainerState{
+				Running: &corev1.ContainerStateRunning{},
+			},
+		}
+	}
+
+	It("should list the distinct images of the running policy server containers", func() {
+		pods := []corev1.Pod{
+			podWithContainerStatuses(runningContainer("policy-server-default", "ghcr.io/kubewarden/policy-server@sha256:bbb")),
+			podWithContainerStatuses(runningContainer("policy-server-default", "ghcr.io/kubewarden/policy-server@sha256:aaa")),
+			podWithContainerStatuses(
+				runningContainer("policy-server-default", "ghcr.io/kubewarden/policy-server@sha256:bbb"),
+				runningContainer("otc-container", "ghcr.io/open-telemetry/collector@sha256:ccc"),
+			),
+		}
+
+		Expect(observedImages(pods, "policy-server-default")).To(Equal([]string{
+			"ghcr.io/kubewarden/policy-server@sha256:aaa",
+			"ghcr.io/kubewarden/policy-server@sha256:bbb",
+		}))
+	})
+
+	It("should ignore the containers that are not running", func() {
+		waitingContainer := corev1.ContainerStatus{
+			Name:    "policy-server-default",
+			ImageID: "ghcr.io/kubewarden/policy-server@sha256:aaa",
+			State: corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+			},
+		}
+
+		Expect(observedImages([]corev1.Pod{podWithContainerStatuses(waitingContainer)}, "policy-server-default")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Policy server managed OpenTelemetry environment variables", func() {
+	It("should not manage any variable when the telemetry is disabled", func() {
+		Expect(TelemetryConfiguration{OtelSidecarEnabled: true}.ManagedOtelEnvVars()).To(BeEmpty())
+	})
+
+	It("should manage the exporter endpoint when the sidecar is enabled", func() {
+		telemetryConfiguration := TelemetryConfiguration{MetricsEnabled: true, OtelSidecarEnabled: true}
+		Expect(telemetryConfiguration.ManagedOtelEnvVars()).To(Equal([]string{"OTEL_EXPORTER_OTLP_ENDPOINT"}))
+	})
+
+	It("should manage the variables replicated from the controller when the sidecar is disabled", func() {
+		GinkgoT().Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "https://collector:4317")
+		GinkgoT().Setenv("OTEL_EXPORTER_OTLP_INSECURE", "")
+
+		telemetryConfiguration := TelemetryConfiguration{TracingEnabled: true}
+		Expect(telemetryConfiguration.ManagedOtelEnvVars()).To(Equal([]string{"OTEL_EXPORTER_OTLP_ENDPOINT"}))
+	})
+
+	It("should manage the traces sampler variables when the tracing is enabled", func() {
+		telemetryConfiguration := TelemetryConfiguration{TracingEnabled: true, OtelSidecarEnabled: true, TracingSampler: TracingSamplerRatio, TracingSamplerRatio: 0.1}
+		Expect(telemetryConfiguration.ManagedOtelEnvVars()).To(Equal([]string{"OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_TRACES_SAMPLER", "OTEL_TRACES_SAMPLER_ARG"}))
+	})
+})
+
+var _ = Describe("Policy server traces sampler", func() {
+	DescribeTable("should configure the sampler of the policy server",
+		func(sampler string, ratio float64, expected []corev1.EnvVar) {
+			telemetryConfiguration := TelemetryConfiguration{TracingEnabled: true, TracingSampler: sampler, TracingSamplerRatio: ratio}
+			Expect(telemetryConfiguration.tracesSamplerEnvVars()).To(Equal(expected))
+		},
+		Entry("always", TracingSamplerAlways, 0.0, []corev1.EnvVar{
+			{Name: "OTEL_TRACES_SAMPLER", Value: "parentbased_always_on"},
+		}),
+		Entry("never", TracingSamplerNever, 0.0, []corev1.EnvVar{
+			{Name: "OTEL_TRACES_SAMPLER", Value: "always_off"},
+		}),
+		Entry("ratio", TracingSamplerRatio, 0.05, []corev1.EnvVar{
+			{Name: "OTEL_TRACES_SAMPLER", Value: "parentbased_traceidratio"},
+			{Name: "OTEL_TRACES_SAMPLER_ARG", Value: "0.05"},
+		}),
+		Entry("policy server default", "", 0.0, nil),
+	)
+})
+
+var _ = Describe("Policy server deployment progressing condition", func() {
+	It("should reflect a stalled rollout", func() {
+		deployment := &appsv1.Deployment{
+			Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{
+					{
+						Type:   appsv1.DeploymentAvailable,
+						Status: corev1.ConditionTrue,
+						Reason: "MinimumReplicasAvailable",
+					},
+					{
+						Type:    appsv1.DeploymentProgressing,
+						Status:  corev1.ConditionFalse,
+						Reason:  "ProgressDeadlineExceeded",
+						Message: `ReplicaSet "policy-server-default-5d8f7b" has timed out progressing.`,
+					},
+				},
+			},
+		}
+
+		Expect(deploymentProgressingCondition(deployment)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Type":    Equal(string(policiesv1.PolicyServerDeploymentProgressing)),
+			"Status":  Equal(metav1.ConditionFalse),
+			"Reason":  Equal("ProgressDeadlineExceeded"),
+			"Message": Equal(`ReplicaSet "policy-server-default-5d8f7b" has timed out progressing.`),
+		})))
+	})
+
+	It("should not report the condition before the deployment does", func() {
+		Expect(deploymentProgressingCondition(&appsv1.Deployment{})).To(BeNil())
+	})
+})
+
+var _ = Describe("Policy server verification config volume", func() {
+	It("should not mount any volume when no verification config is set", func() {
+		Expect(verificationConfigVolumeSource(policiesv1.NewPolicyServerFactory().Build())).To(BeNil())
+	})
+
+	It("should mount the verification config ConfigMap", func() {
+		policyServer := policiesv1.NewPolicyServerFactory().Build()
+		policyServer.Spec.VerificationConfig = "verification-config"
+
+		volumeSource := verificationConfigVolumeSource(policyServer)
+		Expect(volumeSource).ToNot(BeNil())
+		Expect(volumeSource.Secret).To(BeNil())
+		Expect(volumeSource.ConfigMap).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"LocalObjectReference": Equal(corev1.LocalObjectReference{Name: "verification-config"}),
+			"Items":                Equal([]corev1.KeyToPath{{Key: constants.PolicyServerVerificationConfigEntry, Path: verificationFilename}}),
+		})))
+	})
+
+	It("should mount the verification config Secret", func() {
+		policyServer := policiesv1.NewPolicyServerFactory().Build()
+		policyServer.Spec.VerificationConfigSecret = "verification-config"
+
+		volumeSource := verificationConfigVolumeSource(policyServer)
+		Expect(volumeSource).ToNot(BeNil())
+		Expect(volumeSource.ConfigMap).To(BeNil())
+		Expect(volumeSource.Secret).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"SecretName": Equal("verification-config"),
+			"Items":      Equal([]corev1.KeyToPath{{Key: constants.PolicyServerVerificationConfigEntry, Path: verificationFilename}}),
+		})))
+	})
+})
+
+var _ = Describe("Policy server ConfigMap checksum", func() {
+	data := map[string]string{
+		constants.PolicyServerConfigPoliciesEntry: "{}",
+		constants.PolicyServerConfigSourcesEntry:  "{}",
+	}
+
+	It("should not change when the ConfigMap is rewritten with the same content", func() {
+		Expect(configMapChecksum(data)).To(Equal(configMapChecksum(maps.Clone(data))))
+	})
+
+	It("should change when the content of the ConfigMap changes", func() {
+		changedData := maps.Clone(data)
+		changedData[constants.PolicyServerConfigPoliciesEntry] = `{"privileged-pods":{}}`
+
+		Expect(configMapChecksum(changedData)).ToNot(Equal(configMapChecksum(data)))
+	})
+
+	It("should tell apart entries with the same concatenated content", func() {
+		Expect(configMapChecksum(map[string]string{"a": "bc"})).ToNot(Equal(configMapChecksum(map[string]string{"ab": "c"})))
+	})
+})
+
+var _ = Describe("Policy server policies summary", func() {
+	It("should count the bound policies in each status", func() {
+		policyWithStatus := func(policy policiesv1.Policy, status policiesv1.PolicyStatusEnum) policiesv1.Policy {
+			policy.GetStatus().PolicyStatus = status
+			return policy
+		}
+		policies := []policiesv1.Policy{
+			policyWithStatus(policiesv1.NewClusterAdmissionPolicyFactory().Build(), policiesv1.PolicyStatusActive),
+			policyWithStatus(policiesv1.NewAdmissionPolicyFactory().Build(), policiesv1.PolicyStatusActive),
+			policyWithStatus(policiesv1.NewClusterAdmissionPolicyGroupFactory().Build(), policiesv1.PolicyStatusPending),
+			policyWithStatus(policiesv1.NewAdmissionPolicyGroupFactory().Build(), policiesv1.PolicyStatusScheduled),
+			policiesv1.NewClusterAdmissionPolicyFactory().Build(),
+		}
+
+		Expect(summarizePoliciesStatus(policies)).To(Equal(&policiesv1.PolicyServerPoliciesSummary{
+			Total:     5,
+			Active:    2,
+			Pending:   1,
+			Scheduled: 1,
+		}))
+	})
+
+	It("should report a policy server without policies", func() {
+		Expect(summarizePoliciesStatus(nil)).To(Equal(&policiesv1.PolicyServerPoliciesSummary{}))
+	})
+})
+
+var _ = Describe("Policy server pods schedulable condition", func() {
+	pendingPod := func(name, reason, message string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{
+					{
+						Type:    corev1.PodScheduled,
+						Status:  corev1.ConditionFalse,
+						Reason:  reason,
+						Message: message,
+					},
+				},
+			},
+		}
+	}
+
+	It("should report the pods rejected by a ResourceQuota", func() {
+		deployment := &appsv1.Deployment{
+			Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{
+					{
+						Type:    appsv1.DeploymentReplicaFailure,
+						Status:  corev1.ConditionTrue,
+						Reason:  "FailedCreate",
+						Message: `pods "policy-server-default-5d8f7b-x2x4n" is forbidden: exceeded quota: compute, requested: requests.cpu=1, used: requests.cpu=4, limited: requests.cpu=4`,
+					},
+				},
+			},
+		}
+
+		Expect(podsSchedulableCondition(deployment, nil)).To(MatchFields(IgnoreExtras, Fields{
+			"Type":    Equal(string(policiesv1.PolicyServerPodsSchedulable)),
+			"Status":  Equal(metav1.ConditionFalse),
+			"Reason":  Equal(string(policiesv1.PodsCreationFailed)),
+			"Message": ContainSubstring("exceeded quota: compute"),
+		}))
+	})
+
+	It("should report the first unschedulable pod with the scheduler message", func() {
+		pods := []corev1.Pod{
+			pendingPod("policy-server-default-b", corev1.PodReasonUnschedulable, "0/3 nodes are available: 3 Insufficient memory."),
+			pendingPod("policy-server-default-a", corev1.PodReasonUnschedulable, "0/3 nodes are available: 3 Insufficient cpu."),
+			pendingPod("policy-server-default-0", corev1.PodReasonSchedulingGated, "Scheduling is blocked due to non-empty scheduling gates"),
+		}
+
+		Expect(podsSchedulableCondition(&appsv1.Deployment{}, pods)).To(MatchFields(IgnoreExtras, Fields{
+			"Type":    Equal(string(policiesv1.PolicyServerPodsSchedulable)),
+			"Status":  Equal(metav1.ConditionFalse),
+			"Reason":  Equal(string(policiesv1.PodsUnschedulable)),
+			"Message": Equal("pod policy-server-default-a: 0/3 nodes are available: 3 Insufficient cpu."),
+		}))
+	})
+
+	It("should report the pods as schedulable", func() {
+		pods := []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "policy-server-default-a"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+		}
+
+		Expect(podsSchedulableCondition(&appsv1.Deployment{}, pods)).To(MatchFields(IgnoreExtras, Fields{
+			"Type":   Equal(string(policiesv1.PolicyServerPodsSchedulable)),
+			"Status": Equal(metav1.ConditionTrue),
+			"Reason": Equal(string(policiesv1.PodsScheduled)),
+		}))
+	})
+
+	It("should enqueue the policy server of its pods", func() {
+		reconciler := PolicyServerReconciler{DeploymentsNamespace: deploymentsNamespace}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "policy-server-default-a",
+				Namespace: deploymentsNamespace,
+				Labels:    map[string]string{constants.PolicyServerLabelKey: "default"},
+			},
+		}
+
+		Expect(reconciler.enqueuePolicyServerPod(context.Background(), pod)).To(Equal([]ctrl.Request{
+			{NamespacedName: client.ObjectKey{Name: "default"}},
+		}))
+
+		pod.Labels = nil
+		Expect(reconciler.enqueuePolicyServerPod(context.Background(), pod)).To(BeEmpty())
+	})
+
+	It("should only pass the pod phase and readiness transitions", func() {
+		podPredicate := policyServerPodStatusChangedPredicate()
+		oldPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy-server-default-a", ResourceVersion: "1"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+					{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+				},
+			},
+		}
+
+		newPod := oldPod.DeepCopy()
+		newPod.ResourceVersion = "2"
+		newPod.Labels = map[string]string{"foo": "bar"}
+		Expect(podPredicate.Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod})).To(BeFalse())
+
+		newPod = oldPod.DeepCopy()
+		newPod.Status.Conditions[1].Status = corev1.ConditionTrue
+		Expect(podPredicate.Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod})).To(BeTrue())
+
+		newPod = oldPod.DeepCopy()
+		newPod.Status.Phase = corev1.PodFailed
+		Expect(podPredicate.Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod})).To(BeTrue())
+
+		newPod = oldPod.DeepCopy()
+		newPod.Status.Phase = corev1.PodPending
+		newPod.Status.Conditions = []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable, Message: "0/3 nodes are available"},
+		}
+		Expect(podPredicate.Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod})).To(BeTrue())
+
+		Expect(podPredicate.Create(event.CreateEvent{Object: oldPod})).To(BeTrue())
+		Expect(podPredicate.Delete(event.DeleteEvent{Object: oldPod})).To(BeTrue())
+	})
+})
@@ -0,0 +1,28 @@
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+const (
+	reconciliationPausedReason  = "PausedByAnnotation"
+	reconciliationPausedMessage = "The reconciliation is paused by the " + constants.PausedAnnotation + " annotation"
+)
+
+// isReconciliationPaused returns true when the object has the paused
+// annotation set to "true". The controllers do not touch the resources of a
+// paused object, allowing them to be edited by hand.
+func isReconciliationPaused(obj metav1.Object) bool {
+	return obj.GetAnnotations()[constants.PausedAnnotation] == "true"
+}
+
+func reconciliationPausedCondition(conditionType string) metav1.Condition {
+	return metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  reconciliationPausedReason,
+		Message: reconciliationPausedMessage,
+	}
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+	"github.com/kubewarden/kubewarden-controller/internal/constants"
+)
+
+func newFakeClientTestScheme() *runtime.Scheme {
+	testScheme := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(testScheme)).To(Succeed())
+	Expect(policiesv1.AddToScheme(testScheme)).To(Succeed())
+	return testScheme
+}
+
+var _ = Describe("Paused policy server reconciliation", func() {
+	ctx := context.Background()
+
+	var (
+		reconciler   *PolicyServerReconciler
+		policyServer *policiesv1.PolicyServer
+	)
+
+	BeforeEach(func() {
+		policyServer = policiesv1.NewPolicyServerFactory().WithName("paused").Build()
+		policyServer.SetAnnotations(map[string]string{constants.PausedAnnotation: "true"})
+
+		policyServerIndex := func(obj client.Object) []string {
+			return []string{obj.(policiesv1.Policy).GetPolicyServer()}
+		}
+		reconciler = &PolicyServerReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(newFakeClientTestScheme()).
+				WithObjects(policyServer).
+				WithStatusSubresource(policyServer).
+				WithIndex(&policiesv1.ClusterAdmissionPolicy{}, constants.PolicyServerIndexKey, policyServerIndex).
+				WithIndex(&policiesv1.AdmissionPolicy{}, constants.PolicyServerIndexKey, policyServerIndex).
+				WithIndex(&policiesv1.ClusterAdmissionPolicyGroup{}, constants.PolicyServerIndexKey, policyServerIndex).
+				WithIndex(&policiesv1.AdmissionPolicyGroup{}, constants.PolicyServerIndexKey, policyServerIndex).
+				Build(),
+			DeploymentsNamespace: deploymentsNamespace,
+		}
+	})
+
+	reconcilePolicyServer := func() (*policiesv1.PolicyServer, error) {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: policyServer.Name}})
+		storedPolicyServer := &policiesv1.PolicyServer{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(policyServer), storedPolicyServer)).To(Succeed())
+		return storedPolicyServer, err
+	}
+
+	It("should not reconcile the resources of a paused policy server", func() {
+		storedPolicyServer, err := reconcilePolicyServer()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(apimeta.FindStatusCondition(storedPolicyServer.Status.Conditions, string(policiesv1.PolicyServerReconciliationPaused))).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionTrue),
+			"Reason": Equal("PausedByAnnotation"),
+		})))
+		err = reconciler.Get(ctx, types.NamespacedName{Namespace: deploymentsNamespace, Name: policyServer.NameWithPrefix()}, &appsv1.Deployment{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should resume the reconciliation when the annotation is removed", func() {
+		_, err := reconcilePolicyServer()
+		Expect(err).ToNot(HaveOccurred())
+
+		storedPolicyServer := &policiesv1.PolicyServer{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(policyServer), storedPolicyServer)).To(Succeed())
+		storedPolicyServer.SetAnnotations(nil)
+		Expect(reconciler.Update(ctx, storedPolicyServer)).To(Succeed())
+
+		// The CA root secret does not exist, so the certificate secret
+		// reconciliation fails. The status is updated anyway.
+		storedPolicyServer, err = reconcilePolicyServer()
+		Expect(err).To(HaveOccurred())
+		Expect(apimeta.FindStatusCondition(storedPolicyServer.Status.Conditions, string(policiesv1.PolicyServerReconciliationPaused))).To(BeNil())
+		Expect(apimeta.FindStatusCondition(storedPolicyServer.Status.Conditions, string(policiesv1.PolicyServerCertSecretReconciled))).ToNot(BeNil())
+	})
+})
+
+var _ = Describe("Paused policy reconciliation", func() {
+	ctx := context.Background()
+
+	var (
+		subReconciler *policySubReconciler
+		policy        *policiesv1.ClusterAdmissionPolicy
+	)
+
+	BeforeEach(func() {
+		policy = policiesv1.NewClusterAdmissionPolicyFactory().WithName("paused").WithPolicyServer("").Build()
+		policy.SetAnnotations(map[string]string{constants.PausedAnnotation: "true"})
+		subReconciler = &policySubReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(newFakeClientTestScheme()).
+				WithObjects(policy).
+				WithStatusSubresource(policy).
+				Build(),
+			deploymentsNamespace: deploymentsNamespace,
+		}
+	})
+
+	reconcilePolicy := func() *policiesv1.ClusterAdmissionPolicy {
+		storedPolicy := &policiesv1.ClusterAdmissionPolicy{}
+		Expect(subReconciler.Get(ctx, client.ObjectKeyFromObject(policy), storedPolicy)).To(Succeed())
+		_, err := subReconciler.reconcile(ctx, storedPolicy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(subReconciler.Get(ctx, client.ObjectKeyFromObject(policy), storedPolicy)).To(Succeed())
+		return storedPolicy
+	}
+
+	It("should not reconcile a paused policy", func() {
+		storedPolicy := reconcilePolicy()
+
+		Expect(apimeta.FindStatusCondition(storedPolicy.Status.Conditions, string(policiesv1.PolicyReconciliationPaused))).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionTrue),
+			"Reason": Equal("PausedByAnnotation"),
+		})))
+		Expect(storedPolicy.Status.PolicyStatus).To(BeEmpty())
+		err := subReconciler.Get(ctx, types.NamespacedName{Name: policy.GetUniqueName()}, &admissionregistrationv1.ValidatingWebhookConfiguration{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should resume the reconciliation when the annotation is removed", func() {
+		storedPolicy := reconcilePolicy()
+		storedPolicy.SetAnnotations(nil)
+		Expect(subReconciler.Update(ctx, storedPolicy)).To(Succeed())
+
+		storedPolicy = reconcilePolicy()
+
+		Expect(apimeta.FindStatusCondition(storedPolicy.Status.Conditions, string(policiesv1.PolicyReconciliationPaused))).To(BeNil())
+		Expect(storedPolicy.Status.PolicyStatus).To(Equal(policiesv1.PolicyStatusUnscheduled))
+	})
+})
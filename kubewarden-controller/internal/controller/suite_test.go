@@ -115,6 +115,9 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	})
 	Expect(err).ToNot(HaveOccurred())
 
+	err = SetupPolicyServerIndexes(ctx, k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	err = (&AdmissionPolicyReconciler{
 		Client:               k8sManager.GetClient(),
 		Scheme:               k8sManager.GetScheme(),
@@ -3,11 +3,16 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/metric"
 	metricSDK "go.opentelemetry.io/otel/sdk/metric"
 
@@ -15,53 +20,252 @@ import (
 )
 
 const (
-	meterName                      = "kubewarden"
-	policyCounterMetricName        = "kubewarden_policy_total"
-	policyCounterMetricDescription = "How many policies are installed in the cluster"
-	timeBetweenExports             = 2 * time.Second
+	meterName                    = "kubewarden"
+	policyCountMetricName        = "kubewarden_policies"
+	policyCountMetricDescription = "How many policies are currently installed in the cluster"
+	// DefaultExportInterval is the default interval between two exports of
+	// the metrics.
+	DefaultExportInterval = 2 * time.Second
+
+	otlpProtocolEnvVar        = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	otlpMetricsProtocolEnvVar = "OTEL_EXPORTER_OTLP_METRICS_PROTOCOL"
+	otlpProtocolGRPC          = "grpc"
+	otlpProtocolHTTPProtobuf  = "http/protobuf"
+
+	policyServerReplicasReadyMetricName          = "kubewarden_policy_server_replicas_ready"
+	policyServerReplicasReadyMetricDescription   = "How many replicas of the policy server are ready"
+	policyServerReplicasDesiredMetricName        = "kubewarden_policy_server_replicas_desired"
+	policyServerReplicasDesiredMetricDescription = "How many replicas of the policy server are desired"
+
+	policyServerRestartsMetricName        = "kubewarden_policy_server_restarts_total"
+	policyServerRestartsMetricDescription = "How many times the policy server containers restarted"
+
+	webhookConfigReconcileErrorsMetricName        = "kubewarden_webhook_config_reconcile_errors_total"
+	webhookConfigReconcileErrorsMetricDescription = "How many times the reconciliation of the webhook configuration of a policy failed"
+
+	// unknownRegistry is the registry reported for the modules whose origin
+	// cannot be determined, like local files.
+	unknownRegistry = "unknown"
 )
 
-func New() (func(context.Context) error, error) {
+// New initializes the global meter provider exporting the metrics with the
+// OTLP exporter every exportInterval. When exportInterval is zero, the
+// DefaultExportInterval is used.
+func New(exportInterval time.Duration) (func(context.Context) error, error) {
 	ctx := context.Background()
 
+	if exportInterval < 0 {
+		return nil, fmt.Errorf("invalid metrics export interval %s: must be positive", exportInterval)
+	}
+	if exportInterval == 0 {
+		exportInterval = DefaultExportInterval
+	}
+
 	// Create the OTLP exporter to export metrics to the specified endpoint.
 	// All the Otel exporter configuration is set by environment variables.
-	exporter, err := otlpmetricgrpc.New(
-		ctx,
-	)
+	exporter, err := newExporter(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("cannot start metric exporter: %w", err)
 	}
 	meterProvider := metricSDK.NewMeterProvider(metricSDK.WithReader(
-		metricSDK.NewPeriodicReader(exporter, metricSDK.WithInterval(timeBetweenExports))))
+		metricSDK.NewPeriodicReader(exporter, metricSDK.WithInterval(exportInterval))))
 
 	otel.SetMeterProvider(meterProvider)
 
 	return meterProvider.Shutdown, nil
 }
 
-func RecordPolicyCount(ctx context.Context, policy policiesv1.Policy) error {
-	failurePolicy := ""
-	if policy.GetFailurePolicy() != nil {
-		failurePolicy = string(*policy.GetFailurePolicy())
+// exporterProtocol returns the OTLP protocol used to export the metrics. Like
+// the OpenTelemetry SDKs, the OTEL_EXPORTER_OTLP_METRICS_PROTOCOL environment
+// variable takes precedence over OTEL_EXPORTER_OTLP_PROTOCOL. The gRPC protocol
+// is used when none of them is set.
+func exporterProtocol() (string, error) {
+	protocol := os.Getenv(otlpMetricsProtocolEnvVar)
+	if protocol == "" {
+		protocol = os.Getenv(otlpProtocolEnvVar)
+	}
+	if protocol == "" {
+		return otlpProtocolGRPC, nil
+	}
+
+	switch protocol {
+	case otlpProtocolGRPC, otlpProtocolHTTPProtobuf:
+		return protocol, nil
+	default:
+		return "", fmt.Errorf("unknown OTLP protocol %q, supported values are %q and %q", protocol, otlpProtocolGRPC, otlpProtocolHTTPProtobuf)
+	}
+}
+
+func newExporter(ctx context.Context) (metricSDK.Exporter, error) {
+	protocol, err := exporterProtocol()
+	if err != nil {
+		return nil, err
+	}
+
+	if protocol == otlpProtocolHTTPProtobuf {
+		exporter, err := otlpmetrichttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create the OTLP HTTP exporter: %w", err)
+		}
+		return exporter, nil
+	}
+
+	exporter, err := otlpmetricgrpc.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the OTLP gRPC exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+// PolicyLister returns all the policies installed in the cluster.
+type PolicyLister func(ctx context.Context) ([]policiesv1.Policy, error)
+
+type policyCountKey struct {
+	policyServer string
+	status       string
+	mode         string
+	module       string
+	registry     string
+}
+
+// RegisterPolicyCount registers an observable gauge reporting, on each
+// collection cycle, the current number of policies grouped by policy server, status,
+// mode and module. The module of a policy group is made of the modules of its
+// members. Each data point also reports the registry hosting the module. The
+// policies are enumerated with the given lister.
+func RegisterPolicyCount(lister PolicyLister) error {
+	meter := otel.Meter(meterName)
+	gauge, err := meter.Int64ObservableGauge(policyCountMetricName, metric.WithDescription(policyCountMetricDescription))
+	if err != nil {
+		return fmt.Errorf("cannot create the instrument: %w", err)
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, observer metric.Observer) error {
+		policies, err := lister(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot list policies: %w", err)
+		}
+
+		counts := make(map[policyCountKey]int64)
+		for _, policy := range policies {
+			modules := policy.GetModules()
+			counts[policyCountKey{
+				policyServer: policy.GetPolicyServer(),
+				status:       string(policy.GetStatus().PolicyStatus),
+				mode:         string(policy.GetPolicyMode()),
+				module:       strings.Join(modules, ","),
+				registry:     modulesRegistry(modules),
+			}]++
+		}
+
+		for key, count := range counts {
+			observer.ObserveInt64(gauge, count, metric.WithAttributes(
+				attribute.String("policy_server", key.policyServer),
+				attribute.String("policy_status", key.status),
+				attribute.String("mode", key.mode),
+				attribute.String("module", key.module),
+				attribute.String("registry", key.registry),
+			))
+		}
+		return nil
+	}, gauge)
+	if err != nil {
+		return fmt.Errorf("cannot register the policy count callback: %w", err)
+	}
+
+	return nil
+}
+
+// modulesRegistry returns the registries hosting the given modules, sorted
+// and comma-separated.
+func modulesRegistry(modules []string) string {
+	registries := []string{}
+	for _, module := range modules {
+		registries = append(registries, moduleRegistry(module))
+	}
+	slices.Sort(registries)
+
+	return strings.Join(slices.Compact(registries), ",")
+}
+
+// moduleRegistry returns the host of the OCI registry or of the HTTP server
+// the given module is downloaded from. Like the policy server, a module
+// reference without scheme is considered an OCI reference: its registry is the
+// first path component when it looks like a host name. unknownRegistry is
+// returned when the registry cannot be determined.
+func moduleRegistry(module string) string {
+	if !strings.Contains(module, "://") {
+		host, _, found := strings.Cut(module, "/")
+		if !found || !(strings.ContainsAny(host, ".:") || host == "localhost") {
+			return unknownRegistry
+		}
+		return host
+	}
+
+	moduleURL, err := url.Parse(module)
+	if err != nil {
+		return unknownRegistry
+	}
+	switch moduleURL.Scheme {
+	case "registry", "http", "https":
+		if moduleURL.Host == "" {
+			return unknownRegistry
+		}
+		return moduleURL.Host
+	default:
+		return unknownRegistry
+	}
+}
+
+// RecordPolicyServerReplicas records the number of ready and desired replicas
+// of the given policy server.
+func RecordPolicyServerReplicas(ctx context.Context, policyServer *policiesv1.PolicyServer, readyReplicas, desiredReplicas int32) error {
+	meter := otel.Meter(meterName)
+	readyGauge, err := meter.Int64Gauge(policyServerReplicasReadyMetricName, metric.WithDescription(policyServerReplicasReadyMetricDescription))
+	if err != nil {
+		return fmt.Errorf("cannot create the instrument: %w", err)
+	}
+	desiredGauge, err := meter.Int64Gauge(policyServerReplicasDesiredMetricName, metric.WithDescription(policyServerReplicasDesiredMetricDescription))
+	if err != nil {
+		return fmt.Errorf("cannot create the instrument: %w", err)
 	}
 
+	labels := metric.WithAttributes(attribute.String("policy_server", policyServer.GetName()))
+	readyGauge.Record(ctx, int64(readyReplicas), labels)
+	desiredGauge.Record(ctx, int64(desiredReplicas), labels)
+
+	return nil
+}
+
+// RecordPolicyServerRestarts increments the restarts counter of the given
+// policy server.
+func RecordPolicyServerRestarts(ctx context.Context, policyServer *policiesv1.PolicyServer, restarts int64) error {
 	meter := otel.Meter(meterName)
-	counter, err := meter.Int64Counter(policyCounterMetricName, metric.WithDescription(policyCounterMetricDescription))
+	counter, err := meter.Int64Counter(policyServerRestartsMetricName, metric.WithDescription(policyServerRestartsMetricDescription))
 	if err != nil {
 		return fmt.Errorf("cannot create the instrument: %w", err)
 	}
 
-	commonLabels := []attribute.KeyValue{
-		attribute.String("name", policy.GetUniqueName()),
-		attribute.String("policy_server", policy.GetPolicyServer()),
-		attribute.String("module", policy.GetModule()),
-		attribute.Bool("mutating", policy.IsMutating()),
-		attribute.String("namespace", policy.GetNamespace()),
-		attribute.String("failure_policy", failurePolicy),
-		attribute.String("policy_status", string(policy.GetStatus().PolicyStatus)),
+	counter.Add(ctx, restarts, metric.WithAttributes(attribute.String("policy_server", policyServer.GetName())))
+
+	return nil
+}
+
+// RecordWebhookConfigError increments the counter of the failed
+// reconciliations of the webhook configurations of the policies of the given
+// kind. The reason tells why the reconciliation failed, like the reason of the
+// Kubernetes API error.
+func RecordWebhookConfigError(ctx context.Context, policyKind, reason string) error {
+	meter := otel.Meter(meterName)
+	counter, err := meter.Int64Counter(webhookConfigReconcileErrorsMetricName, metric.WithDescription(webhookConfigReconcileErrorsMetricDescription))
+	if err != nil {
+		return fmt.Errorf("cannot create the instrument: %w", err)
 	}
-	counter.Add(ctx, 1, metric.WithAttributes(commonLabels...))
+
+	counter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("policy_kind", policyKind),
+		attribute.String("reason", reason),
+	))
 
 	return nil
 }
@@ -0,0 +1,275 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	metricSDK "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	policiesv1 "github.com/kubewarden/kubewarden-controller/api/policies/v1"
+)
+
+func TestRecordPolicyServerReplicas(t *testing.T) {
+	reader := metricSDK.NewManualReader()
+	meterProvider := metricSDK.NewMeterProvider(metricSDK.WithReader(reader))
+	otel.SetMeterProvider(meterProvider)
+	t.Cleanup(func() {
+		_ = meterProvider.Shutdown(t.Context())
+	})
+
+	policyServer := policiesv1.NewPolicyServerFactory().WithName("default").Build()
+
+	require.NoError(t, RecordPolicyServerReplicas(t.Context(), policyServer, 1, 3))
+	require.NoError(t, RecordPolicyServerReplicas(t.Context(), policyServer, 2, 3))
+
+	var resourceMetrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &resourceMetrics))
+	require.Len(t, resourceMetrics.ScopeMetrics, 1)
+
+	recordedValues := map[string]int64{}
+	for _, recordedMetric := range resourceMetrics.ScopeMetrics[0].Metrics {
+		gauge, ok := recordedMetric.Data.(metricdata.Gauge[int64])
+		require.True(t, ok)
+		require.Len(t, gauge.DataPoints, 1)
+
+		policyServerName, found := gauge.DataPoints[0].Attributes.Value(attribute.Key("policy_server"))
+		require.True(t, found)
+		assert.Equal(t, "default", policyServerName.AsString())
+
+		recordedValues[recordedMetric.Name] = gauge.DataPoints[0].Value
+	}
+
+	assert.Equal(t, map[string]int64{
+		policyServerReplicasReadyMetricName:   2,
+		policyServerReplicasDesiredMetricName: 3,
+	}, recordedValues)
+}
+
+func TestExporterProtocol(t *testing.T) {
+	tests := []struct {
+		name             string
+		protocol         string
+		metricsProtocol  string
+		expectedProtocol string
+		error            string
+	}{
+		{
+			name:             "default protocol",
+			protocol:         "",
+			metricsProtocol:  "",
+			expectedProtocol: "grpc",
+			error:            "",
+		},
+		{
+			name:             "HTTP protocol",
+			protocol:         "http/protobuf",
+			metricsProtocol:  "",
+			expectedProtocol: "http/protobuf",
+			error:            "",
+		},
+		{
+			name:             "metrics protocol takes precedence",
+			protocol:         "http/protobuf",
+			metricsProtocol:  "grpc",
+			expectedProtocol: "grpc",
+			error:            "",
+		},
+		{
+			name:             "unknown protocol",
+			protocol:         "",
+			metricsProtocol:  "http/json",
+			expectedProtocol: "",
+			error:            `unknown OTLP protocol "http/json", supported values are "grpc" and "http/protobuf"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(otlpProtocolEnvVar, test.protocol)
+			t.Setenv(otlpMetricsProtocolEnvVar, test.metricsProtocol)
+
+			protocol, err := exporterProtocol()
+			if test.error != "" {
+				require.EqualError(t, err, test.error)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedProtocol, protocol)
+		})
+	}
+}
+
+func TestNewWithUnknownProtocol(t *testing.T) {
+	t.Setenv(otlpMetricsProtocolEnvVar, "unknown")
+
+	_, err := New(DefaultExportInterval)
+	require.ErrorContains(t, err, `unknown OTLP protocol "unknown"`)
+}
+
+func TestNewWithNegativeExportInterval(t *testing.T) {
+	_, err := New(-time.Second)
+	require.EqualError(t, err, "invalid metrics export interval -1s: must be positive")
+}
+
+func TestRecordPolicyServerRestarts(t *testing.T) {
+	reader := metricSDK.NewManualReader()
+	meterProvider := metricSDK.NewMeterProvider(metricSDK.WithReader(reader))
+	otel.SetMeterProvider(meterProvider)
+	t.Cleanup(func() {
+		_ = meterProvider.Shutdown(t.Context())
+	})
+
+	policyServer := policiesv1.NewPolicyServerFactory().WithName("default").Build()
+
+	require.NoError(t, RecordPolicyServerRestarts(t.Context(), policyServer, 2))
+	require.NoError(t, RecordPolicyServerRestarts(t.Context(), policyServer, 1))
+
+	var resourceMetrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &resourceMetrics))
+	require.Len(t, resourceMetrics.ScopeMetrics, 1)
+	require.Len(t, resourceMetrics.ScopeMetrics[0].Metrics, 1)
+
+	recordedMetric := resourceMetrics.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, policyServerRestartsMetricName, recordedMetric.Name)
+
+	sum, ok := recordedMetric.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(3), sum.DataPoints[0].Value)
+}
+
+func TestRegisterPolicyCount(t *testing.T) {
+	reader := metricSDK.NewManualReader()
+	meterProvider := metricSDK.NewMeterProvider(metricSDK.WithReader(reader))
+	otel.SetMeterProvider(meterProvider)
+	t.Cleanup(func() {
+		_ = meterProvider.Shutdown(t.Context())
+	})
+
+	activePolicy := policiesv1.NewClusterAdmissionPolicyFactory().WithPolicyServer("default").WithMode(policiesv1.PolicyMode("protect")).Build()
+	activePolicy.Status.PolicyStatus = policiesv1.PolicyStatusActive
+	otherActivePolicy := policiesv1.NewAdmissionPolicyFactory().WithPolicyServer("default").WithMode(policiesv1.PolicyMode("protect")).Build()
+	otherActivePolicy.Status.PolicyStatus = policiesv1.PolicyStatusActive
+	pendingPolicy := policiesv1.NewAdmissionPolicyFactory().WithPolicyServer("default").WithMode(policiesv1.PolicyMode("monitor")).Build()
+	pendingPolicy.Status.PolicyStatus = policiesv1.PolicyStatusPending
+
+	activePolicyGroup := policiesv1.NewClusterAdmissionPolicyGroupFactory().WithPolicyServer("default").Build()
+	activePolicyGroup.Status.PolicyStatus = policiesv1.PolicyStatusActive
+
+	policies := []policiesv1.Policy{activePolicy, otherActivePolicy, pendingPolicy, activePolicyGroup}
+	require.NoError(t, RegisterPolicyCount(func(_ context.Context) ([]policiesv1.Policy, error) {
+		return policies, nil
+	}))
+
+	collectCounts := func() map[string]int64 {
+		var resourceMetrics metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(t.Context(), &resourceMetrics))
+		require.Len(t, resourceMetrics.ScopeMetrics, 1)
+		require.Len(t, resourceMetrics.ScopeMetrics[0].Metrics, 1)
+
+		assert.Equal(t, policyCountMetricName, resourceMetrics.ScopeMetrics[0].Metrics[0].Name)
+		gauge, ok := resourceMetrics.ScopeMetrics[0].Metrics[0].Data.(metricdata.Gauge[int64])
+		require.True(t, ok)
+
+		counts := map[string]int64{}
+		for _, dataPoint := range gauge.DataPoints {
+			policyServer, _ := dataPoint.Attributes.Value(attribute.Key("policy_server"))
+			status, _ := dataPoint.Attributes.Value(attribute.Key("policy_status"))
+			mode, _ := dataPoint.Attributes.Value(attribute.Key("mode"))
+			module, _ := dataPoint.Attributes.Value(attribute.Key("module"))
+			registry, _ := dataPoint.Attributes.Value(attribute.Key("registry"))
+			assert.Equal(t, "ghcr.io", registry.AsString())
+			counts[policyServer.AsString()+"/"+status.AsString()+"/"+mode.AsString()+"/"+module.AsString()] = dataPoint.Value
+		}
+		return counts
+	}
+
+	podPrivileged := "registry://ghcr.io/kubewarden/tests/pod-privileged:v0.2.5"
+	assert.Equal(t, map[string]int64{
+		"default/active/protect/" + podPrivileged:  2,
+		"default/pending/monitor/" + podPrivileged: 1,
+		// The policy groups are told apart by the modules of their members
+		"default/active/protect/" + podPrivileged + ",registry://ghcr.io/kubewarden/tests/user-group-psp:v0.4.9": 1,
+	}, collectCounts())
+
+	// The gauge reports the current number of policies, not a monotonic sum
+	policies = []policiesv1.Policy{activePolicy}
+	assert.Equal(t, map[string]int64{
+		"default/active/protect/" + podPrivileged: 1,
+	}, collectCounts())
+}
+
+func TestModuleRegistry(t *testing.T) {
+	tests := []struct {
+		name     string
+		module   string
+		expected string
+	}{
+		{"OCI registry", "registry://ghcr.io/kubewarden/tests/pod-privileged:v0.2.5", "ghcr.io"},
+		{"OCI registry with port", "registry://registry.local:5000/pod-privileged:v0.2.5", "registry.local:5000"},
+		{"HTTPS server", "https://example.com/policies/pod-privileged.wasm", "example.com"},
+		{"HTTP server", "http://localhost:8080/pod-privileged.wasm", "localhost:8080"},
+		{"bare reference", "ghcr.io/kubewarden/tests/pod-privileged:v0.2.5", "ghcr.io"},
+		{"bare reference to localhost", "localhost/pod-privileged:v0.2.5", "localhost"},
+		{"bare reference without registry", "kubewarden/pod-privileged:v0.2.5", unknownRegistry},
+		{"bare name", "pod-privileged.wasm", unknownRegistry},
+		{"local file", "file:///policies/pod-privileged.wasm", unknownRegistry},
+		{"empty module", "", unknownRegistry},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, moduleRegistry(test.module))
+		})
+	}
+}
+
+func TestModulesRegistry(t *testing.T) {
+	modules := []string{
+		"registry://ghcr.io/kubewarden/tests/pod-privileged:v0.2.5",
+		"file:///policies/user-group-psp.wasm",
+		"registry://ghcr.io/kubewarden/tests/user-group-psp:v0.4.9",
+	}
+
+	assert.Equal(t, "ghcr.io,unknown", modulesRegistry(modules))
+}
+
+func TestRecordWebhookConfigError(t *testing.T) {
+	reader := metricSDK.NewManualReader()
+	meterProvider := metricSDK.NewMeterProvider(metricSDK.WithReader(reader))
+	otel.SetMeterProvider(meterProvider)
+	t.Cleanup(func() {
+		_ = meterProvider.Shutdown(t.Context())
+	})
+
+	require.NoError(t, RecordWebhookConfigError(t.Context(), "ClusterAdmissionPolicy", "Conflict"))
+	require.NoError(t, RecordWebhookConfigError(t.Context(), "ClusterAdmissionPolicy", "Conflict"))
+	require.NoError(t, RecordWebhookConfigError(t.Context(), "AdmissionPolicy", "Forbidden"))
+
+	var resourceMetrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &resourceMetrics))
+	require.Len(t, resourceMetrics.ScopeMetrics, 1)
+	require.Len(t, resourceMetrics.ScopeMetrics[0].Metrics, 1)
+
+	recordedMetric := resourceMetrics.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, webhookConfigReconcileErrorsMetricName, recordedMetric.Name)
+
+	sum, ok := recordedMetric.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	counts := map[string]int64{}
+	for _, dataPoint := range sum.DataPoints {
+		policyKind, _ := dataPoint.Attributes.Value(attribute.Key("policy_kind"))
+		reason, _ := dataPoint.Attributes.Value(attribute.Key("reason"))
+		counts[policyKind.AsString()+"/"+reason.AsString()] = dataPoint.Value
+	}
+	assert.Equal(t, map[string]int64{
+		"ClusterAdmissionPolicy/Conflict": 2,
+		"AdmissionPolicy/Forbidden":       1,
+	}, counts)
+}
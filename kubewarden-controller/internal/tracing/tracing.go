@@ -0,0 +1,221 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	traceSDK "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const (
+	tracerName = "kubewarden-controller"
+
+	otlpProtocolEnvVar       = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	otlpTracesProtocolEnvVar = "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"
+	otlpProtocolGRPC         = "grpc"
+	otlpProtocolHTTPProtobuf = "http/protobuf"
+
+	// linkTTL is the time after which the link to an admission request is
+	// dropped when the admitted object has not been reconciled.
+	linkTTL = 5 * time.Minute
+	// maxLinksPerObject is the maximum number of admission requests linked
+	// to a reconciliation.
+	maxLinksPerObject = 16
+
+	// PolicyNameKey is the span attribute reporting the unique name of the
+	// reconciled policy.
+	PolicyNameKey = attribute.Key("kubewarden.policy.name")
+	// PolicyServerNameKey is the span attribute reporting the name of the
+	// reconciled policy server, or of the policy server of the reconciled
+	// policy.
+	PolicyServerNameKey = attribute.Key("kubewarden.policy_server.name")
+)
+
+// New initializes the global tracer provider exporting the spans, sampled by
+// the given sampler, with the OTLP exporter.
+func New(sampler traceSDK.Sampler) (func(context.Context) error, error) {
+	ctx := context.Background()
+
+	// All the Otel exporter configuration is set by environment variables.
+	exporter, err := newExporter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot start trace exporter: %w", err)
+	}
+	tracerProvider := traceSDK.NewTracerProvider(
+		traceSDK.WithBatcher(exporter),
+		traceSDK.WithSampler(sampler),
+	)
+
+	otel.SetTracerProvider(tracerProvider)
+
+	return tracerProvider.Shutdown, nil
+}
+
+// exporterProtocol returns the OTLP protocol used to export the spans. Like
+// the OpenTelemetry SDKs, the OTEL_EXPORTER_OTLP_TRACES_PROTOCOL environment
+// variable takes precedence over OTEL_EXPORTER_OTLP_PROTOCOL. The gRPC protocol
+// is used when none of them is set.
+func exporterProtocol() (string, error) {
+	protocol := os.Getenv(otlpTracesProtocolEnvVar)
+	if protocol == "" {
+		protocol = os.Getenv(otlpProtocolEnvVar)
+	}
+	if protocol == "" {
+		return otlpProtocolGRPC, nil
+	}
+
+	switch protocol {
+	case otlpProtocolGRPC, otlpProtocolHTTPProtobuf:
+		return protocol, nil
+	default:
+		return "", fmt.Errorf("unknown OTLP protocol %q, supported values are %q and %q", protocol, otlpProtocolGRPC, otlpProtocolHTTPProtobuf)
+	}
+}
+
+func newExporter(ctx context.Context) (traceSDK.SpanExporter, error) {
+	protocol, err := exporterProtocol()
+	if err != nil {
+		return nil, err
+	}
+
+	if protocol == otlpProtocolHTTPProtobuf {
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create the OTLP HTTP exporter: %w", err)
+		}
+		return exporter, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the OTLP gRPC exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+// Tracer returns the tracer of the controller, provided by the global tracer
+// provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// webhookServer is a webhook.Server extracting the W3C trace context of the
+// admission requests, sent by the Kubernetes API server when its tracing is
+// enabled, and starting a span for each of them.
+type webhookServer struct {
+	webhook.Server
+}
+
+// NewWebhookServer returns a webhook.Server tracing the admission requests
+// served by the given server.
+func NewWebhookServer(server webhook.Server) webhook.Server {
+	return webhookServer{server}
+}
+
+func (s webhookServer) Register(path string, hook http.Handler) {
+	s.Server.Register(path, otelhttp.NewHandler(hook, path, otelhttp.WithPropagators(propagation.TraceContext{})))
+}
+
+// admissionLinks holds, for each object, the span contexts of the traced
+// admission requests changing it, until its next reconciliation. The links are
+// kept in memory only, they are lost when the controller restarts or when the
+// object is reconciled by another instance of the controller.
+//
+//nolint:gochecknoglobals // Shared by the webhooks and the reconcilers, like the global tracer provider
+var admissionLinks = &linkRegistry{links: map[string][]pendingLink{}}
+
+// pendingLink is the span context of a traced admission request, waiting for
+// the reconciliation of the admitted object.
+type pendingLink struct {
+	spanContext trace.SpanContext
+	recordedAt  time.Time
+}
+
+// linkRegistry stores the pending links of the objects. The links not
+// consumed within linkTTL, e.g. the ones of the admission requests rejected
+// by a validating webhook, are dropped.
+type linkRegistry struct {
+	mu    sync.Mutex
+	links map[string][]pendingLink
+}
+
+func (r *linkRegistry) add(key string, spanContext trace.SpanContext, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, links := range r.links {
+		r.links[k] = slices.DeleteFunc(links, func(link pendingLink) bool {
+			return now.Sub(link.recordedAt) > linkTTL
+		})
+		if len(r.links[k]) == 0 {
+			delete(r.links, k)
+		}
+	}
+
+	if len(r.links[key]) < maxLinksPerObject {
+		r.links[key] = append(r.links[key], pendingLink{spanContext: spanContext, recordedAt: now})
+	}
+}
+
+func (r *linkRegistry) take(key string) []trace.Link {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	links := make([]trace.Link, 0, len(r.links[key]))
+	for _, link := range r.links[key] {
+		links = append(links, trace.Link{SpanContext: link.spanContext})
+	}
+	delete(r.links, key)
+
+	return links
+}
+
+// objectKey identifies an object, both when it is admitted and when it is
+// reconciled.
+func objectKey(obj metav1.Object) string {
+	return fmt.Sprintf("%T/%s/%s", obj, obj.GetNamespace(), obj.GetName())
+}
+
+// RecordAdmission records the span context of the given admission request
+// context, the next reconciliation of the admitted object is linked to it.
+// Nothing is recorded when the request is not traced or when the name of the
+// object is not known yet.
+func RecordAdmission(ctx context.Context, obj metav1.Object) {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() || obj.GetName() == "" {
+		return
+	}
+
+	admissionLinks.add(objectKey(obj), spanContext, time.Now())
+}
+
+// StartReconcileSpan starts the span of the reconciliation of the given
+// object. The span is the root of a new trace, linked to the traced admission
+// requests changing the object since its last reconciliation, if any. A
+// non-recording span is started when the tracer is nil.
+func StartReconcileSpan(ctx context.Context, tracer trace.Tracer, obj metav1.Object, spanName string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	if tracer == nil {
+		tracer = noop.NewTracerProvider().Tracer(tracerName)
+	}
+
+	return tracer.Start(ctx, spanName,
+		trace.WithNewRoot(),
+		trace.WithLinks(admissionLinks.take(objectKey(obj))...),
+		trace.WithAttributes(attributes...),
+	)
+}
@@ -0,0 +1,160 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	traceSDK "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const (
+	traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+)
+
+func TestExporterProtocol(t *testing.T) {
+	tests := []struct {
+		name             string
+		protocol         string
+		tracesProtocol   string
+		expectedProtocol string
+		error            string
+	}{
+		{
+			name:             "default protocol",
+			protocol:         "",
+			tracesProtocol:   "",
+			expectedProtocol: "grpc",
+			error:            "",
+		},
+		{
+			name:             "HTTP protocol",
+			protocol:         "http/protobuf",
+			tracesProtocol:   "",
+			expectedProtocol: "http/protobuf",
+			error:            "",
+		},
+		{
+			name:             "traces protocol takes precedence",
+			protocol:         "http/protobuf",
+			tracesProtocol:   "grpc",
+			expectedProtocol: "grpc",
+			error:            "",
+		},
+		{
+			name:             "unknown protocol",
+			protocol:         "",
+			tracesProtocol:   "http/json",
+			expectedProtocol: "",
+			error:            `unknown OTLP protocol "http/json", supported values are "grpc" and "http/protobuf"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(otlpProtocolEnvVar, test.protocol)
+			t.Setenv(otlpTracesProtocolEnvVar, test.tracesProtocol)
+
+			protocol, err := exporterProtocol()
+			if test.error != "" {
+				require.EqualError(t, err, test.error)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedProtocol, protocol)
+		})
+	}
+}
+
+// handlersRecorder is a webhook.Server recording the registered handlers.
+type handlersRecorder struct {
+	webhook.Server
+	handlers map[string]http.Handler
+}
+
+func (r *handlersRecorder) Register(path string, hook http.Handler) {
+	r.handlers[path] = hook
+}
+
+func TestWebhookServerExtractsTraceContext(t *testing.T) {
+	recorder := &handlersRecorder{handlers: map[string]http.Handler{}}
+	server := NewWebhookServer(recorder)
+
+	var spanContext trace.SpanContext
+	server.Register("/mutate", http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		spanContext = trace.SpanContextFromContext(r.Context())
+	}))
+
+	request := httptest.NewRequest(http.MethodPost, "/mutate", nil)
+	request.Header.Set("traceparent", traceParent)
+	recorder.handlers["/mutate"].ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Equal(t, traceID, spanContext.TraceID().String())
+}
+
+func TestReconcileSpanIsLinkedToTheAdmissionRequests(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracer := traceSDK.NewTracerProvider(traceSDK.WithSpanProcessor(spanRecorder)).Tracer("test")
+
+	obj := &metav1.ObjectMeta{Name: "privileged-pods", Annotations: map[string]string{"app": "kubewarden"}}
+	admissionCtx, admissionSpan := tracer.Start(context.Background(), "admission")
+	RecordAdmission(admissionCtx, obj)
+	admissionSpan.End()
+
+	assert.Equal(t, map[string]string{"app": "kubewarden"}, obj.GetAnnotations())
+
+	_, span := StartReconcileSpan(context.Background(), tracer, obj, "reconcile", PolicyNameKey.String("privileged-pods"))
+	span.End()
+
+	require.Len(t, spanRecorder.Ended(), 2)
+	reconcileSpan := spanRecorder.Ended()[1]
+	assert.NotEqual(t, admissionSpan.SpanContext().TraceID(), reconcileSpan.SpanContext().TraceID())
+	assert.False(t, reconcileSpan.Parent().IsValid())
+	require.Len(t, reconcileSpan.Links(), 1)
+	assert.Equal(t, admissionSpan.SpanContext(), reconcileSpan.Links()[0].SpanContext)
+	assert.Contains(t, reconcileSpan.Attributes(), PolicyNameKey.String("privileged-pods"))
+
+	// The links are consumed by the first reconciliation
+	_, span = StartReconcileSpan(context.Background(), tracer, obj, "reconcile")
+	span.End()
+	assert.Empty(t, spanRecorder.Ended()[2].Links())
+}
+
+func TestRecordAdmissionWithoutTraceContext(t *testing.T) {
+	obj := &metav1.ObjectMeta{Name: "untraced"}
+
+	RecordAdmission(context.Background(), obj)
+
+	assert.Empty(t, admissionLinks.take(objectKey(obj)))
+}
+
+func TestLinkRegistryDropsExpiredLinks(t *testing.T) {
+	registry := &linkRegistry{links: map[string][]pendingLink{}}
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{1},
+	})
+	now := time.Now()
+
+	registry.add("rejected", spanContext, now)
+	registry.add("admitted", spanContext, now.Add(linkTTL+time.Second))
+
+	assert.Empty(t, registry.take("rejected"))
+	assert.Len(t, registry.take("admitted"), 1)
+}
+
+func TestStartReconcileSpanWithoutTracer(t *testing.T) {
+	_, span := StartReconcileSpan(context.Background(), nil, &metav1.ObjectMeta{}, "reconcile")
+	defer span.End()
+
+	assert.False(t, span.IsRecording())
+}